@@ -2,8 +2,22 @@ module github.com/iden3/go-rapidsnark/cmd/proof
 
 go 1.18
 
-require github.com/iden3/go-rapidsnark/prover v0.0.2
+require (
+	github.com/iden3/go-rapidsnark/prover v0.0.11
+	github.com/iden3/go-rapidsnark/verifier v0.0.6
+	github.com/iden3/go-rapidsnark/zkey v0.0.1
+)
 
-require github.com/iden3/go-rapidsnark/types v0.0.3 // indirect
+require (
+	github.com/iden3/go-iden3-crypto v0.0.15 // indirect
+	github.com/iden3/go-rapidsnark/types v0.0.4 // indirect
+	golang.org/x/crypto v0.7.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
+)
 
-replace github.com/iden3/go-rapidsnark/prover => ../../prover
+replace (
+	github.com/iden3/go-rapidsnark/prover => ../../prover
+	github.com/iden3/go-rapidsnark/types => ../../types
+	github.com/iden3/go-rapidsnark/verifier => ../../verifier
+	github.com/iden3/go-rapidsnark/zkey => ../../zkey
+)
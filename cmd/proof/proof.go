@@ -14,6 +14,17 @@ var publicInputsFName = flag.String("public", "public.json",
 	"public inputs file")
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export-vk":
+			exportVK(os.Args[2:])
+			return
+		case "export-solidity":
+			exportSolidity(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
 	if *zkeyFName == "" {
@@ -33,19 +44,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	zkeyBytes, err := os.ReadFile(*zkeyFName)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "failed to read zkey file: %v\n", err)
-		os.Exit(1)
-	}
-
 	wtnsBytes, err := os.ReadFile(*wtnsFName)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "failed to read witness file: %v\n", err)
 		os.Exit(1)
 	}
 
-	proof, publicInputs, err := prover.Groth16ProverRaw(zkeyBytes, wtnsBytes)
+	proof, publicInputs, err := prover.Groth16ProverFileRaw(*zkeyFName,
+		wtnsBytes)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iden3/go-rapidsnark/verifier"
+)
+
+// exportSolidity implements the export-solidity subcommand: write the
+// Solidity verifier contract of a verification key in the snarkjs JSON format.
+func exportSolidity(args []string) {
+	fs := flag.NewFlagSet("export-solidity", flag.ExitOnError)
+	vkFName := fs.String("vk", "verification_key.json",
+		"verification key file")
+	solFName := fs.String("sol", "verifier.sol", "Solidity verifier file")
+	pragma := fs.String("pragma", ">=0.7.0 <0.9.0",
+		"version of the Solidity pragma")
+	contractName := fs.String("name", "Groth16Verifier", "contract name")
+	_ = fs.Parse(args)
+
+	if *vkFName == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "verification key file is required\n")
+		os.Exit(1)
+	}
+	if *solFName == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "Solidity verifier file is required\n")
+		os.Exit(1)
+	}
+
+	vk, err := os.ReadFile(*vkFName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"failed to read verification key file: %v\n", err)
+		os.Exit(1)
+	}
+
+	sol, err := verifier.ExportSolidityVerifier(vk,
+		verifier.WithPragma(*pragma), verifier.WithContractName(*contractName))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"failed to export Solidity verifier: %v\n", err)
+		os.Exit(1)
+	}
+
+	err = os.WriteFile(*solFName, sol, 0644)
+	if err != nil {
+		panic(err)
+	}
+}
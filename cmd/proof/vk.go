@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iden3/go-rapidsnark/zkey"
+)
+
+// exportVK implements the export-vk subcommand: write the verification key
+// of a zkey in the snarkjs JSON format.
+func exportVK(args []string) {
+	fs := flag.NewFlagSet("export-vk", flag.ExitOnError)
+	zkeyFName := fs.String("zkey", "", "circuit zkey file")
+	vkFName := fs.String("vk", "verification_key.json",
+		"verification key file")
+	_ = fs.Parse(args)
+
+	if *zkeyFName == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "zkey file is required\n")
+		os.Exit(1)
+	}
+	if *vkFName == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "verification key file is required\n")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(*zkeyFName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to open zkey file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		panic(err)
+	}
+
+	zk, err := zkey.Read(f, st.Size())
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read zkey file: %v\n", err)
+		os.Exit(1)
+	}
+	vk, err := zk.ExportVerificationKey()
+	if err != nil {
+		panic(err)
+	}
+
+	err = os.WriteFile(*vkFName, vk, 0644)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,54 @@
+package prover
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// readBinFile splits an iden3 binary container (zkey, wtns, ...) into its
+// sections. Section data is returned as sub-slices of data, indexed by
+// section id. A section id may appear only once.
+func readBinFile(data []byte, magic string,
+	maxVersion uint32) (map[uint32][]byte, error) {
+
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%v file is too short", magic)
+	}
+	if string(data[:4]) != magic {
+		return nil, fmt.Errorf("invalid %v file format", magic)
+	}
+	version := binary.LittleEndian.Uint32(data[4:8])
+	if version == 0 || version > maxVersion {
+		return nil, fmt.Errorf("unsupported %v file version: %v", magic,
+			version)
+	}
+	nSections := binary.LittleEndian.Uint32(data[8:12])
+
+	// every section has a 12 bytes header, do not trust nSections for the
+	// size of the map
+	if uint64(nSections) > uint64(len(data)-12)/12 {
+		return nil, fmt.Errorf("invalid %v file: truncated section header",
+			magic)
+	}
+	sections := make(map[uint32][]byte, nSections)
+	pos := uint64(12)
+	for i := uint32(0); i < nSections; i++ {
+		if uint64(len(data))-pos < 12 {
+			return nil, fmt.Errorf("invalid %v file: truncated section header",
+				magic)
+		}
+		id := binary.LittleEndian.Uint32(data[pos:])
+		size := binary.LittleEndian.Uint64(data[pos+4:])
+		pos += 12
+		if uint64(len(data))-pos < size {
+			return nil, fmt.Errorf("invalid %v file: section %v is truncated",
+				magic, id)
+		}
+		if _, ok := sections[id]; ok {
+			return nil, fmt.Errorf("duplicate %v section: %v", magic, id)
+		}
+		sections[id] = data[pos : pos+size]
+		pos += size
+	}
+	return sections, nil
+}
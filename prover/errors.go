@@ -0,0 +1,99 @@
+package prover
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is an error code returned by rapidsnark, see prover.h.
+type ErrorCode int
+
+const (
+	// ErrorCodeProverError is PROVER_ERROR: the proof could not be generated,
+	// e.g. because the zkey or the witness are malformed.
+	ErrorCodeProverError ErrorCode = 1
+	// ErrorCodeShortBuffer is PROVER_ERROR_SHORT_BUFFER: the output buffers
+	// are too small.
+	ErrorCodeShortBuffer ErrorCode = 2
+	// ErrorCodeInvalidWitnessLength is PROVER_INVALID_WITNESS_LENGTH: the
+	// number of signals in the witness does not match the zkey.
+	ErrorCodeInvalidWitnessLength ErrorCode = 3
+)
+
+var (
+	// ErrProverClosed is returned when a Prover is used after Close was
+	// called.
+	ErrProverClosed = errors.New("prover is closed")
+	// ErrZkeyEmpty is returned when the zkey has no data.
+	ErrZkeyEmpty = errors.New("zkey is empty")
+	// ErrZkeyPathEmpty is returned when no zkey file path is given.
+	ErrZkeyPathEmpty = errors.New("zkey path is empty")
+	// ErrWitnessEmpty is returned when the witness has no data.
+	ErrWitnessEmpty = errors.New("witness is empty")
+	// ErrProofTooLarge is returned when the proof does not fit into the
+	// output buffer.
+	ErrProofTooLarge = errors.New("proof is too large")
+	// ErrPublicInputsTooLarge is returned when the public inputs do not fit
+	// into MaxBufferSize.
+	ErrPublicInputsTooLarge = errors.New("public inputs is too large")
+	// ErrInvalidZkey is returned when the zkey is not a valid Groth16 zkey.
+	ErrInvalidZkey = errors.New("invalid zkey")
+	// ErrInvalidWitness is returned when the witness is not a valid wtns
+	// file or has values out of the field.
+	ErrInvalidWitness = errors.New("invalid witness")
+
+	// ErrProverFailed matches an *Error with ErrorCodeProverError.
+	ErrProverFailed = errors.New("prover failed")
+	// ErrInvalidWitnessLength matches an *Error with
+	// ErrorCodeInvalidWitnessLength and witnesses with a number of signals
+	// different from the zkey.
+	ErrInvalidWitnessLength = errors.New("invalid witness length")
+
+	// ErrWitnessMismatch is returned when the witness was not calculated
+	// for the circuit of the zkey: the field or the number of signals
+	// differ. It is detected before the witness is passed to the prover.
+	ErrWitnessMismatch = errors.New("witness does not match zkey")
+)
+
+// Error is returned when the prover fails with one of the rapidsnark
+// error codes. Use errors.Is with ErrProverFailed or ErrInvalidWitnessLength
+// to check the kind of failure, or errors.As to get the code and message.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("error generating proof. Code: %v. Message: %v",
+		int(e.Code), e.Message)
+}
+
+// Is reports whether the error code corresponds to the target sentinel
+// error.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrProverFailed:
+		return e.Code == ErrorCodeProverError
+	case ErrInvalidWitnessLength:
+		return e.Code == ErrorCodeInvalidWitnessLength
+	default:
+		return false
+	}
+}
+
+// witnessMismatchError describes how the witness differs from the zkey.
+type witnessMismatchError struct {
+	msg           string
+	invalidLength bool
+}
+
+func (e *witnessMismatchError) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is ErrWitnessMismatch, or
+// ErrInvalidWitnessLength for a wrong number of signals.
+func (e *witnessMismatchError) Is(target error) bool {
+	return target == ErrWitnessMismatch ||
+		(e.invalidLength && target == ErrInvalidWitnessLength)
+}
@@ -0,0 +1,93 @@
+package prover
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// frDomain holds the roots of unity needed to evaluate the Groth16
+// polynomials over a domain of size n and over its odd coset.
+type frDomain struct {
+	r     *big.Int
+	n     int
+	roots []*big.Int // ω^i for i in [0, n)
+	nInv  *big.Int
+	shift *big.Int // ω₂ₙ, the 2n-th root of unity used to move to the coset
+}
+
+// rootOfUnity returns the n-th root of unity of the scalar field used by
+// snarkjs and rapidsnark: 5^((r-1)/n).
+func rootOfUnity(r *big.Int, n int) *big.Int {
+	e := new(big.Int).Sub(r, big.NewInt(1))
+	e.Div(e, big.NewInt(int64(n)))
+	return new(big.Int).Exp(big.NewInt(5), e, r)
+}
+
+func newFrDomain(r *big.Int, n int) *frDomain {
+	d := &frDomain{r: r, n: n}
+	w := rootOfUnity(r, n)
+	d.roots = make([]*big.Int, n)
+	d.roots[0] = big.NewInt(1)
+	for i := 1; i < n; i++ {
+		d.roots[i] = new(big.Int).Mul(d.roots[i-1], w)
+		d.roots[i].Mod(d.roots[i], r)
+	}
+	d.nInv = new(big.Int).ModInverse(big.NewInt(int64(n)), r)
+	d.shift = rootOfUnity(r, 2*n)
+	return d
+}
+
+// fft evaluates in place the polynomial with coefficients a over the domain.
+// If inverse is set, it interpolates instead.
+func (d *frDomain) fft(a []*big.Int, inverse bool) {
+	n := d.n
+	logN := bits.TrailingZeros(uint(n))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse(uint(i)) >> (bits.UintSize - logN))
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	t := new(big.Int)
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		step := n / size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				idx := k * step
+				if inverse && idx != 0 {
+					idx = n - idx
+				}
+				t.Mul(a[start+k+half], d.roots[idx])
+				u := a[start+k]
+				a[start+k+half] = new(big.Int).Sub(u, t)
+				a[start+k+half].Mod(a[start+k+half], d.r)
+				a[start+k] = new(big.Int).Add(u, t)
+				a[start+k].Mod(a[start+k], d.r)
+			}
+		}
+	}
+
+	if inverse {
+		for i := range a {
+			a[i].Mul(a[i], d.nInv)
+			a[i].Mod(a[i], d.r)
+		}
+	}
+}
+
+// toOddCoset takes the evaluations of a polynomial over the domain and
+// returns its evaluations over the coset ω₂ₙ·<ω>, where Z(x) = xⁿ - 1 is
+// constant and non-zero.
+func (d *frDomain) toOddCoset(evals []*big.Int) {
+	d.fft(evals, true)
+	shift := big.NewInt(1)
+	for i := range evals {
+		evals[i].Mul(evals[i], shift)
+		evals[i].Mod(evals[i], d.r)
+		shift.Mul(shift, d.shift)
+		shift.Mod(shift, d.r)
+	}
+	d.fft(evals, false)
+}
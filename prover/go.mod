@@ -2,4 +2,24 @@ module github.com/iden3/go-rapidsnark/prover
 
 go 1.18
 
-require github.com/iden3/go-rapidsnark/types v0.0.3
+require (
+	github.com/iden3/go-rapidsnark/types v0.0.4
+	github.com/iden3/go-rapidsnark/verifier v0.0.6
+	github.com/iden3/go-rapidsnark/zkey v0.0.1
+	github.com/stretchr/testify v1.8.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/iden3/go-iden3-crypto v0.0.15 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.7.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/iden3/go-rapidsnark/types => ../types
+	github.com/iden3/go-rapidsnark/verifier => ../verifier
+	github.com/iden3/go-rapidsnark/zkey => ../zkey
+)
@@ -0,0 +1,215 @@
+package prover
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// ctxCheckInterval is the number of points processed between checks of the
+// context during multi-exponentiations.
+const ctxCheckInterval = 256
+
+// header returns the parameters a witness must match to be used with pk.
+func (pk *provingKey) header() zkeyHeader {
+	return zkeyHeader{n8r: n8, r: bn254R, nVars: pk.nVars}
+}
+
+// prove computes a Groth16 proof for the witness values. Randomness for
+// the proof blinding factors is read from rnd. The computation is stopped
+// when ctx is done.
+func (pk *provingKey) prove(ctx context.Context, w []*big.Int,
+	rnd io.Reader) (*types.ZKProof, error) {
+
+	if len(w) != pk.nVars {
+		return nil, &Error{
+			Code: ErrorCodeInvalidWitnessLength,
+			Message: fmt.Sprintf("invalid witness length: %v, expected %v",
+				len(w), pk.nVars)}
+	}
+	for _, v := range w {
+		if v.Cmp(bn254R) >= 0 {
+			return nil, fmt.Errorf("%w: witness value is not in the field",
+				ErrInvalidWitness)
+		}
+	}
+
+	h, err := pk.quotientEvals(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rand.Int(rnd, bn254R)
+	if err != nil {
+		return nil, err
+	}
+	s, err := rand.Int(rnd, bn254R)
+	if err != nil {
+		return nil, err
+	}
+
+	// A = α + Σ wᵢ·Aᵢ + r·δ
+	piA, err := multiExpG1(ctx, pk.a, w)
+	if err != nil {
+		return nil, err
+	}
+	piA.Add(piA, pk.alpha1)
+	piA.Add(piA, new(bn256.G1).ScalarMult(pk.delta1, r))
+
+	// B = β + Σ wᵢ·Bᵢ + s·δ
+	piB, err := multiExpG2(ctx, pk.b2, w)
+	if err != nil {
+		return nil, err
+	}
+	piB.Add(piB, pk.beta2)
+	piB.Add(piB, new(bn256.G2).ScalarMult(pk.delta2, s))
+
+	piB1, err := multiExpG1(ctx, pk.b1, w)
+	if err != nil {
+		return nil, err
+	}
+	piB1.Add(piB1, pk.beta1)
+	piB1.Add(piB1, new(bn256.G1).ScalarMult(pk.delta1, s))
+
+	// C = Σ wᵢ·Cᵢ + Σ hᵢ·Hᵢ + s·A + r·B₁ - r·s·δ
+	piC, err := multiExpG1(ctx, pk.c, w[pk.nPublic+1:])
+	if err != nil {
+		return nil, err
+	}
+	piH, err := multiExpG1(ctx, pk.h, h)
+	if err != nil {
+		return nil, err
+	}
+	piC.Add(piC, piH)
+	piC.Add(piC, new(bn256.G1).ScalarMult(piA, s))
+	piC.Add(piC, new(bn256.G1).ScalarMult(piB1, r))
+	rs := new(big.Int).Mul(r, s)
+	rs.Mod(rs, bn254R)
+	piC.Add(piC, new(bn256.G1).Neg(new(bn256.G1).ScalarMult(pk.delta1, rs)))
+
+	pubSignals := make([]string, pk.nPublic)
+	for i := range pubSignals {
+		pubSignals[i] = w[i+1].String()
+	}
+
+	return &types.ZKProof{
+		Proof: &types.ProofData{
+			A:        g1ToStrings(piA),
+			B:        g2ToStrings(piB),
+			C:        g1ToStrings(piC),
+			Protocol: "groth16",
+		},
+		PubSignals: pubSignals,
+	}, nil
+}
+
+// quotientEvals evaluates A·B - C over the odd coset of the domain. These
+// are the scalars for the H points of the zkey.
+func (pk *provingKey) quotientEvals(ctx context.Context,
+	w []*big.Int) ([]*big.Int, error) {
+	n := pk.domainSize
+	a := make([]*big.Int, n)
+	b := make([]*big.Int, n)
+	c := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		a[i] = new(big.Int)
+		b[i] = new(big.Int)
+	}
+
+	t := new(big.Int)
+	for _, coef := range pk.coefs {
+		dst := a
+		if coef.Matrix == 1 {
+			dst = b
+		}
+		t.Mul(coef.Value, w[coef.Signal])
+		dst[coef.Constraint].Add(dst[coef.Constraint], t)
+		dst[coef.Constraint].Mod(dst[coef.Constraint], bn254R)
+	}
+	for i := 0; i < n; i++ {
+		c[i] = new(big.Int).Mul(a[i], b[i])
+		c[i].Mod(c[i], bn254R)
+	}
+
+	for _, evals := range [][]*big.Int{a, b, c} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pk.domain.toOddCoset(evals)
+	}
+
+	for i := 0; i < n; i++ {
+		a[i].Mul(a[i], b[i])
+		a[i].Sub(a[i], c[i])
+		a[i].Mod(a[i], bn254R)
+	}
+	return a, nil
+}
+
+func multiExpG1(ctx context.Context, points []*bn256.G1,
+	scalars []*big.Int) (*bn256.G1, error) {
+
+	acc := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	for i, p := range points {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if scalars[i].Sign() == 0 {
+			continue
+		}
+		acc.Add(acc, new(bn256.G1).ScalarMult(p, scalars[i]))
+	}
+	return acc, nil
+}
+
+func multiExpG2(ctx context.Context, points []*bn256.G2,
+	scalars []*big.Int) (*bn256.G2, error) {
+
+	acc := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	for i, p := range points {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if scalars[i].Sign() == 0 {
+			continue
+		}
+		acc.Add(acc, new(bn256.G2).ScalarMult(p, scalars[i]))
+	}
+	return acc, nil
+}
+
+func g1ToStrings(p *bn256.G1) []string {
+	m := p.Marshal()
+	x := new(big.Int).SetBytes(m[:n8])
+	y := new(big.Int).SetBytes(m[n8:])
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return []string{"0", "1", "0"}
+	}
+	return []string{x.String(), y.String(), "1"}
+}
+
+func g2ToStrings(p *bn256.G2) [][]string {
+	m := p.Marshal()
+	xc1 := new(big.Int).SetBytes(m[:n8])
+	xc0 := new(big.Int).SetBytes(m[n8 : 2*n8])
+	yc1 := new(big.Int).SetBytes(m[2*n8 : 3*n8])
+	yc0 := new(big.Int).SetBytes(m[3*n8:])
+	if xc0.Sign() == 0 && xc1.Sign() == 0 && yc0.Sign() == 0 &&
+		yc1.Sign() == 0 {
+		return [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return [][]string{
+		{xc0.String(), xc1.String()},
+		{yc0.String(), yc1.String()},
+		{"1", "0"},
+	}
+}
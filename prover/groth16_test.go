@@ -0,0 +1,345 @@
+package prover
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestData(t testing.TB) (zkey, wtns, vk []byte) {
+	var err error
+	zkey, err = os.ReadFile("testdata/circuit.zkey")
+	require.NoError(t, err)
+	wtns, err = os.ReadFile("testdata/circuit.wtns")
+	require.NoError(t, err)
+	vk, err = os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	return zkey, wtns, vk
+}
+
+func groth16ProveGo(zkey, wtns []byte) (*types.ZKProof, error) {
+	pk, err := parseProvingKey(zkey)
+	if err != nil {
+		return nil, err
+	}
+	w, err := readWitness(wtns, pk.header())
+	if err != nil {
+		return nil, err
+	}
+	return pk.prove(context.Background(), w, rand.Reader)
+}
+
+func TestGroth16ProveGo(t *testing.T) {
+	zkey, wtns, vk := readTestData(t)
+
+	proof, err := groth16ProveGo(zkey, wtns)
+	require.NoError(t, err)
+	require.Equal(t, "groth16", proof.Proof.Protocol)
+	// the same JSON as rapidsnark, which does not set the curve
+	proofJSON, err := json.Marshal(proof.Proof)
+	require.NoError(t, err)
+	require.NotContains(t, string(proofJSON), "curve")
+	require.Equal(t, []string{"396", "3"}, proof.PubSignals)
+
+	err = verifier.VerifyGroth16(*proof, vk)
+	require.NoError(t, err)
+}
+
+func TestGroth16Prover(t *testing.T) {
+	zkey, wtns, vk := readTestData(t)
+
+	proof, err := Groth16Prover(zkey, wtns)
+	require.NoError(t, err)
+	err = verifier.VerifyGroth16(*proof, vk)
+	require.NoError(t, err)
+
+	proofJSON, publicJSON, err := Groth16ProverRaw(zkey, wtns)
+	require.NoError(t, err)
+	var rawProof types.ZKProof
+	require.NoError(t, json.Unmarshal([]byte(proofJSON), &rawProof.Proof))
+	require.NoError(t, json.Unmarshal([]byte(publicJSON), &rawProof.PubSignals))
+	err = verifier.VerifyGroth16(rawProof, vk)
+	require.NoError(t, err)
+}
+
+func TestGroth16ProveGoErrors(t *testing.T) {
+	zkey, wtns, _ := readTestData(t)
+
+	_, err := Groth16Prover(nil, wtns)
+	require.EqualError(t, err, "zkey is empty")
+
+	_, err = Groth16Prover(zkey, nil)
+	require.EqualError(t, err, "witness is empty")
+
+	_, err = groth16ProveGo(wtns, wtns)
+	require.EqualError(t, err, "invalid zkey file format")
+
+	// drop the last signal from the witness: update the witness count in
+	// the header section and the size of the data section
+	short := append([]byte{}, wtns[:len(wtns)-32]...)
+	binary.LittleEndian.PutUint32(short[60:], 5)
+	binary.LittleEndian.PutUint64(short[68:], 5*32)
+	_, err = groth16ProveGo(zkey, short)
+	require.EqualError(t, err, "witness has 5 signals, zkey expects 6")
+
+	pk, err := parseProvingKey(zkey)
+	require.NoError(t, err)
+	w, err := readWitness(wtns, pk.header())
+	require.NoError(t, err)
+	_, err = pk.prove(context.Background(), w[:5], rand.Reader)
+	require.EqualError(t, err, "error generating proof. Code: 3. "+
+		"Message: invalid witness length: 5, expected 6")
+}
+
+func TestProverErrors(t *testing.T) {
+	zkey, wtns, _ := readTestData(t)
+
+	_, err := Groth16Prover(nil, wtns)
+	require.ErrorIs(t, err, ErrZkeyEmpty)
+
+	_, err = Groth16Prover(zkey, nil)
+	require.ErrorIs(t, err, ErrWitnessEmpty)
+
+	_, err = Groth16ProverFile("", wtns)
+	require.ErrorIs(t, err, ErrZkeyPathEmpty)
+
+	_, err = Groth16Prover(wtns, wtns)
+	require.ErrorIs(t, err, ErrInvalidZkey)
+	require.NotErrorIs(t, err, ErrProverFailed)
+	var proverErr *Error
+	require.False(t, errors.As(err, &proverErr))
+
+	_, err = NewProver(wtns)
+	require.ErrorIs(t, err, ErrInvalidZkey)
+
+	short := append([]byte{}, wtns[:len(wtns)-32]...)
+	binary.LittleEndian.PutUint32(short[60:], 5)
+	binary.LittleEndian.PutUint64(short[68:], 5*32)
+	_, err = Groth16Prover(zkey, short)
+	require.ErrorIs(t, err, ErrInvalidWitnessLength)
+	require.NotErrorIs(t, err, ErrProverFailed)
+
+	// a header with 2^32-1 sections and no data
+	_, err = Groth16Prover(zkey,
+		[]byte("wtns\x02\x00\x00\x00\xff\xff\xff\xff"))
+	require.ErrorIs(t, err, ErrInvalidWitness)
+	require.EqualError(t, err,
+		"invalid witness: invalid wtns file: truncated section header")
+
+	_, err = Groth16Prover(zkey, wtns[:len(wtns)-1])
+	require.ErrorIs(t, err, ErrInvalidWitness)
+	require.NotErrorIs(t, err, ErrProverFailed)
+	require.EqualError(t, err,
+		"invalid witness: invalid wtns file: section 2 is truncated")
+}
+
+// buildWitness encodes a wtns file with n8 bytes field elements.
+func buildWitness(n8 int, prime *big.Int, values []*big.Int) []byte {
+	le := func(v *big.Int) []byte {
+		b := v.FillBytes(make([]byte, n8))
+		for i := 0; i < n8/2; i++ {
+			b[i], b[n8-1-i] = b[n8-1-i], b[i]
+		}
+		return b
+	}
+
+	var hdr bytes.Buffer
+	_ = binary.Write(&hdr, binary.LittleEndian, uint32(n8))
+	hdr.Write(le(prime))
+	_ = binary.Write(&hdr, binary.LittleEndian, uint32(len(values)))
+	var data bytes.Buffer
+	for _, v := range values {
+		data.Write(le(v))
+	}
+
+	var wtns bytes.Buffer
+	wtns.WriteString("wtns")
+	_ = binary.Write(&wtns, binary.LittleEndian, []uint32{2, 2})
+	for id, sec := range [][]byte{hdr.Bytes(), data.Bytes()} {
+		_ = binary.Write(&wtns, binary.LittleEndian, uint32(id+1))
+		_ = binary.Write(&wtns, binary.LittleEndian, uint64(len(sec)))
+		wtns.Write(sec)
+	}
+	return wtns.Bytes()
+}
+
+func TestWitnessMismatch(t *testing.T) {
+	zkey, wtns, _ := readTestData(t)
+
+	pk, err := parseProvingKey(zkey)
+	require.NoError(t, err)
+	w, err := readWitness(wtns, pk.header())
+	require.NoError(t, err)
+	require.Equal(t, wtns, buildWitness(n8, bn254R, w))
+
+	bls12381R, _ := new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+	testCases := []struct {
+		name  string
+		wtns  []byte
+		err   string
+		isLen bool
+	}{
+		{
+			name: "prime",
+			wtns: buildWitness(n8, bls12381R, w),
+			err:  "witness prime differs from zkey r",
+		},
+		{
+			name: "n8",
+			wtns: buildWitness(48, bn254R, w),
+			err:  "witness field element size is 48 bytes, zkey expects 32",
+		},
+		{
+			name:  "more signals",
+			wtns:  buildWitness(n8, bn254R, append(w, big.NewInt(1))),
+			err:   "witness has 7 signals, zkey expects 6",
+			isLen: true,
+		},
+		{
+			name:  "less signals",
+			wtns:  buildWitness(n8, bn254R, w[:4]),
+			err:   "witness has 4 signals, zkey expects 6",
+			isLen: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Groth16Prover(zkey, tc.wtns)
+			require.EqualError(t, err, tc.err)
+			require.ErrorIs(t, err, ErrWitnessMismatch)
+			if tc.isLen {
+				require.ErrorIs(t, err, ErrInvalidWitnessLength)
+			} else {
+				require.NotErrorIs(t, err, ErrInvalidWitnessLength)
+			}
+
+			_, err = Groth16ProverFile("testdata/circuit.zkey", tc.wtns)
+			require.EqualError(t, err, tc.err)
+			require.ErrorIs(t, err, ErrWitnessMismatch)
+		})
+	}
+}
+
+func TestGroth16ProverFile(t *testing.T) {
+	_, wtns, vk := readTestData(t)
+
+	proof, err := Groth16ProverFile("testdata/circuit.zkey", wtns)
+	require.NoError(t, err)
+	err = verifier.VerifyGroth16(*proof, vk)
+	require.NoError(t, err)
+
+	proofJSON, publicJSON, err := Groth16ProverFileRaw(
+		"testdata/circuit.zkey", wtns)
+	require.NoError(t, err)
+	var rawProof types.ZKProof
+	require.NoError(t, json.Unmarshal([]byte(proofJSON), &rawProof.Proof))
+	require.NoError(t, json.Unmarshal([]byte(publicJSON), &rawProof.PubSignals))
+	err = verifier.VerifyGroth16(rawProof, vk)
+	require.NoError(t, err)
+
+	_, err = Groth16ProverFile("", wtns)
+	require.EqualError(t, err, "zkey path is empty")
+
+	_, err = Groth16ProverFile("testdata/not_exists.zkey", wtns)
+	require.Error(t, err)
+}
+
+func TestProver(t *testing.T) {
+	zkey, wtns, vk := readTestData(t)
+
+	p, err := NewProver(zkey)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			proof, err := p.Prove(wtns)
+			if err == nil {
+				err = verifier.VerifyGroth16(*proof, vk)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, p.Close())
+	require.NoError(t, p.Close())
+
+	_, err = p.Prove(wtns)
+	require.ErrorIs(t, err, ErrProverClosed)
+	_, _, err = p.ProveRaw(wtns)
+	require.ErrorIs(t, err, ErrProverClosed)
+
+	_, err = NewProver(nil)
+	require.EqualError(t, err, "zkey is empty")
+
+	// the zkey is validated when the prover is created
+	_, err = NewProver(wtns)
+	require.Error(t, err)
+}
+
+func TestProveContext(t *testing.T) {
+	zkey, wtns, vk := readTestData(t)
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Groth16ProverContext(canceledCtx, zkey, wtns)
+	require.ErrorIs(t, err, context.Canceled)
+	_, _, err = Groth16ProverRawContext(canceledCtx, zkey, wtns)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = Groth16ProverFileContext(canceledCtx, "testdata/circuit.zkey",
+		wtns)
+	require.ErrorIs(t, err, context.Canceled)
+	_, _, err = Groth16ProverFileRawContext(canceledCtx,
+		"testdata/circuit.zkey", wtns)
+	require.ErrorIs(t, err, context.Canceled)
+
+	p, err := NewProver(zkey)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	_, err = p.ProveContext(canceledCtx, wtns)
+	require.ErrorIs(t, err, context.Canceled)
+	_, _, err = p.ProveRawContext(canceledCtx, wtns)
+	require.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	proof, err := p.ProveContext(ctx, wtns)
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyGroth16(*proof, vk))
+
+	proof, err = Groth16ProverFileContext(ctx, "testdata/circuit.zkey", wtns)
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyGroth16(*proof, vk))
+
+	// the pure Go prover checks the context while generating the proof
+	pk, err := parseProvingKey(zkey)
+	require.NoError(t, err)
+	w, err := readWitness(wtns, pk.header())
+	require.NoError(t, err)
+	_, err = pk.prove(canceledCtx, w, rand.Reader)
+	require.ErrorIs(t, err, context.Canceled)
+}
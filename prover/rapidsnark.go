@@ -9,17 +9,150 @@ package prover
 import "C"
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
+	"os"
+	"sync"
 	"unsafe"
 
 	"github.com/iden3/go-rapidsnark/types"
 )
 
-const bufferSize = 16384
+// proofBufferSize is large enough for a bn254 proof in JSON format.
+// rapidsnark requires at least 810 bytes.
+const proofBufferSize = 1024
+
+// errorBufferSize is the size of the buffer for rapidsnark error messages.
+const errorBufferSize = 4096
+
+// MaxBufferSize is the largest public inputs buffer the prover allocates.
 const MaxBufferSize = 10485760
 
+// Prover keeps a copy of a zkey in C memory, which avoids re-copying the zkey
+// bytes for every proof. rapidsnark has no API to keep a parsed zkey, so
+// every proof still parses the whole zkey. It is safe for concurrent use by
+// multiple goroutines. Close must be called to release the zkey memory.
+type Prover struct {
+	mu          sync.RWMutex
+	zkeyPointer unsafe.Pointer
+	zkeySize    int
+	publicSize  uint64
+	header      zkeyHeader
+}
+
+// NewProver creates a Prover for the zkey. The zkey is copied, so the slice
+// may be reused or released by the caller after the call.
+func NewProver(zkey []byte) (*Prover, error) {
+	if len(zkey) == 0 {
+		return nil, ErrZkeyEmpty
+	}
+	header, err := readZkeyHeader(bytes.NewReader(zkey), int64(len(zkey)))
+	if err != nil {
+		return nil, err
+	}
+
+	zkeyPointer := C.CBytes(zkey)
+	publicSize, err := publicBufferSize(func(errorMessage *C.char,
+		errorMessageSize C.ulong) (uint64, C.int) {
+
+		var size C.size_t
+		r := C.groth16_public_size_for_zkey_buf(
+			zkeyPointer, C.ulong(len(zkey)),
+			&size,
+			errorMessage, errorMessageSize)
+		return uint64(size), r
+	})
+	if err != nil {
+		C.free(zkeyPointer)
+		return nil, err
+	}
+
+	return &Prover{
+		zkeyPointer: zkeyPointer,
+		zkeySize:    len(zkey),
+		publicSize:  publicSize,
+		header:      header,
+	}, nil
+}
+
+// Prove generates proof and returns proof and pubsignals as types.ZKProof
+func (p *Prover) Prove(witness []byte) (proof *types.ZKProof, err error) {
+	proofStr, pubSignalsStr, err := p.ProveRaw(witness)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalProof(proofStr, pubSignalsStr)
+}
+
+// ProveContext is like Prove but returns ctx.Err() as soon as ctx is done.
+// rapidsnark can not be interrupted, so the proof generation keeps running
+// in the background and its resources are released when it finishes.
+func (p *Prover) ProveContext(ctx context.Context,
+	witness []byte) (proof *types.ZKProof, err error) {
+	proofStr, pubSignalsStr, err := p.ProveRawContext(ctx, witness)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalProof(proofStr, pubSignalsStr)
+}
+
+// ProveRawContext is like ProveRaw but returns ctx.Err() as soon as ctx is
+// done. rapidsnark can not be interrupted, so the proof generation keeps
+// running in the background and its resources are released when it
+// finishes.
+func (p *Prover) ProveRawContext(ctx context.Context,
+	witness []byte) (proof string, publicInputs string, err error) {
+	if err = ctx.Err(); err != nil {
+		return "", "", err
+	}
+	return runContext(ctx, func() (string, string, error) {
+		return p.ProveRaw(witness)
+	})
+}
+
+// ProveRaw generates proof and returns proof and pubsignals as json string
+func (p *Prover) ProveRaw(
+	witness []byte) (proof string, publicInputs string, err error) {
+	if len(witness) == 0 {
+		return "", "", ErrWitnessEmpty
+	}
+	if _, _, err = checkWitness(witness, p.header); err != nil {
+		return "", "", err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.zkeyPointer == nil {
+		return "", "", ErrProverClosed
+	}
+
+	return groth16ProverRaw(witness, p.publicSize, func(
+		wtnsPointer unsafe.Pointer, wtnsSize C.ulong,
+		proofBuffer *C.char, proofSize *C.ulong,
+		publicBuffer *C.char, publicSize *C.ulong,
+		errorMessage *C.char, errorMessageSize C.ulong) C.int {
+
+		return C.groth16_prover(
+			p.zkeyPointer, C.ulong(p.zkeySize),
+			wtnsPointer, wtnsSize,
+			proofBuffer, proofSize,
+			publicBuffer, publicSize,
+			errorMessage, errorMessageSize)
+	})
+}
+
+// Close releases the zkey memory. It waits for running proofs to finish.
+// Calling Close more than once is a no-op.
+func (p *Prover) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.zkeyPointer != nil {
+		C.free(p.zkeyPointer)
+		p.zkeyPointer = nil
+	}
+	return nil
+}
+
 // Groth16Prover generates proof and returns proof and pubsignals as types.ZKProof
 func Groth16Prover(zkey []byte,
 	witness []byte) (proof *types.ZKProof, err error) {
@@ -27,117 +160,276 @@ func Groth16Prover(zkey []byte,
 	if err != nil {
 		return nil, err
 	}
-	var proofData types.ProofData
-	var pubSignals []string
+	return unmarshalProof(proofStr, pubSignalsStr)
+}
 
-	err = json.Unmarshal([]byte(proofStr), &proofData)
+// Groth16ProverContext is like Groth16Prover but returns ctx.Err() as soon
+// as ctx is done. The proof generation keeps running in the background and
+// its resources are released when it finishes.
+func Groth16ProverContext(ctx context.Context, zkey []byte,
+	witness []byte) (proof *types.ZKProof, err error) {
+	proofStr, pubSignalsStr, err := Groth16ProverRawContext(ctx, zkey,
+		witness)
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal([]byte(pubSignalsStr), &pubSignals)
+	return unmarshalProof(proofStr, pubSignalsStr)
+}
+
+// Groth16ProverFile generates proof using the zkey stored in the file at
+// zkeyPath and returns proof and pubsignals as types.ZKProof. The zkey is
+// read by rapidsnark directly and never loaded into Go memory.
+func Groth16ProverFile(zkeyPath string,
+	witness []byte) (proof *types.ZKProof, err error) {
+	proofStr, pubSignalsStr, err := Groth16ProverFileRaw(zkeyPath, witness)
 	if err != nil {
 		return nil, err
 	}
+	return unmarshalProof(proofStr, pubSignalsStr)
+}
 
-	return &types.ZKProof{Proof: &proofData, PubSignals: pubSignals}, nil
+// Groth16ProverFileContext is like Groth16ProverFile but returns ctx.Err()
+// as soon as ctx is done. The proof generation keeps running in the
+// background and its resources are released when it finishes.
+func Groth16ProverFileContext(ctx context.Context, zkeyPath string,
+	witness []byte) (proof *types.ZKProof, err error) {
+	proofStr, pubSignalsStr, err := Groth16ProverFileRawContext(ctx,
+		zkeyPath, witness)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalProof(proofStr, pubSignalsStr)
 }
 
 // Groth16ProverRaw generates proof and returns proof and pubsignals as json string
 func Groth16ProverRaw(zkey []byte,
 	witness []byte) (proof string, publicInputs string, err error) {
-	if len(zkey) == 0 {
-		return "", "", errors.New("zkey is empty")
+	p, err := NewProver(zkey)
+	if err != nil {
+		return "", "", err
+	}
+	defer p.Close()
+
+	return p.ProveRaw(witness)
+}
+
+// Groth16ProverRawContext is like Groth16ProverRaw but returns ctx.Err() as
+// soon as ctx is done. The proof generation keeps running in the background
+// and its resources are released when it finishes.
+func Groth16ProverRawContext(ctx context.Context, zkey []byte,
+	witness []byte) (proof string, publicInputs string, err error) {
+	if err = ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	p, err := NewProver(zkey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return runContext(ctx, func() (string, string, error) {
+		defer p.Close()
+		return p.ProveRaw(witness)
+	})
+}
+
+// Groth16ProverFileRawContext is like Groth16ProverFileRaw but returns
+// ctx.Err() as soon as ctx is done. The proof generation keeps running in
+// the background and its resources are released when it finishes.
+func Groth16ProverFileRawContext(ctx context.Context, zkeyPath string,
+	witness []byte) (proof string, publicInputs string, err error) {
+	if err = ctx.Err(); err != nil {
+		return "", "", err
+	}
+	return runContext(ctx, func() (string, string, error) {
+		return Groth16ProverFileRaw(zkeyPath, witness)
+	})
+}
+
+// Groth16ProverFileRaw generates proof using the zkey stored in the file at
+// zkeyPath and returns proof and pubsignals as json string
+func Groth16ProverFileRaw(zkeyPath string,
+	witness []byte) (proof string, publicInputs string, err error) {
+	if zkeyPath == "" {
+		return "", "", ErrZkeyPathEmpty
 	}
 	if len(witness) == 0 {
-		return "", "", errors.New("witness is empty")
+		return "", "", ErrWitnessEmpty
+	}
+	header, err := readZkeyFileHeader(zkeyPath)
+	if err != nil {
+		return "", "", err
+	}
+	if _, _, err = checkWitness(witness, header); err != nil {
+		return "", "", err
 	}
 
-	proofBufSize := bufferSize
-	publicBufSize := bufferSize
+	zkeyPathPointer := C.CString(zkeyPath)
+	defer C.free(unsafe.Pointer(zkeyPathPointer))
 
-	const errorBufSize = 4096
-	errorMessage := make([]byte, errorBufSize)
+	publicSize, err := publicBufferSize(func(errorMessage *C.char,
+		errorMessageSize C.ulong) (uint64, C.int) {
 
-	zkeyPointer := C.CBytes(zkey)
-	wtnsPointer := C.CBytes(witness)
+		var size C.ulong
+		r := C.groth16_public_size_for_zkey_file(
+			zkeyPathPointer,
+			&size,
+			errorMessage, errorMessageSize)
+		return uint64(size), r
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return groth16ProverRaw(witness, publicSize, func(
+		wtnsPointer unsafe.Pointer, wtnsSize C.ulong,
+		proofBuffer *C.char, proofSize *C.ulong,
+		publicBuffer *C.char, publicSize *C.ulong,
+		errorMessage *C.char, errorMessageSize C.ulong) C.int {
+
+		return C.groth16_prover_zkey_file(
+			zkeyPathPointer,
+			wtnsPointer, wtnsSize,
+			proofBuffer, proofSize,
+			publicBuffer, publicSize,
+			errorMessage, errorMessageSize)
+	})
+}
+
+// readZkeyFileHeader reads the header of the zkey file at zkeyPath without
+// loading the rest of the file.
+func readZkeyFileHeader(zkeyPath string) (zkeyHeader, error) {
+	f, err := os.Open(zkeyPath)
+	if err != nil {
+		return zkeyHeader{}, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return zkeyHeader{}, err
+	}
+	return readZkeyHeader(f, st.Size())
+}
+
+// runContext runs prove in a new goroutine and waits for its result or for
+// ctx to be done, whichever comes first. prove is always run to completion.
+func runContext(ctx context.Context,
+	prove func() (string, string, error)) (string, string, error) {
+
+	type result struct {
+		proof, publicInputs string
+		err                 error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		var res result
+		res.proof, res.publicInputs, res.err = prove()
+		resCh <- res
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.proof, res.publicInputs, res.err
+	case <-ctx.Done():
+		return "", "", ctx.Err()
+	}
+}
+
+// proveFunc calls one of the rapidsnark prover functions with the witness
+// and output buffers.
+type proveFunc func(wtnsPointer unsafe.Pointer, wtnsSize C.ulong,
+	proofBuffer *C.char, proofSize *C.ulong,
+	publicBuffer *C.char, publicSize *C.ulong,
+	errorMessage *C.char, errorMessageSize C.ulong) C.int
 
-	errorMessagePointer := C.CString(string(errorMessage))
+// publicSizeFunc calls one of the rapidsnark functions that calculate the
+// size of the public inputs buffer for a zkey.
+type publicSizeFunc func(errorMessage *C.char,
+	errorMessageSize C.ulong) (uint64, C.int)
+
+func publicBufferSize(publicSize publicSizeFunc) (uint64, error) {
+	errorMessage := (*C.char)(C.calloc(errorBufferSize, 1))
+	defer C.free(unsafe.Pointer(errorMessage))
+
+	size, r := publicSize(errorMessage, errorBufferSize)
+	if r != C.PROVER_OK {
+		return 0, &Error{
+			Code:    ErrorCode(r),
+			Message: cBufferString(errorMessage, errorBufferSize)}
+	}
+	if size > MaxBufferSize {
+		return 0, ErrPublicInputsTooLarge
+	}
+	return size, nil
+}
+
+// groth16ProverRaw runs the prover once with output buffers of the exact
+// size required by the zkey.
+func groth16ProverRaw(witness []byte, publicBufSize uint64,
+	prove proveFunc) (proof string, publicInputs string, err error) {
+
+	wtnsPointer := C.CBytes(witness)
+	proofBuffer := (*C.char)(C.calloc(proofBufferSize, 1))
+	publicBuffer := (*C.char)(C.calloc(C.size_t(publicBufSize), 1))
+	errorMessage := (*C.char)(C.calloc(errorBufferSize, 1))
 
 	defer func() {
-		C.free(zkeyPointer)
 		C.free(wtnsPointer)
-		C.free(unsafe.Pointer(errorMessagePointer))
+		C.free(unsafe.Pointer(proofBuffer))
+		C.free(unsafe.Pointer(publicBuffer))
+		C.free(unsafe.Pointer(errorMessage))
 	}()
 
-	for {
-		var proofBuffer = make([]byte, proofBufSize)
-		var publicBuffer = make([]byte, publicBufSize)
-
-		//proofBufferPointer := unsafe.Pointer(&proofBuffer[0])
-		proofBufferPointer := C.CString(string(proofBuffer))
-		proofBufSizePointer := unsafe.Pointer(&proofBufSize)
-		publicBufferPointer := C.CString(string(publicBuffer))
-		publicBufSizePointer := unsafe.Pointer(&publicBufSize)
+	proofSize := C.ulong(proofBufferSize)
+	publicSize := C.ulong(publicBufSize)
 
-		defer func() {
-			C.free(unsafe.Pointer(proofBufferPointer))
-			C.free(unsafe.Pointer(publicBufferPointer))
-		}()
+	r := prove(
+		wtnsPointer, C.ulong(len(witness)),
+		proofBuffer, &proofSize,
+		publicBuffer, &publicSize,
+		errorMessage, errorBufferSize)
 
-		r := C.groth16_prover(
-			zkeyPointer, C.ulong(len(zkey)),
-			wtnsPointer, C.ulong(len(witness)),
-			proofBufferPointer, (*C.ulong)(proofBufSizePointer),
-			publicBufferPointer, (*C.ulong)(publicBufSizePointer),
-			errorMessagePointer, errorBufSize)
-
-		proofBuffer = []byte(C.GoString(proofBufferPointer))
-		publicBuffer = []byte(C.GoString(publicBufferPointer))
-		errorMessage = []byte(C.GoString(errorMessagePointer))
-
-		if r != 0 && r != 2 {
-			idx := bytes.IndexByte(errorMessage[:], 0)
-			if idx == -1 {
-				idx = len(errorMessage)
-			}
-			return "", "", fmt.Errorf(
-				"error generating proof. Code: %v. Message: %v",
-				r, string(errorMessage[:idx]))
+	switch r {
+	case C.PROVER_OK:
+	case C.PROVER_ERROR_SHORT_BUFFER:
+		// rapidsnark reports the sizes it needs in proofSize and publicSize
+		if proofSize > proofBufferSize {
+			return "", "", ErrProofTooLarge
 		}
+		return "", "", ErrPublicInputsTooLarge
+	default:
+		return "", "", &Error{
+			Code:    ErrorCode(r),
+			Message: cBufferString(errorMessage, errorBufferSize)}
+	}
 
-		// if true, enlarge buffers and repeat.
-		repeat := false
-
-		if len(proofBuffer) == 0 {
-			if proofBufSize >= MaxBufferSize {
-				return "", "", errors.New("proof is too large")
-			}
-			proofBufSize *= 2
-			if proofBufSize >= MaxBufferSize {
-				proofBufSize = MaxBufferSize
-			}
-			repeat = true
-		} else {
-			proof = string(proofBuffer)
-		}
+	return cBufferString(proofBuffer, proofBufferSize),
+		cBufferString(publicBuffer, int(publicBufSize)), nil
+}
 
-		if len(publicBuffer) == 0 {
-			if publicBufSize >= MaxBufferSize {
-				return "", "", errors.New("public inputs is too large")
-			}
-			publicBufSize *= 2
-			if publicBufSize >= MaxBufferSize {
-				publicBufSize = MaxBufferSize
-			}
-			repeat = true
-		} else {
-			publicInputs = string(publicBuffer)
-		}
+// cBufferString returns the NUL-terminated string stored in a C buffer of
+// the given size.
+func cBufferString(buf *C.char, size int) string {
+	b := C.GoBytes(unsafe.Pointer(buf), C.int(size))
+	if idx := bytes.IndexByte(b, 0); idx != -1 {
+		b = b[:idx]
+	}
+	return string(b)
+}
 
-		if repeat {
-			continue
-		}
+func unmarshalProof(proofStr,
+	pubSignalsStr string) (*types.ZKProof, error) {
+	var proofData types.ProofData
+	var pubSignals []string
 
-		return
+	err := json.Unmarshal([]byte(proofStr), &proofData)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(pubSignalsStr), &pubSignals)
+	if err != nil {
+		return nil, err
 	}
+
+	return &types.ZKProof{Proof: &proofData, PubSignals: pubSignals}, nil
 }
@@ -0,0 +1,178 @@
+//go:build prover_disabled
+
+package prover
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/iden3/go-rapidsnark/types"
+)
+
+// Prover keeps a parsed zkey in memory to generate many proofs with it.
+// It is safe for concurrent use by multiple goroutines. Close must be
+// called to release the zkey memory.
+type Prover struct {
+	mu sync.RWMutex
+	pk *provingKey
+}
+
+// NewProver creates a Prover for the zkey.
+func NewProver(zkey []byte) (*Prover, error) {
+	if len(zkey) == 0 {
+		return nil, ErrZkeyEmpty
+	}
+	pk, err := parseProvingKey(zkey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidZkey, err)
+	}
+	return &Prover{pk: pk}, nil
+}
+
+// Prove generates proof and returns proof and pubsignals as types.ZKProof
+func (p *Prover) Prove(witness []byte) (proof *types.ZKProof, err error) {
+	return p.ProveContext(context.Background(), witness)
+}
+
+// ProveContext is like Prove but stops generating the proof and returns
+// ctx.Err() when ctx is done.
+func (p *Prover) ProveContext(ctx context.Context,
+	witness []byte) (proof *types.ZKProof, err error) {
+	if len(witness) == 0 {
+		return nil, ErrWitnessEmpty
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.pk == nil {
+		return nil, ErrProverClosed
+	}
+
+	w, err := readWitness(witness, p.pk.header())
+	if err != nil {
+		return nil, err
+	}
+	return p.pk.prove(ctx, w, rand.Reader)
+}
+
+// ProveRaw generates proof and returns proof and pubsignals as json string
+func (p *Prover) ProveRaw(witness []byte) (proof string, publicInputs string, err error) {
+	return p.ProveRawContext(context.Background(), witness)
+}
+
+// ProveRawContext is like ProveRaw but stops generating the proof and
+// returns ctx.Err() when ctx is done.
+func (p *Prover) ProveRawContext(ctx context.Context,
+	witness []byte) (proof string, publicInputs string, err error) {
+	zkProof, err := p.ProveContext(ctx, witness)
+	if err != nil {
+		return "", "", err
+	}
+	return marshalProof(zkProof)
+}
+
+// Close releases the zkey memory. It waits for running proofs to finish.
+// Calling Close more than once is a no-op.
+func (p *Prover) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pk = nil
+	return nil
+}
+
+// Groth16Prover generates proof and returns proof and pubsignals as types.ZKProof
+func Groth16Prover(zkey []byte, witness []byte) (proof *types.ZKProof, err error) {
+	return Groth16ProverContext(context.Background(), zkey, witness)
+}
+
+// Groth16ProverContext is like Groth16Prover but stops generating the proof
+// and returns ctx.Err() when ctx is done.
+func Groth16ProverContext(ctx context.Context, zkey []byte,
+	witness []byte) (proof *types.ZKProof, err error) {
+	p, err := NewProver(zkey)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+
+	return p.ProveContext(ctx, witness)
+}
+
+// Groth16ProverFile generates proof using the zkey stored in the file at
+// zkeyPath and returns proof and pubsignals as types.ZKProof
+func Groth16ProverFile(zkeyPath string, witness []byte) (proof *types.ZKProof, err error) {
+	return Groth16ProverFileContext(context.Background(), zkeyPath, witness)
+}
+
+// Groth16ProverFileContext is like Groth16ProverFile but stops generating
+// the proof and returns ctx.Err() when ctx is done.
+func Groth16ProverFileContext(ctx context.Context, zkeyPath string,
+	witness []byte) (proof *types.ZKProof, err error) {
+	zkey, err := readZkeyFile(zkeyPath)
+	if err != nil {
+		return nil, err
+	}
+	return Groth16ProverContext(ctx, zkey, witness)
+}
+
+// Groth16ProverRaw generates proof and returns proof and pubsignals as json string
+func Groth16ProverRaw(zkey []byte, witness []byte) (proof string, publicInputs string, err error) {
+	return Groth16ProverRawContext(context.Background(), zkey, witness)
+}
+
+// Groth16ProverRawContext is like Groth16ProverRaw but stops generating the
+// proof and returns ctx.Err() when ctx is done.
+func Groth16ProverRawContext(ctx context.Context, zkey []byte,
+	witness []byte) (proof string, publicInputs string, err error) {
+	p, err := NewProver(zkey)
+	if err != nil {
+		return "", "", err
+	}
+	defer p.Close()
+
+	return p.ProveRawContext(ctx, witness)
+}
+
+// Groth16ProverFileRaw generates proof using the zkey stored in the file at
+// zkeyPath and returns proof and pubsignals as json string
+func Groth16ProverFileRaw(zkeyPath string, witness []byte) (proof string, publicInputs string, err error) {
+	return Groth16ProverFileRawContext(context.Background(), zkeyPath,
+		witness)
+}
+
+// Groth16ProverFileRawContext is like Groth16ProverFileRaw but stops
+// generating the proof and returns ctx.Err() when ctx is done.
+func Groth16ProverFileRawContext(ctx context.Context, zkeyPath string,
+	witness []byte) (proof string, publicInputs string, err error) {
+	zkey, err := readZkeyFile(zkeyPath)
+	if err != nil {
+		return "", "", err
+	}
+	return Groth16ProverRawContext(ctx, zkey, witness)
+}
+
+func readZkeyFile(zkeyPath string) ([]byte, error) {
+	if zkeyPath == "" {
+		return nil, ErrZkeyPathEmpty
+	}
+	return os.ReadFile(zkeyPath)
+}
+
+func marshalProof(zkProof *types.ZKProof) (proof string, publicInputs string, err error) {
+	proofBytes, err := json.Marshal(zkProof.Proof)
+	if err != nil {
+		return "", "", err
+	}
+	publicBytes, err := json.Marshal(zkProof.PubSignals)
+	if err != nil {
+		return "", "", err
+	}
+	return string(proofBytes), string(publicBytes), nil
+}
@@ -0,0 +1,431 @@
+// Command groth16gen writes the Groth16 test vectors of the repository:
+// circuit.zkey, circuit.wtns and verification_key.json.
+//
+// The setup is derived from fixed seeds, so the output is reproducible. The
+// curve arithmetic is done with gnark-crypto, not with the packages under
+// test, and vk_alphabeta_12 is checked against a key exported by snarkjs
+// before anything is written.
+//
+// Usage, from this directory:
+//
+//	go run . ..
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+var (
+	q, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	r, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+)
+
+// verificationKey has the fields of a snarkjs Groth16 verification key, in
+// the order snarkjs writes them.
+type verificationKey struct {
+	Protocol    string       `json:"protocol"`
+	Curve       string       `json:"curve"`
+	NPublic     int          `json:"nPublic"`
+	Alpha1      []string     `json:"vk_alpha_1"`
+	Beta2       [][]string   `json:"vk_beta_2"`
+	Gamma2      [][]string   `json:"vk_gamma_2"`
+	Delta2      [][]string   `json:"vk_delta_2"`
+	AlphaBeta12 [][][]string `json:"vk_alphabeta_12"`
+	IC          [][]string   `json:"IC"`
+}
+
+// term is coef·signal in a linear combination.
+type term struct {
+	signal int
+	coef   *big.Int
+}
+
+// constraint is A·B = C.
+type constraint struct {
+	A, B, C []term
+}
+
+type section struct {
+	id   uint32
+	data []byte
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: groth16gen <output dir>")
+	}
+	if err := checkAlphaBeta(
+		"../../../zkey/testdata/snarkjs_verification_key.json"); err != nil {
+		log.Fatal(err)
+	}
+
+	zkey, wtns, vk := generate()
+	vkJSON, err := json.MarshalIndent(vk, "", " ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		"circuit.zkey":          zkey,
+		"circuit.wtns":          wtns,
+		"verification_key.json": vkJSON,
+	} {
+		err = os.WriteFile(filepath.Join(os.Args[1], name), data, 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate returns the zkey, the witness and the verification key of
+//
+//	m = a·b
+//	out = m·(b+1)
+//	t = (2a+3b)·(a-1)
+//
+// with the signals 1, out, a, b, m, t, where out and a are public, for
+// a = 3 and b = 11.
+func generate() ([]byte, []byte, verificationKey) {
+	const nVars, nPublic = 6, 2
+	one := big.NewInt(1)
+	cs := []constraint{
+		{A: []term{{2, one}}, B: []term{{3, one}}, C: []term{{4, one}}},
+		{A: []term{{4, one}}, B: []term{{3, one}, {0, one}},
+			C: []term{{1, one}}},
+		{A: []term{{2, big.NewInt(2)}, {3, big.NewInt(3)}},
+			B: []term{{2, one}, {0, mod(big.NewInt(-1))}},
+			C: []term{{5, one}}},
+	}
+	a, b := big.NewInt(3), big.NewInt(11)
+	m := new(big.Int).Mul(a, b)
+	out := new(big.Int).Mul(m, new(big.Int).Add(b, one))
+	t := new(big.Int).Mul(
+		new(big.Int).Add(new(big.Int).Lsh(a, 1),
+			new(big.Int).Mul(b, big.NewInt(3))),
+		new(big.Int).Sub(a, one))
+	witness := []*big.Int{one, out, a, b, m, t}
+
+	// snarkjs adds a constraint signal·0 = 0 for the constant and every
+	// public signal, so the proof is bound to the public inputs
+	nConstraints := len(cs)
+	for s := 0; s <= nPublic; s++ {
+		cs = append(cs, constraint{A: []term{{s, one}}})
+	}
+	domainSize := 1
+	for domainSize < len(cs) {
+		domainSize *= 2
+	}
+
+	tau, alpha, beta := seed("tau"), seed("alpha"), seed("beta")
+	gamma, delta := seed("gamma"), seed("delta")
+
+	// the QAP polynomials of every signal evaluated at tau
+	lagrange := lagrangeAt(domainSize, tau)
+	u, v, w := make([]*big.Int, nVars), make([]*big.Int, nVars),
+		make([]*big.Int, nVars)
+	for i := range u {
+		u[i], v[i], w[i] = new(big.Int), new(big.Int), new(big.Int)
+	}
+	for i, c := range cs {
+		for _, p := range []struct {
+			lc   []term
+			poly []*big.Int
+		}{{c.A, u}, {c.B, v}, {c.C, w}} {
+			for _, tm := range p.lc {
+				x := p.poly[tm.signal]
+				mod(x.Add(x, new(big.Int).Mul(tm.coef, lagrange[i])))
+			}
+		}
+	}
+
+	// the coefficients of A and B, in Montgomery form of the scalar field
+	// multiplied again by R, as rapidsnark expects them
+	rr := new(big.Int).Lsh(one, 256)
+	rr.Mul(rr, rr).Mod(rr, r)
+	var coefs bytes.Buffer
+	nCoefs := 0
+	writeCoef := func(matrix, c int, tm term) {
+		coefs.Write(u32(uint32(matrix)))
+		coefs.Write(u32(uint32(c)))
+		coefs.Write(u32(uint32(tm.signal)))
+		coefs.Write(le(mod(new(big.Int).Mul(tm.coef, rr))))
+		nCoefs++
+	}
+	for i, c := range cs[:nConstraints] {
+		for _, tm := range c.A {
+			writeCoef(0, i, tm)
+		}
+	}
+	for i, c := range cs[:nConstraints] {
+		for _, tm := range c.B {
+			writeCoef(1, i, tm)
+		}
+	}
+	for s := 0; s <= nPublic; s++ {
+		writeCoef(0, nConstraints+s, term{s, one})
+	}
+	coefSection := append(u32(uint32(nCoefs)), coefs.Bytes()...)
+
+	var header bytes.Buffer
+	header.Write(u32(32))
+	header.Write(le(q))
+	header.Write(u32(32))
+	header.Write(le(r))
+	header.Write(u32(nVars))
+	header.Write(u32(nPublic))
+	header.Write(u32(uint32(domainSize)))
+	header.Write(g1Bytes(g1(alpha)))
+	header.Write(g1Bytes(g1(beta)))
+	header.Write(g2Bytes(g2(beta)))
+	header.Write(g2Bytes(g2(gamma)))
+	header.Write(g1Bytes(g1(delta)))
+	header.Write(g2Bytes(g2(delta)))
+
+	// β·u + α·v + w of a signal
+	lc := func(j int) *big.Int {
+		x := new(big.Int).Mul(beta, u[j])
+		x.Add(x, new(big.Int).Mul(alpha, v[j]))
+		return mod(x.Add(x, w[j]))
+	}
+	var ic, pointsA, pointsB1, pointsB2, pointsC, pointsH bytes.Buffer
+	vkIC := make([][]string, 0, nPublic+1)
+	for j := 0; j <= nPublic; j++ {
+		p := g1(new(big.Int).Mul(lc(j), inverse(gamma)))
+		ic.Write(g1Bytes(p))
+		vkIC = append(vkIC, g1Strings(p))
+	}
+	for j := 0; j < nVars; j++ {
+		pointsA.Write(g1Bytes(g1(u[j])))
+		pointsB1.Write(g1Bytes(g1(v[j])))
+		pointsB2.Write(g2Bytes(g2(v[j])))
+	}
+	for j := nPublic + 1; j < nVars; j++ {
+		pointsC.Write(g1Bytes(g1(new(big.Int).Mul(lc(j), inverse(delta)))))
+	}
+	// rapidsnark evaluates h on the odd roots of the domain of twice the
+	// size, so the H points are the matching Lagrange bases over δ
+	lagrange2 := lagrangeAt(2*domainSize, tau)
+	for i := 0; i < domainSize; i++ {
+		pointsH.Write(g1Bytes(g1(
+			new(big.Int).Mul(lagrange2[2*i+1], inverse(delta)))))
+	}
+
+	// an empty contribution section: a zero hash and no contributions
+	contributions := append(make([]byte, 64), u32(0)...)
+	zkey := writeBinFile("zkey", 1, []section{
+		{1, u32(1)}, // groth16
+		{2, header.Bytes()},
+		{3, ic.Bytes()},
+		{4, coefSection},
+		{5, pointsA.Bytes()},
+		{6, pointsB1.Bytes()},
+		{7, pointsB2.Bytes()},
+		{8, pointsC.Bytes()},
+		{9, pointsH.Bytes()},
+		{10, contributions},
+	})
+
+	var wtnsHeader, wtnsData bytes.Buffer
+	wtnsHeader.Write(u32(32))
+	wtnsHeader.Write(le(r))
+	wtnsHeader.Write(u32(uint32(len(witness))))
+	for _, x := range witness {
+		wtnsData.Write(le(x))
+	}
+	wtns := writeBinFile("wtns", 2, []section{
+		{1, wtnsHeader.Bytes()},
+		{2, wtnsData.Bytes()},
+	})
+
+	alpha1, beta2 := g1(alpha), g2(beta)
+	vk := verificationKey{
+		Protocol:    "groth16",
+		Curve:       "bn128",
+		NPublic:     nPublic,
+		Alpha1:      g1Strings(alpha1),
+		Beta2:       g2Strings(beta2),
+		Gamma2:      g2Strings(g2(gamma)),
+		Delta2:      g2Strings(g2(delta)),
+		AlphaBeta12: alphaBeta12(alpha1, beta2),
+		IC:          vkIC,
+	}
+	return zkey, wtns, vk
+}
+
+// checkAlphaBeta ensures that alphaBeta12 computes vk_alphabeta_12 of a
+// verification key exported by snarkjs.
+func checkAlphaBeta(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var vk verificationKey
+	if err = json.Unmarshal(data, &vk); err != nil {
+		return err
+	}
+	var alpha1 bn254.G1Affine
+	if _, err = alpha1.X.SetString(vk.Alpha1[0]); err != nil {
+		return err
+	}
+	if _, err = alpha1.Y.SetString(vk.Alpha1[1]); err != nil {
+		return err
+	}
+	var beta2 bn254.G2Affine
+	for _, c := range []struct {
+		e *fp.Element
+		s string
+	}{
+		{&beta2.X.A0, vk.Beta2[0][0]}, {&beta2.X.A1, vk.Beta2[0][1]},
+		{&beta2.Y.A0, vk.Beta2[1][0]}, {&beta2.Y.A1, vk.Beta2[1][1]},
+	} {
+		if _, err = c.e.SetString(c.s); err != nil {
+			return err
+		}
+	}
+	if !reflect.DeepEqual(alphaBeta12(alpha1, beta2), vk.AlphaBeta12) {
+		return errors.New("vk_alphabeta_12 differs from snarkjs")
+	}
+	return nil
+}
+
+// alphaBeta12 returns e(α, β) in the snarkjs format. gnark-crypto and
+// ffjavascript build Fp12 with the same tower, so the coefficients are
+// written in their natural order.
+func alphaBeta12(alpha1 bn254.G1Affine, beta2 bn254.G2Affine) [][][]string {
+	e, err := bn254.Pair([]bn254.G1Affine{alpha1}, []bn254.G2Affine{beta2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fp6 := func(x bn254.E6) [][]string {
+		return [][]string{
+			{fpString(x.B0.A0), fpString(x.B0.A1)},
+			{fpString(x.B1.A0), fpString(x.B1.A1)},
+			{fpString(x.B2.A0), fpString(x.B2.A1)},
+		}
+	}
+	return [][][]string{fp6(e.C0), fp6(e.C1)}
+}
+
+// seed derives a scalar of the setup from a label.
+func seed(label string) *big.Int {
+	h := sha256.Sum256([]byte(label))
+	return mod(new(big.Int).SetBytes(h[:]))
+}
+
+func mod(x *big.Int) *big.Int {
+	return x.Mod(x, r)
+}
+
+func inverse(x *big.Int) *big.Int {
+	return new(big.Int).ModInverse(x, r)
+}
+
+// lagrangeAt evaluates the Lagrange bases of the domain of size n at x.
+// The roots of unity are the ones of ffjavascript, powers of 5.
+func lagrangeAt(n int, x *big.Int) []*big.Int {
+	e := new(big.Int).Sub(r, big.NewInt(1))
+	e.Div(e, big.NewInt(int64(n)))
+	root := new(big.Int).Exp(big.NewInt(5), e, r)
+
+	// L_i(x) = ωⁱ·(xⁿ - 1) / (n·(x - ωⁱ))
+	z := new(big.Int).Exp(x, big.NewInt(int64(n)), r)
+	z.Sub(z, big.NewInt(1))
+	mod(z.Mul(z, inverse(big.NewInt(int64(n)))))
+	bases := make([]*big.Int, n)
+	wi := big.NewInt(1)
+	for i := range bases {
+		d := mod(new(big.Int).Sub(x, wi))
+		l := new(big.Int).Mul(z, wi)
+		bases[i] = mod(l.Mul(l, inverse(d)))
+		wi = mod(new(big.Int).Mul(wi, root))
+	}
+	return bases
+}
+
+func g1(k *big.Int) bn254.G1Affine {
+	var p bn254.G1Affine
+	p.ScalarMultiplicationBase(new(big.Int).Mod(k, r))
+	return p
+}
+
+func g2(k *big.Int) bn254.G2Affine {
+	var p bn254.G2Affine
+	p.ScalarMultiplicationBase(new(big.Int).Mod(k, r))
+	return p
+}
+
+// fpBytes returns x in Montgomery form, little-endian, as it is stored in
+// a zkey. gnark-crypto keeps field elements in Montgomery form.
+func fpBytes(x fp.Element) []byte {
+	b := make([]byte, 0, fp.Bytes)
+	for _, limb := range x {
+		b = binary.LittleEndian.AppendUint64(b, limb)
+	}
+	return b
+}
+
+func g1Bytes(p bn254.G1Affine) []byte {
+	return append(fpBytes(p.X), fpBytes(p.Y)...)
+}
+
+func g2Bytes(p bn254.G2Affine) []byte {
+	var b []byte
+	for _, x := range []fp.Element{p.X.A0, p.X.A1, p.Y.A0, p.Y.A1} {
+		b = append(b, fpBytes(x)...)
+	}
+	return b
+}
+
+func fpString(x fp.Element) string {
+	return x.BigInt(new(big.Int)).String()
+}
+
+func g1Strings(p bn254.G1Affine) []string {
+	return []string{fpString(p.X), fpString(p.Y), "1"}
+}
+
+func g2Strings(p bn254.G2Affine) [][]string {
+	return [][]string{
+		{fpString(p.X.A0), fpString(p.X.A1)},
+		{fpString(p.Y.A0), fpString(p.Y.A1)},
+		{"1", "0"},
+	}
+}
+
+// le returns x as 32 bytes, little-endian.
+func le(x *big.Int) []byte {
+	b := x.FillBytes(make([]byte, 32))
+	for i := 0; i < len(b)/2; i++ {
+		b[i], b[len(b)-1-i] = b[len(b)-1-i], b[i]
+	}
+	return b
+}
+
+func u32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+// writeBinFile encodes an iden3 binary container.
+func writeBinFile(magic string, version uint32, sections []section) []byte {
+	var b bytes.Buffer
+	b.WriteString(magic)
+	b.Write(u32(version))
+	b.Write(u32(uint32(len(sections))))
+	for _, s := range sections {
+		b.Write(u32(s.id))
+		b.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(s.data))))
+		b.Write(s.data)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,162 @@
+package prover
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/zkey"
+)
+
+const (
+	wtnsSectionHeader = 1
+	wtnsSectionData   = 2
+)
+
+// wtnsHeader is the header section of a wtns file.
+type wtnsHeader struct {
+	n8       int
+	prime    *big.Int
+	nWitness int
+}
+
+// zkeyHeader has the parameters of a zkey a witness must match.
+type zkeyHeader struct {
+	n8r   int
+	r     *big.Int
+	nVars int
+}
+
+// readZkeyHeader reads the parameters of the zkey the witness is checked
+// against before proving. Only the header sections of the zkey are read.
+func readZkeyHeader(r io.ReaderAt, size int64) (zkeyHeader, error) {
+	z, err := zkey.Read(r, size)
+	if err != nil {
+		return zkeyHeader{}, fmt.Errorf("%w: %v", ErrInvalidZkey, err)
+	}
+	return zkeyHeader{n8r: z.N8r, r: z.R, nVars: z.NVars}, nil
+}
+
+// checkWitness parses the wtns file and ensures that it was calculated for
+// the circuit of the zkey, so mismatches are reported before the witness
+// is passed to the prover. It returns the header and the data section of
+// the witness.
+func checkWitness(wtns []byte, zk zkeyHeader) (wtnsHeader, []byte, error) {
+	hdr, data, err := readWitnessFile(wtns)
+	if err != nil {
+		return wtnsHeader{}, nil, fmt.Errorf("%w: %v", ErrInvalidWitness, err)
+	}
+
+	if hdr.n8 != zk.n8r {
+		return wtnsHeader{}, nil, &witnessMismatchError{
+			msg: fmt.Sprintf("witness field element size is %v bytes, "+
+				"zkey expects %v", hdr.n8, zk.n8r)}
+	}
+	if hdr.prime.Cmp(zk.r) != 0 {
+		return wtnsHeader{}, nil, &witnessMismatchError{
+			msg: "witness prime differs from zkey r"}
+	}
+	if hdr.nWitness != zk.nVars {
+		return wtnsHeader{}, nil, &witnessMismatchError{
+			msg: fmt.Sprintf("witness has %v signals, zkey expects %v",
+				hdr.nWitness, zk.nVars),
+			invalidLength: true}
+	}
+	return hdr, data, nil
+}
+
+// readWitnessFile decodes the header of a wtns file and returns it
+// together with the data section. It accepts the same files as
+// witness.ParseWTNS, but the signals are not decoded.
+func readWitnessFile(wtns []byte) (wtnsHeader, []byte, error) {
+	sections, err := readBinFile(wtns, "wtns", 2)
+	if err != nil {
+		return wtnsHeader{}, nil, err
+	}
+	for id := range sections {
+		if id != wtnsSectionHeader && id != wtnsSectionData {
+			return wtnsHeader{}, nil,
+				fmt.Errorf("unexpected wtns section id: %v", id)
+		}
+	}
+
+	sec, ok := sections[wtnsSectionHeader]
+	if !ok {
+		return wtnsHeader{}, nil, errors.New("wtns header section is missing")
+	}
+	// n8, the prime in n8 bytes and the number of signals
+	if len(sec) < 4 {
+		return wtnsHeader{}, nil,
+			errors.New("invalid wtns header section size")
+	}
+	var hdr wtnsHeader
+	n8 := binary.LittleEndian.Uint32(sec)
+	if n8 == 0 || n8%4 != 0 {
+		return wtnsHeader{}, nil,
+			fmt.Errorf("invalid wtns field element size: %v", n8)
+	}
+	if uint64(len(sec)) != 4+uint64(n8)+4 {
+		return wtnsHeader{}, nil,
+			errors.New("invalid wtns header section size")
+	}
+	hdr.n8 = int(n8)
+	prime := sec[4 : 4+hdr.n8]
+	hdr.prime = readLEInt(prime)
+	if hdr.prime.Cmp(big.NewInt(1)) <= 0 {
+		return wtnsHeader{}, nil,
+			fmt.Errorf("invalid wtns prime: %v", hdr.prime)
+	}
+	hdr.nWitness = int(binary.LittleEndian.Uint32(sec[4+hdr.n8:]))
+
+	data, ok := sections[wtnsSectionData]
+	if !ok {
+		return wtnsHeader{}, nil, errors.New("wtns data section is missing")
+	}
+	if uint64(len(data)) != uint64(hdr.nWitness)*uint64(hdr.n8) {
+		return wtnsHeader{}, nil, fmt.Errorf(
+			"invalid wtns data section size: %v, expected %v signals of %v bytes",
+			len(data), hdr.nWitness, hdr.n8)
+	}
+	for i := 0; i < hdr.nWitness; i++ {
+		if !lessLE(data[i*hdr.n8:(i+1)*hdr.n8], prime) {
+			return wtnsHeader{}, nil,
+				fmt.Errorf("wtns signal %v is not in the field", i)
+		}
+	}
+	return hdr, data, nil
+}
+
+// readWitness decodes the signal values of a wtns file calculated for the
+// circuit of the zkey.
+func readWitness(wtns []byte, zk zkeyHeader) ([]*big.Int, error) {
+	hdr, data, err := checkWitness(wtns, zk)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]*big.Int, hdr.nWitness)
+	for i := range values {
+		values[i] = readLEInt(data[i*hdr.n8 : (i+1)*hdr.n8])
+	}
+	return values, nil
+}
+
+func readLEInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// lessLE reports whether the little-endian integer a is less than b. Both
+// have the same length.
+func lessLE(a, b []byte) bool {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
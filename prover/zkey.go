@@ -0,0 +1,151 @@
+package prover
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"github.com/iden3/go-rapidsnark/zkey"
+)
+
+// bn254 base field and scalar field sizes in bytes.
+const n8 = 32
+
+var bn254R, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// provingKey is a Groth16 proving key decoded from the zkey file format.
+type provingKey struct {
+	nVars      int
+	nPublic    int
+	domainSize int
+
+	alpha1 *bn256.G1
+	beta1  *bn256.G1
+	beta2  *bn256.G2
+	delta1 *bn256.G1
+	delta2 *bn256.G2
+
+	coefs []zkey.Coef
+	a     []*bn256.G1
+	b1    []*bn256.G1
+	b2    []*bn256.G2
+	c     []*bn256.G1
+	h     []*bn256.G1
+
+	domain *frDomain
+}
+
+// parseProvingKey decodes a Groth16 zkey for the bn254 curve.
+func parseProvingKey(data []byte) (*provingKey, error) {
+	z, err := zkey.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if z.Curve != zkey.CurveBN128 {
+		return nil, errors.New("zkey curve is not supported, only bn254 is")
+	}
+	if z.DomainSize > 1<<27 {
+		return nil, fmt.Errorf("invalid zkey domain size: %v", z.DomainSize)
+	}
+
+	pk := &provingKey{
+		nVars:      z.NVars,
+		nPublic:    z.NPublic,
+		domainSize: z.DomainSize,
+	}
+	if pk.alpha1, err = toG1(z.Alpha1); err != nil {
+		return nil, fmt.Errorf("invalid zkey vk_alpha_1: %w", err)
+	}
+	if pk.beta1, err = toG1(z.Beta1); err != nil {
+		return nil, fmt.Errorf("invalid zkey vk_beta_1: %w", err)
+	}
+	if pk.beta2, err = toG2(z.Beta2); err != nil {
+		return nil, fmt.Errorf("invalid zkey vk_beta_2: %w", err)
+	}
+	if pk.delta1, err = toG1(z.Delta1); err != nil {
+		return nil, fmt.Errorf("invalid zkey vk_delta_1: %w", err)
+	}
+	if pk.delta2, err = toG2(z.Delta2); err != nil {
+		return nil, fmt.Errorf("invalid zkey vk_delta_2: %w", err)
+	}
+
+	if pk.coefs, err = z.Coefs(); err != nil {
+		return nil, err
+	}
+	if pk.a, err = readG1Section(z, zkey.SectionPointsA); err != nil {
+		return nil, err
+	}
+	if pk.b1, err = readG1Section(z, zkey.SectionPointsB1); err != nil {
+		return nil, err
+	}
+	if pk.b2, err = readG2Section(z, zkey.SectionPointsB2); err != nil {
+		return nil, err
+	}
+	if pk.c, err = readG1Section(z, zkey.SectionPointsC); err != nil {
+		return nil, err
+	}
+	if pk.h, err = readG1Section(z, zkey.SectionPointsH); err != nil {
+		return nil, err
+	}
+
+	pk.domain = newFrDomain(bn254R, pk.domainSize)
+	return pk, nil
+}
+
+func readG1Section(z *zkey.ZKey, id uint32) ([]*bn256.G1, error) {
+	zp, err := z.PointsG1(id)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]*bn256.G1, len(zp))
+	for i, p := range zp {
+		if points[i], err = toG1(p); err != nil {
+			return nil, fmt.Errorf("zkey section %v: %w", id, err)
+		}
+	}
+	return points, nil
+}
+
+func readG2Section(z *zkey.ZKey, id uint32) ([]*bn256.G2, error) {
+	zp, err := z.PointsG2(id)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]*bn256.G2, len(zp))
+	for i, p := range zp {
+		if points[i], err = toG2(p); err != nil {
+			return nil, fmt.Errorf("zkey section %v: %w", id, err)
+		}
+	}
+	return points, nil
+}
+
+// toG1 converts an affine point of the zkey to bn256, which checks that it
+// is on the curve.
+func toG1(p zkey.G1) (*bn256.G1, error) {
+	buf := make([]byte, 2*n8)
+	p.X.FillBytes(buf[:n8])
+	p.Y.FillBytes(buf[n8:])
+	g := new(bn256.G1)
+	if _, err := g.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// toG2 converts an affine point of the zkey to bn256, which checks that it
+// is on the curve.
+func toG2(p zkey.G2) (*bn256.G2, error) {
+	buf := make([]byte, 4*n8)
+	// bn256 expects the imaginary part first
+	p.X[1].FillBytes(buf[:n8])
+	p.X[0].FillBytes(buf[n8 : 2*n8])
+	p.Y[1].FillBytes(buf[2*n8 : 3*n8])
+	p.Y[0].FillBytes(buf[3*n8:])
+	g := new(bn256.G2)
+	if _, err := g.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
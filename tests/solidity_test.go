@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier"
+	"github.com/stretchr/testify/require"
+)
+
+// compileSolidity compiles the contract with solc and returns its runtime
+// bytecode. The test is skipped if solc is not installed.
+func compileSolidity(t *testing.T, contract []byte, name string) []byte {
+	solc, err := exec.LookPath("solc")
+	if err != nil {
+		t.Skip("solc is not installed")
+	}
+
+	path := filepath.Join(t.TempDir(), "verifier.sol")
+	require.NoError(t, os.WriteFile(path, contract, 0o600))
+	out, err := exec.Command(solc, "--optimize", "--combined-json",
+		"bin-runtime", path).Output()
+	require.NoError(t, err)
+
+	var res struct {
+		Contracts map[string]struct {
+			BinRuntime string `json:"bin-runtime"`
+		} `json:"contracts"`
+	}
+	require.NoError(t, json.Unmarshal(out, &res))
+	c, ok := res.Contracts[path+":"+name]
+	require.True(t, ok, "contract %v not found in the solc output", name)
+	code, err := hex.DecodeString(c.BinRuntime)
+	require.NoError(t, err)
+	return code
+}
+
+// callVerifyProof runs verifyProof of the verifier in the EVM and returns
+// its result.
+func callVerifyProof(t *testing.T, code []byte, zkProof types.ZKProof) bool {
+	input, err := zkProof.VerifyProofCallData()
+	require.NoError(t, err)
+	ret, _, err := runtime.Execute(code, input, nil)
+	require.NoError(t, err)
+	require.Len(t, ret, 32)
+	return new(big.Int).SetBytes(ret).Sign() != 0
+}
+
+func readZKProof(t *testing.T, proofPath, publicPath string) types.ZKProof {
+	proofJSON, err := os.ReadFile(proofPath)
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile(publicPath)
+	require.NoError(t, err)
+
+	var zkProof types.ZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+func TestSolidityVerifier(t *testing.T) {
+	vkJSON, err := os.ReadFile("../verifier/testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readZKProof(t, "../verifier/testdata/proof.json",
+		"../verifier/testdata/public.json")
+	require.NoError(t, verifier.VerifyGroth16(zkProof, vkJSON))
+
+	sol, err := verifier.ExportSolidityVerifier(vkJSON,
+		verifier.WithContractName("TestVerifier"))
+	require.NoError(t, err)
+	code := compileSolidity(t, sol, "TestVerifier")
+
+	require.True(t, callVerifyProof(t, code, zkProof))
+
+	// another public input
+	tampered := zkProof
+	tampered.PubSignals = append([]string{}, zkProof.PubSignals...)
+	tampered.PubSignals[0] = "397"
+	require.False(t, callVerifyProof(t, code, tampered))
+
+	// a public input that is not in the scalar field
+	r, _ := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	signal, _ := new(big.Int).SetString(zkProof.PubSignals[0], 10)
+	tampered.PubSignals[0] = signal.Add(signal, r).String()
+	require.False(t, callVerifyProof(t, code, tampered))
+}
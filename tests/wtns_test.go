@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/prover"
+	"github.com/iden3/go-rapidsnark/witness/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type wtnsSection struct {
+	id   uint32
+	data []byte
+}
+
+// encodeWTNS writes a wtns container with the given sections.
+func encodeWTNS(version uint32, sections ...wtnsSection) []byte {
+	var b bytes.Buffer
+	b.WriteString("wtns")
+	_ = binary.Write(&b, binary.LittleEndian,
+		[]uint32{version, uint32(len(sections))})
+	for _, s := range sections {
+		_ = binary.Write(&b, binary.LittleEndian, s.id)
+		_ = binary.Write(&b, binary.LittleEndian, uint64(len(s.data)))
+		b.Write(s.data)
+	}
+	return b.Bytes()
+}
+
+// TestWTNSParsers ensures that the wtns validation of the prover, which
+// does not decode the signals, rejects the same files as
+// witness.ParseWTNS.
+func TestWTNSParsers(t *testing.T) {
+	zkey, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	wtns, err := os.ReadFile("../prover/testdata/circuit.wtns")
+	require.NoError(t, err)
+
+	_, err = witness.ParseWTNS(wtns)
+	require.NoError(t, err)
+	_, err = prover.Groth16Prover(zkey, wtns)
+	require.NoError(t, err)
+
+	// the sections of circuit.wtns
+	hdr := wtns[24:64]
+	data := wtns[76:]
+	require.Len(t, data, 6*32)
+	with := func(b []byte, off int, v uint32) []byte {
+		b = append([]byte{}, b...)
+		binary.LittleEndian.PutUint32(b[off:], v)
+		return b
+	}
+	prime := hdr[4:36]
+	// n8 = 32, prime = 1 and 6 signals
+	primeOne := with(with(make([]byte, 40), 0, 32), 4, 1)
+	binary.LittleEndian.PutUint32(primeOne[36:], 6)
+
+	testCases := []struct {
+		name string
+		wtns []byte
+	}{
+		{"too short", wtns[:11]},
+		{"magic", append([]byte("zkey"), wtns[4:]...)},
+		{"version 0", with(wtns, 4, 0)},
+		{"version 3", with(wtns, 4, 3)},
+		{"truncated section header", with(wtns, 8, 3)},
+		{"huge section count", with(wtns, 8, 0xffffffff)},
+		{"truncated section", wtns[:len(wtns)-1]},
+		{"unknown section", encodeWTNS(2, wtnsSection{1, hdr},
+			wtnsSection{2, data}, wtnsSection{3, nil})},
+		{"duplicate section", encodeWTNS(2, wtnsSection{1, hdr},
+			wtnsSection{1, hdr}, wtnsSection{2, data})},
+		{"header missing", encodeWTNS(2, wtnsSection{2, data})},
+		{"data missing", encodeWTNS(2, wtnsSection{1, hdr})},
+		{"empty header", encodeWTNS(2, wtnsSection{1, hdr[:3]},
+			wtnsSection{2, data})},
+		{"n8 0", encodeWTNS(2, wtnsSection{1, with(hdr, 0, 0)},
+			wtnsSection{2, data})},
+		{"n8 30", encodeWTNS(2, wtnsSection{1, with(hdr, 0, 30)},
+			wtnsSection{2, data})},
+		{"long header", encodeWTNS(2,
+			wtnsSection{1, append(append([]byte{}, hdr...), 0)},
+			wtnsSection{2, data})},
+		{"short header", encodeWTNS(2, wtnsSection{1, hdr[:len(hdr)-1]},
+			wtnsSection{2, data})},
+		{"prime 1", encodeWTNS(2, wtnsSection{1, primeOne},
+			wtnsSection{2, data})},
+		{"data size", encodeWTNS(2, wtnsSection{1, hdr},
+			wtnsSection{2, data[:len(data)-1]})},
+		{"signal not in field", encodeWTNS(2, wtnsSection{1, hdr},
+			wtnsSection{2, append(append([]byte{}, data[:5*32]...),
+				prime...)})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := witness.ParseWTNS(tc.wtns)
+			require.Error(t, err)
+			_, err = prover.Groth16Prover(zkey, tc.wtns)
+			require.ErrorIs(t, err, prover.ErrInvalidWitness)
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The binary encoding of a Groth16 proof on the bn128 curve is the
+// compressed points A, B and C, 128 bytes, as gnark-crypto compresses them:
+// the x coordinate, for G2 the imaginary part first, with two flags in the
+// most significant bits of the first byte, the point at infinity or whether
+// y is the lexicographically largest of y and -y. The binary encoding of a
+// ZKProof follows the proof with the number of public signals as a 4-byte
+// big-endian number and the public signals, 32 bytes each.
+//
+// The types module does not depend on the curve implementation of the
+// verifier, so the points are decompressed here with math/big. The points of
+// G2 are only checked to be on the curve: the verifier checks that they are
+// in G2.
+const (
+	compressedMask     = 0b11 << 6
+	compressedSmallest = 0b10 << 6
+	compressedLargest  = 0b11 << 6
+	compressedInfinity = 0b01 << 6
+
+	// ProofBinarySize is the size of the binary encoding of ProofData.
+	ProofBinarySize = 32 + 64 + 32
+)
+
+var (
+	// fieldP is the modulus of the base field of bn128.
+	fieldP, _ = new(big.Int).SetString(
+		"21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	// halfP is (p-1)/2: y is the largest of y and -y if it is greater.
+	halfP = new(big.Int).Rsh(fieldP, 1)
+	// twistB is 3/(i+9), the b coefficient of the twist, as c0, c1.
+	twistB = [2]*big.Int{
+		bigFromBase10("19485874751759354771024239261021720505790618469301721065564631296452457478373"),
+		bigFromBase10("266929791119991161246907387137283842545076965332900288569378510910307636690"),
+	}
+)
+
+func bigFromBase10(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+// MarshalBinary encodes a Groth16 proof on the bn128 curve into 128 bytes.
+func (p ProofData) MarshalBinary() ([]byte, error) {
+	if p.Protocol != "" && p.Protocol != "groth16" {
+		return nil, fmt.Errorf("proof protocol is not groth16: %v", p.Protocol)
+	}
+	if p.Curve != "" && p.Curve != "bn128" {
+		return nil, fmt.Errorf("proof curve is not bn128: %v", p.Curve)
+	}
+
+	a, err := callDataAffine(p.A, "pi_a")
+	if err != nil {
+		return nil, err
+	}
+	b, err := callDataAffineG2(p.B, "pi_b")
+	if err != nil {
+		return nil, err
+	}
+	c, err := callDataAffine(p.C, "pi_c")
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, ProofBinarySize)
+	if err = compressG1(data[:32], a); err != nil {
+		return nil, fmt.Errorf("invalid pi_a: %w", err)
+	}
+	if err = compressG2(data[32:96], b); err != nil {
+		return nil, fmt.Errorf("invalid pi_b: %w", err)
+	}
+	if err = compressG1(data[96:], c); err != nil {
+		return nil, fmt.Errorf("invalid pi_c: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes the output of ProofData.MarshalBinary.
+func (p *ProofData) UnmarshalBinary(data []byte) error {
+	if len(data) != ProofBinarySize {
+		return fmt.Errorf("invalid binary proof length: %v", len(data))
+	}
+
+	a, err := decompressG1(data[:32])
+	if err != nil {
+		return fmt.Errorf("invalid pi_a: %w", err)
+	}
+	b, err := decompressG2(data[32:96])
+	if err != nil {
+		return fmt.Errorf("invalid pi_b: %w", err)
+	}
+	c, err := decompressG1(data[96:])
+	if err != nil {
+		return fmt.Errorf("invalid pi_c: %w", err)
+	}
+
+	*p = ProofData{
+		A:        a,
+		B:        b,
+		C:        c,
+		Protocol: "groth16",
+		Curve:    "bn128",
+	}
+	return nil
+}
+
+// MarshalBinary encodes a Groth16 proof on the bn128 curve and its public
+// signals into 132 bytes plus 32 bytes per public signal.
+func (p ZKProof) MarshalBinary() ([]byte, error) {
+	if p.Proof == nil {
+		return nil, errors.New("proof is empty")
+	}
+	proof, err := p.Proof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, ProofBinarySize+4, ProofBinarySize+4+
+		32*len(p.PubSignals))
+	copy(data, proof)
+	binary.BigEndian.PutUint32(data[ProofBinarySize:], uint32(len(p.PubSignals)))
+	for i, s := range p.PubSignals {
+		w, err := callDataWord(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public signal %v: %w", i, err)
+		}
+		var b [32]byte
+		data = append(data, w.FillBytes(b[:])...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes the output of ZKProof.MarshalBinary.
+func (p *ZKProof) UnmarshalBinary(data []byte) error {
+	if len(data) < ProofBinarySize+4 {
+		return fmt.Errorf("invalid binary proof length: %v", len(data))
+	}
+	n := binary.BigEndian.Uint32(data[ProofBinarySize:])
+	if uint64(len(data)) != ProofBinarySize+4+32*uint64(n) {
+		return fmt.Errorf("invalid binary proof length %v for %v public "+
+			"signals", len(data), n)
+	}
+
+	var proof ProofData
+	if err := proof.UnmarshalBinary(data[:ProofBinarySize]); err != nil {
+		return err
+	}
+	pubSignals := make([]string, n)
+	for i := range pubSignals {
+		off := ProofBinarySize + 4 + 32*i
+		pubSignals[i] = new(big.Int).SetBytes(data[off : off+32]).String()
+	}
+
+	*p = ZKProof{Proof: &proof, PubSignals: pubSignals}
+	return nil
+}
+
+// compressG1 writes the compressed point (x, y) to m.
+func compressG1(m []byte, xy [2]*big.Int) error {
+	if xy[0].Cmp(fieldP) >= 0 || xy[1].Cmp(fieldP) >= 0 {
+		return errors.New("coordinate exceeds modulus")
+	}
+	if xy[0].Sign() == 0 && xy[1].Sign() == 0 {
+		m[0] = compressedInfinity
+		return nil
+	}
+
+	xy[0].FillBytes(m)
+	if xy[1].Cmp(halfP) > 0 {
+		m[0] |= compressedLargest
+	} else {
+		m[0] |= compressedSmallest
+	}
+	return nil
+}
+
+// compressG2 writes the compressed point (x, y) to m, the coordinates being
+// x.c1, x.c0, y.c1, y.c0.
+func compressG2(m []byte, xy [4]*big.Int) error {
+	for _, c := range xy {
+		if c.Cmp(fieldP) >= 0 {
+			return errors.New("coordinate exceeds modulus")
+		}
+	}
+	if xy[0].Sign() == 0 && xy[1].Sign() == 0 && xy[2].Sign() == 0 &&
+		xy[3].Sign() == 0 {
+		m[0] = compressedInfinity
+		return nil
+	}
+
+	xy[0].FillBytes(m[:32])
+	xy[1].FillBytes(m[32:])
+	if isLargestFp2(xy[3], xy[2]) {
+		m[0] |= compressedLargest
+	} else {
+		m[0] |= compressedSmallest
+	}
+	return nil
+}
+
+// compressedX returns the x coordinate of the compressed point m, split in
+// 32-byte big-endian numbers, and its flags.
+func compressedX(m []byte) ([]*big.Int, byte, error) {
+	flags := m[0] & compressedMask
+	buf := make([]byte, len(m))
+	copy(buf, m)
+	buf[0] &^= compressedMask
+
+	x := make([]*big.Int, len(m)/32)
+	for i := range x {
+		x[i] = new(big.Int).SetBytes(buf[i*32 : (i+1)*32])
+		if x[i].Cmp(fieldP) >= 0 {
+			return nil, 0, errors.New("coordinate exceeds modulus")
+		}
+	}
+
+	switch flags {
+	case compressedSmallest, compressedLargest:
+	case compressedInfinity:
+		for _, c := range x {
+			if c.Sign() != 0 {
+				return nil, 0, errors.New("malformed point at infinity")
+			}
+		}
+	default:
+		return nil, 0, errors.New("point is not compressed")
+	}
+	return x, flags, nil
+}
+
+// decompressG1 decodes a compressed point of G1 into the snarkjs format.
+func decompressG1(m []byte) ([]string, error) {
+	x, flags, err := compressedX(m)
+	if err != nil {
+		return nil, err
+	}
+	if flags == compressedInfinity {
+		return []string{"0", "1", "0"}, nil
+	}
+
+	// y² = x³ + 3
+	y := new(big.Int).Mul(x[0], x[0])
+	y.Mul(y, x[0]).Add(y, big.NewInt(3)).Mod(y, fieldP)
+	if y.ModSqrt(y, fieldP) == nil {
+		return nil, errors.New("point is not on the curve")
+	}
+	if (y.Cmp(halfP) > 0) != (flags == compressedLargest) {
+		y.Sub(fieldP, y)
+	}
+	return []string{x[0].String(), y.String(), "1"}, nil
+}
+
+// decompressG2 decodes a compressed point of G2 into the snarkjs format.
+func decompressG2(m []byte) ([][]string, error) {
+	x, flags, err := compressedX(m)
+	if err != nil {
+		return nil, err
+	}
+	if flags == compressedInfinity {
+		return [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}, nil
+	}
+
+	// y² = x³ + 3/(i+9)
+	x0, x1 := x[1], x[0]
+	y0, y1 := fp2Mul(x0, x1, x0, x1)
+	y0, y1 = fp2Mul(y0, y1, x0, x1)
+	y0.Add(y0, twistB[0]).Mod(y0, fieldP)
+	y1.Add(y1, twistB[1]).Mod(y1, fieldP)
+	y0, y1, ok := fp2Sqrt(y0, y1)
+	if !ok {
+		return nil, errors.New("point is not on the curve")
+	}
+	if isLargestFp2(y0, y1) != (flags == compressedLargest) {
+		if y0.Sign() != 0 {
+			y0.Sub(fieldP, y0)
+		}
+		if y1.Sign() != 0 {
+			y1.Sub(fieldP, y1)
+		}
+	}
+	return [][]string{
+		{x0.String(), x1.String()},
+		{y0.String(), y1.String()},
+		{"1", "0"},
+	}, nil
+}
+
+// isLargestFp2 reports whether y0 + y1·i is lexicographically greater than
+// its opposite: y1 is compared first, unless it is zero.
+func isLargestFp2(y0, y1 *big.Int) bool {
+	if y1.Sign() == 0 {
+		return y0.Cmp(halfP) > 0
+	}
+	return y1.Cmp(halfP) > 0
+}
+
+// fp2Mul returns (a0 + a1·i)(b0 + b1·i) in GF(p²).
+func fp2Mul(a0, a1, b0, b1 *big.Int) (*big.Int, *big.Int) {
+	c0 := new(big.Int).Mul(a0, b0)
+	c0.Sub(c0, new(big.Int).Mul(a1, b1)).Mod(c0, fieldP)
+	c1 := new(big.Int).Mul(a0, b1)
+	c1.Add(c1, new(big.Int).Mul(a1, b0)).Mod(c1, fieldP)
+	return c0, c1
+}
+
+// fp2Sqrt returns a square root of a0 + a1·i in GF(p²), if it is a square.
+// If a0 + a1·i is the square of c0 + c1·i, then c0² = (a0 ± √(a0²+a1²))/2,
+// only one of them being a square when a1 ≠ 0 as -1 is not a square, and
+// c1 = a1/2c0.
+func fp2Sqrt(a0, a1 *big.Int) (*big.Int, *big.Int, bool) {
+	if a1.Sign() == 0 {
+		if s := new(big.Int).ModSqrt(a0, fieldP); s != nil {
+			return s, new(big.Int), true
+		}
+		// (s·i)² = -s²
+		neg := new(big.Int).Sub(fieldP, a0)
+		if s := new(big.Int).ModSqrt(neg, fieldP); s != nil {
+			return new(big.Int), s, true
+		}
+		return nil, nil, false
+	}
+
+	n := new(big.Int).Mul(a0, a0)
+	n.Add(n, new(big.Int).Mul(a1, a1)).Mod(n, fieldP)
+	if n.ModSqrt(n, fieldP) == nil {
+		return nil, nil, false
+	}
+
+	half := new(big.Int).Add(halfP, big.NewInt(1))
+	t := new(big.Int).Add(a0, n)
+	t.Mul(t, half).Mod(t, fieldP)
+	c0 := new(big.Int).ModSqrt(t, fieldP)
+	if c0 == nil {
+		t.Sub(a0, n).Mul(t, half).Mod(t, fieldP)
+		if c0 = new(big.Int).ModSqrt(t, fieldP); c0 == nil {
+			return nil, nil, false
+		}
+	}
+
+	c1 := new(big.Int).Lsh(c0, 1)
+	c1.ModInverse(c1, fieldP)
+	c1.Mul(c1, a1).Mod(c1, fieldP)
+	return c0, c1, true
+}
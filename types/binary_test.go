@@ -0,0 +1,136 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// binaryProof is the compressed proof of testdata/proof.json, as encoded by
+// gnark-crypto.
+const binaryProof = "" +
+	"efa21b6592e3ef463d9050d3ae519c5887c2c70d09343e1da6b491db035a8baf" +
+	"d9f57cc501e88e4c129c233bb8a173c6cc50b9e0e7a8fc4e714026b7b7797ee3" +
+	"1fb37c67f23c6d0392d3b3b029df44aea70fc53630ae3dbf6b7bae7f4c570c33" +
+	"900daf7c33f9789038503d467f6eb39ea64846ce88a62db24b4ade31f176a110"
+
+func TestZKProofBinary(t *testing.T) {
+	zkProof := readTestZKProof(t)
+
+	data, err := zkProof.Proof.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, binaryProof, hex.EncodeToString(data))
+
+	data, err = zkProof.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, binaryProof+"00000002"+
+		"000000000000000000000000000000000000000000000000000000000000018c"+
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		hex.EncodeToString(data))
+
+	var got ZKProof
+	require.NoError(t, got.UnmarshalBinary(data))
+	zkProof.Proof.Curve = "bn128"
+	require.Equal(t, zkProof, got)
+
+	// the points at infinity and the other signs of y
+	zkProof.Proof.A = []string{"0", "1", "0"}
+	zkProof.Proof.B = [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	zkProof.Proof.C[1] = "19358387431968041046570509719761527310631341643121489788198172497323023467862"
+	zkProof.PubSignals = nil
+	data, err = zkProof.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, ProofBinarySize+4)
+	require.Equal(t, byte(compressedInfinity), data[0])
+	require.Equal(t, byte(compressedInfinity), data[32])
+	require.Equal(t, byte(compressedLargest), data[96]&compressedMask)
+	require.NoError(t, got.UnmarshalBinary(data))
+	zkProof.PubSignals = []string{}
+	require.Equal(t, zkProof, got)
+
+	proof := readTestZKProof(t).Proof
+	proof.B[1][0] = "9057795219780061022112771926624321019711168827290041614296759913308508872006"
+	proof.B[1][1] = "8914914857368966980087180250306520340740687453914739979996398748544111108105"
+	data, err = proof.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, byte(compressedSmallest), data[32]&compressedMask)
+	var gotProof ProofData
+	require.NoError(t, gotProof.UnmarshalBinary(data))
+	proof.Curve = "bn128"
+	require.Equal(t, *proof, gotProof)
+}
+
+func TestZKProofBinaryErrors(t *testing.T) {
+	zkProof := readTestZKProof(t)
+	zkProof.Proof.Curve = "bls12381"
+	_, err := zkProof.MarshalBinary()
+	require.EqualError(t, err, "proof curve is not bn128: bls12381")
+
+	zkProof = readTestZKProof(t)
+	zkProof.Proof.A[0] = fieldP.String()
+	_, err = zkProof.MarshalBinary()
+	require.EqualError(t, err, "invalid pi_a: coordinate exceeds modulus")
+
+	data, err := readTestZKProof(t).MarshalBinary()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		modify  func(d []byte) []byte
+		wantErr string
+	}{
+		{
+			name:    "short",
+			modify:  func(d []byte) []byte { return d[:ProofBinarySize+3] },
+			wantErr: "invalid binary proof length: 131",
+		},
+		{
+			name:    "missing public signal",
+			modify:  func(d []byte) []byte { return d[:len(d)-32] },
+			wantErr: "invalid binary proof length 164 for 2 public signals",
+		},
+		{
+			name: "uncompressed pi_a",
+			modify: func(d []byte) []byte {
+				d[0] &^= compressedMask
+				return d
+			},
+			wantErr: "invalid pi_a: point is not compressed",
+		},
+		{
+			name: "pi_c not on the curve",
+			modify: func(d []byte) []byte {
+				// x = 4
+				copy(d[96:128], make([]byte, 32))
+				d[96] = compressedSmallest
+				d[127] = 4
+				return d
+			},
+			wantErr: "invalid pi_c: point is not on the curve",
+		},
+		{
+			name: "pi_b infinity with x",
+			modify: func(d []byte) []byte {
+				d[32] = compressedInfinity
+				return d
+			},
+			wantErr: "invalid pi_b: malformed point at infinity",
+		},
+		{
+			name: "pi_b exceeds modulus",
+			modify: func(d []byte) []byte {
+				d[32] = 0xff
+				return d
+			},
+			wantErr: "invalid pi_b: coordinate exceeds modulus",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.modify(append([]byte{}, data...))
+			var got ZKProof
+			require.EqualError(t, got.UnmarshalBinary(d), tc.wantErr)
+		})
+	}
+}
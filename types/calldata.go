@@ -0,0 +1,216 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// SolidityCallData returns the arguments of the verifyProof function of a
+// Solidity Groth16 verifier for the proof, the same string as `snarkjs zkey
+// export soliditycalldata` (generatecall) prints. The coordinates of pi_b are
+// swapped to the c1, c0 order of the EVM.
+func (p ZKProof) SolidityCallData() (string, error) {
+	words, err := p.callDataWords()
+	if err != nil {
+		return "", err
+	}
+
+	hex := make([]string, len(words))
+	for i, w := range words {
+		hex[i] = fmt.Sprintf(`"0x%064x"`, w)
+	}
+
+	return fmt.Sprintf("[%v, %v],[[%v, %v],[%v, %v]],[%v, %v],[%v]",
+		hex[0], hex[1], hex[2], hex[3], hex[4], hex[5], hex[6], hex[7],
+		strings.Join(hex[8:], ",")), nil
+}
+
+// VerifyProofCallData returns the ABI encoded call of
+// verifyProof(uint[2],uint[2][2],uint[2],uint[N]) of a Solidity Groth16
+// verifier for the proof, N being the number of public signals.
+func (p ZKProof) VerifyProofCallData() ([]byte, error) {
+	words, err := p.callDataWords()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 4, 4+len(words)*32)
+	copy(data, verifyProofSelector(len(p.PubSignals)))
+	for _, w := range words {
+		var b [32]byte
+		data = append(data, w.FillBytes(b[:])...)
+	}
+	return data, nil
+}
+
+// ParseVerifyProofCallData is the inverse of ZKProof.VerifyProofCallData:
+// it parses the ABI encoded call of verifyProof into a Groth16 proof on the
+// bn128 curve.
+func ParseVerifyProofCallData(data []byte) (ZKProof, error) {
+	if len(data) < 4+8*32 || (len(data)-4)%32 != 0 {
+		return ZKProof{}, fmt.Errorf("invalid calldata length: %v", len(data))
+	}
+	nPublic := (len(data)-4)/32 - 8
+	if !bytes.Equal(data[:4], verifyProofSelector(nPublic)) {
+		return ZKProof{}, errors.New("calldata is not a call of verifyProof")
+	}
+
+	words := make([]string, (len(data)-4)/32)
+	for i := range words {
+		words[i] = new(big.Int).SetBytes(data[4+i*32 : 4+(i+1)*32]).String()
+	}
+
+	return ZKProof{
+		Proof: &ProofData{
+			A:        callDataG1(words[0], words[1]),
+			B:        callDataG2(words[2], words[3], words[4], words[5]),
+			C:        callDataG1(words[6], words[7]),
+			Protocol: "groth16",
+			Curve:    "bn128",
+		},
+		PubSignals: words[8:],
+	}, nil
+}
+
+// verifyProofSelector returns the function selector of
+// verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[nPublic]).
+func verifyProofSelector(nPublic int) []byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = fmt.Fprintf(h,
+		"verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[%v])", nPublic)
+	return h.Sum(nil)[:4]
+}
+
+// callDataWords returns the arguments of verifyProof: the affine
+// coordinates of pi_a, pi_b, with c1 before c0, and pi_c followed by the
+// public signals.
+func (p ZKProof) callDataWords() ([]*big.Int, error) {
+	if p.Proof == nil {
+		return nil, errors.New("proof is empty")
+	}
+
+	a, err := callDataAffine(p.Proof.A, "pi_a")
+	if err != nil {
+		return nil, err
+	}
+	b, err := callDataAffineG2(p.Proof.B, "pi_b")
+	if err != nil {
+		return nil, err
+	}
+	c, err := callDataAffine(p.Proof.C, "pi_c")
+	if err != nil {
+		return nil, err
+	}
+
+	words := []*big.Int{a[0], a[1]}
+	words = append(words, b[:]...)
+	words = append(words, c[0], c[1])
+
+	for i, s := range p.PubSignals {
+		w, err := callDataWord(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public signal %v: %w", i, err)
+		}
+		words = append(words, w)
+	}
+	return words, nil
+}
+
+// callDataAffine returns the affine coordinates of a G1 point in the
+// snarkjs format, which must have z = 1 or be the point at infinity.
+func callDataAffine(h []string, name string) ([2]*big.Int, error) {
+	var xy [2]*big.Int
+	if len(h) != 3 {
+		return xy, fmt.Errorf("invalid %v: G1 point must have 3 "+
+			"coordinates, got %v", name, len(h))
+	}
+
+	var c [3]*big.Int
+	for i := range h {
+		var err error
+		c[i], err = callDataWord(h[i])
+		if err != nil {
+			return xy, fmt.Errorf("invalid %v: %w", name, err)
+		}
+	}
+
+	switch {
+	case c[2].Cmp(big.NewInt(1)) == 0:
+		xy[0], xy[1] = c[0], c[1]
+	case c[2].Sign() == 0:
+		// the point at infinity is (0, 0) in the EVM
+		xy[0], xy[1] = new(big.Int), new(big.Int)
+	default:
+		return xy, fmt.Errorf("invalid %v: point is not affine", name)
+	}
+	return xy, nil
+}
+
+// callDataAffineG2 returns the affine coordinates of a G2 point in the
+// snarkjs format, which must have z = 1 or be the point at infinity, in the
+// order of the EVM: x.c1, x.c0, y.c1, y.c0.
+func callDataAffineG2(h [][]string, name string) ([4]*big.Int, error) {
+	var xy [4]*big.Int
+	if len(h) != 3 {
+		return xy, fmt.Errorf("invalid %v: G2 point must have 3 "+
+			"coordinates, got %v", name, len(h))
+	}
+
+	var c [3][2]*big.Int
+	for i := range h {
+		if len(h[i]) != 2 {
+			return xy, fmt.Errorf("invalid %v: coordinate %v must have 2 "+
+				"elements, got %v", name, i, len(h[i]))
+		}
+		for j := range h[i] {
+			var err error
+			c[i][j], err = callDataWord(h[i][j])
+			if err != nil {
+				return xy, fmt.Errorf("invalid %v: %w", name, err)
+			}
+		}
+	}
+
+	switch {
+	case c[2][0].Cmp(big.NewInt(1)) == 0 && c[2][1].Sign() == 0:
+		xy = [4]*big.Int{c[0][1], c[0][0], c[1][1], c[1][0]}
+	case c[2][0].Sign() == 0 && c[2][1].Sign() == 0:
+		// the point at infinity is (0, 0) in the EVM
+		xy = [4]*big.Int{new(big.Int), new(big.Int), new(big.Int),
+			new(big.Int)}
+	default:
+		return xy, fmt.Errorf("invalid %v: point is not affine", name)
+	}
+	return xy, nil
+}
+
+// callDataWord parses a decimal number that fits in a uint256.
+func callDataWord(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("can not parse number: %v", s)
+	}
+	if n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("number does not fit in uint256: %v", s)
+	}
+	return n, nil
+}
+
+func callDataG1(x, y string) []string {
+	if x == "0" && y == "0" {
+		return []string{"0", "1", "0"}
+	}
+	return []string{x, y, "1"}
+}
+
+func callDataG2(x1, x0, y1, y0 string) [][]string {
+	if x1 == "0" && x0 == "0" && y1 == "0" && y0 == "0" {
+		return [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return [][]string{{x0, x1}, {y0, y1}, {"1", "0"}}
+}
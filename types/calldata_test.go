@@ -0,0 +1,125 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readTestZKProof(t *testing.T) ZKProof {
+	proofJSON, err := os.ReadFile("testdata/proof.json")
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile("testdata/public.json")
+	require.NoError(t, err)
+
+	var zkProof ZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+func TestSolidityCallData(t *testing.T) {
+	// calldata.txt was not written by snarkjs. A script formatted it from
+	// proof.json and public.json with the string template of
+	// groth16_exportsoliditycalldata of snarkjs 0.7, which prints the output
+	// of "snarkjs zkey export soliditycalldata public.json proof.json".
+	want, err := os.ReadFile("testdata/calldata.txt")
+	require.NoError(t, err)
+
+	callData, err := readTestZKProof(t).SolidityCallData()
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(string(want)), callData)
+}
+
+func TestVerifyProofCallData(t *testing.T) {
+	zkProof := readTestZKProof(t)
+	data, err := zkProof.VerifyProofCallData()
+	require.NoError(t, err)
+	require.Len(t, data, 4+10*32)
+	require.Equal(t, "f5c9d69e", hex.EncodeToString(data[:4]))
+	// the selector of the verifiers with one public input
+	require.Equal(t, "43753b4d", hex.EncodeToString(verifyProofSelector(1)))
+
+	// the arguments are the words of generatecall
+	callData, err := zkProof.SolidityCallData()
+	require.NoError(t, err)
+	var words []string
+	for _, w := range strings.Split(callData, `"`) {
+		if strings.HasPrefix(w, "0x") {
+			words = append(words, w[2:])
+		}
+	}
+	require.Equal(t, strings.Join(words, ""), hex.EncodeToString(data[4:]))
+
+	got, err := ParseVerifyProofCallData(data)
+	require.NoError(t, err)
+	zkProof.Proof.Curve = "bn128"
+	require.Equal(t, zkProof, got)
+
+	// the point at infinity is (0, 0)
+	zkProof.Proof.C = []string{"0", "1", "0"}
+	data, err = zkProof.VerifyProofCallData()
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 64), data[4+6*32:4+8*32])
+	got, err = ParseVerifyProofCallData(data)
+	require.NoError(t, err)
+	require.Equal(t, zkProof, got)
+}
+
+func TestVerifyProofCallDataErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(p *ZKProof)
+		wantErr string
+	}{
+		{
+			name:    "empty proof",
+			modify:  func(p *ZKProof) { p.Proof = nil },
+			wantErr: "proof is empty",
+		},
+		{
+			name:    "projective pi_a",
+			modify:  func(p *ZKProof) { p.Proof.A[2] = "2" },
+			wantErr: "invalid pi_a: point is not affine",
+		},
+		{
+			name:    "projective pi_b",
+			modify:  func(p *ZKProof) { p.Proof.B[2] = []string{"1", "1"} },
+			wantErr: "invalid pi_b: point is not affine",
+		},
+		{
+			name:    "malformed pi_c",
+			modify:  func(p *ZKProof) { p.Proof.C = p.Proof.C[:2] },
+			wantErr: "invalid pi_c: G1 point must have 3 coordinates, got 2",
+		},
+		{
+			name: "public signal out of range",
+			modify: func(p *ZKProof) {
+				p.PubSignals[1] = "-1"
+			},
+			wantErr: "invalid public signal 1: number does not fit in " +
+				"uint256: -1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zkProof := readTestZKProof(t)
+			tc.modify(&zkProof)
+			_, err := zkProof.VerifyProofCallData()
+			require.EqualError(t, err, tc.wantErr)
+			_, err = zkProof.SolidityCallData()
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+
+	data, err := readTestZKProof(t).VerifyProofCallData()
+	require.NoError(t, err)
+	_, err = ParseVerifyProofCallData(data[:len(data)-1])
+	require.EqualError(t, err, "invalid calldata length: 323")
+	_, err = ParseVerifyProofCallData(data[:len(data)-32])
+	require.EqualError(t, err, "calldata is not a call of verifyProof")
+}
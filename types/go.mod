@@ -1,3 +1,15 @@
 module github.com/iden3/go-rapidsnark/types
 
 go 1.18
+
+require (
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/crypto v0.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -6,6 +6,9 @@ type ProofData struct {
 	B        [][]string `json:"pi_b"`
 	C        []string   `json:"pi_c"`
 	Protocol string     `json:"protocol"`
+	// Curve is the curve of the proof as named by snarkjs. rapidsnark does
+	// not set it, so it is omitted when empty to keep its JSON output.
+	Curve string `json:"curve,omitempty"`
 }
 
 // ZKProof is proof data with public signals
@@ -13,3 +16,74 @@ type ZKProof struct {
 	Proof      *ProofData `json:"proof"`
 	PubSignals []string   `json:"pub_signals"`
 }
+
+// PlonkProofData is structure that represents SnarkJS library result of
+// PLONK proof generation
+type PlonkProofData struct {
+	A        []string `json:"A"`
+	B        []string `json:"B"`
+	C        []string `json:"C"`
+	Z        []string `json:"Z"`
+	T1       []string `json:"T1"`
+	T2       []string `json:"T2"`
+	T3       []string `json:"T3"`
+	Wxi      []string `json:"Wxi"`
+	Wxiw     []string `json:"Wxiw"`
+	EvalA    string   `json:"eval_a"`
+	EvalB    string   `json:"eval_b"`
+	EvalC    string   `json:"eval_c"`
+	EvalS1   string   `json:"eval_s1"`
+	EvalS2   string   `json:"eval_s2"`
+	EvalZw   string   `json:"eval_zw"`
+	Protocol string   `json:"protocol"`
+	Curve    string   `json:"curve"`
+}
+
+// PlonkZKProof is PLONK proof data with public signals
+type PlonkZKProof struct {
+	Proof      *PlonkProofData `json:"proof"`
+	PubSignals []string        `json:"pub_signals"`
+}
+
+// FflonkPolynomials are the commitments of a SnarkJS fflonk proof
+type FflonkPolynomials struct {
+	C1 []string `json:"C1"`
+	C2 []string `json:"C2"`
+	W1 []string `json:"W1"`
+	W2 []string `json:"W2"`
+}
+
+// FflonkEvaluations are the evaluations of a SnarkJS fflonk proof
+type FflonkEvaluations struct {
+	Ql  string `json:"ql"`
+	Qr  string `json:"qr"`
+	Qm  string `json:"qm"`
+	Qo  string `json:"qo"`
+	Qc  string `json:"qc"`
+	S1  string `json:"s1"`
+	S2  string `json:"s2"`
+	S3  string `json:"s3"`
+	A   string `json:"a"`
+	B   string `json:"b"`
+	C   string `json:"c"`
+	Z   string `json:"z"`
+	Zw  string `json:"zw"`
+	T1w string `json:"t1w"`
+	T2w string `json:"t2w"`
+	Inv string `json:"inv"`
+}
+
+// FflonkProofData is structure that represents SnarkJS library result of
+// fflonk proof generation
+type FflonkProofData struct {
+	Polynomials FflonkPolynomials `json:"polynomials"`
+	Evaluations FflonkEvaluations `json:"evaluations"`
+	Protocol    string            `json:"protocol"`
+	Curve       string            `json:"curve"`
+}
+
+// FflonkZKProof is fflonk proof data with public signals
+type FflonkZKProof struct {
+	Proof      *FflonkProofData `json:"proof"`
+	PubSignals []string         `json:"pub_signals"`
+}
@@ -0,0 +1,456 @@
+// Package aggregation aggregates Groth16 proofs of the same circuit on the
+// bn128 curve into a single proof of logarithmic size, in the way of
+// SnarkPack (Gailly, Maller and Nitulescu, https://eprint.iacr.org/2021/529).
+//
+// The proofs (A_i, B_i, C_i) are committed with pairing commitments under
+// an SRS of powers of two secrets. For a random r the aggregated proof
+// shows, with a TIPP and a MIPP argument folded by the same GIPA rounds,
+// that Z_AB = ∏ e(r^i·A_i, B_i) and Z_C = ∑ r^i·C_i, and the verifier
+// checks the Groth16 equation of all the proofs at once:
+//
+//	Z_AB = e(alpha, beta)^(∑ r^i)·e(∑ r^i·vkX_i, gamma)·e(Z_C, delta)
+//
+// The openings of the folded commitment keys are KZG proofs at a random
+// point. The number of proofs is padded to a power of two by repeating the
+// last proof.
+package aggregation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// transcriptLabel is the domain separator of the transcript.
+const transcriptLabel = "go-rapidsnark groth16 aggregation v1"
+
+// commitment is a pairing commitment: the pairing products with the
+// commitment keys of a and of b.
+type commitment [2]*bn256.GT
+
+// gipaRound has the cross terms of a round of the GIPA argument, the L
+// terms of the left half of the keys with the right half of the vectors
+// and the R terms of the other way round.
+type gipaRound struct {
+	zABL, zABR     *bn256.GT
+	zCL, zCR       *bn256.G1
+	comABL, comABR commitment
+	comCL, comCR   commitment
+}
+
+// Proof is an aggregation of Groth16 proofs.
+type Proof struct {
+	comAB  commitment
+	comC   commitment
+	zAB    *bn256.GT
+	zC     *bn256.G1
+	rounds []gipaRound
+	// the vectors and the commitment keys after the last round
+	finalA *bn256.G1
+	finalB *bn256.G2
+	finalC *bn256.G1
+	finalV [2]*bn256.G2
+	finalW [2]*bn256.G1
+	// the KZG openings of finalV and finalW
+	openV [2]*bn256.G2
+	openW [2]*bn256.G1
+}
+
+// aggregation is the statement of an aggregated proof: the padded public
+// inputs of the proofs and the verification key.
+type aggregation struct {
+	vk     *verifyingKey
+	inputs [][]*big.Int
+}
+
+// Aggregate aggregates Groth16 proofs of the verification key. The proofs
+// are not verified: the aggregated proof is valid only if all of them are.
+func Aggregate(srs *SRS, verificationKey []byte,
+	zkProofs []types.ZKProof) (*Proof, error) {
+
+	vk, err := parseVerifyingKey(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(zkProofs) == 0 {
+		return nil, errors.New("no proofs to aggregate")
+	}
+	n := paddedSize(len(zkProofs))
+	if n > srs.Size() {
+		return nil, fmt.Errorf("SRS is too small for %v proofs", n)
+	}
+
+	ag := aggregation{vk: vk, inputs: make([][]*big.Int, n)}
+	a := make([]*bn256.G1, n)
+	b := make([]*bn256.G2, n)
+	c := make([]*bn256.G1, n)
+	for i := range zkProofs {
+		a[i], b[i], c[i], err = parseProof(zkProofs[i].Proof)
+		if err == nil {
+			ag.inputs[i], err = vk.parseInputs(zkProofs[i].PubSignals)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("proof %v: %w", i, err)
+		}
+	}
+	for i := len(zkProofs); i < n; i++ {
+		a[i], b[i], c[i] = a[i-1], b[i-1], c[i-1]
+		ag.inputs[i] = ag.inputs[i-1]
+	}
+
+	// the commitment keys: v of G2 for A and C, w of G1 for B
+	v := [2][]*bn256.G2{append([]*bn256.G2(nil), srs.g2A[:n]...),
+		append([]*bn256.G2(nil), srs.g2B[:n]...)}
+	w := [2][]*bn256.G1{srs.g1A[n : 2*n], srs.g1B[n : 2*n]}
+
+	var p Proof
+	for k := 0; k < 2; k++ {
+		p.comAB[k] = bn256.MultiPair(append(a[:n:n], w[k]...),
+			append(v[k][:n:n], b...))
+		p.comC[k] = bn256.MultiPair(c, v[k])
+	}
+
+	t := newTranscript(transcriptLabel)
+	r := ag.challengeR(t, p.comAB, p.comC)
+
+	// A and C are multiplied by r^i and v by r^-i, which keeps the
+	// commitments
+	rInv := frInv(r)
+	ri, riInv := big.NewInt(1), big.NewInt(1)
+	for i := 0; i < n; i++ {
+		a[i] = new(bn256.G1).ScalarMult(a[i], ri)
+		c[i] = new(bn256.G1).ScalarMult(c[i], ri)
+		for k := 0; k < 2; k++ {
+			v[k][i] = new(bn256.G2).ScalarMult(v[k][i], riInv)
+		}
+		ri, riInv = frMul(ri, r), frMul(riInv, rInv)
+	}
+	// the scalars of the MIPP argument, Z_C = ∑ s_i·C_i
+	s := make([]*big.Int, n)
+	for i := range s {
+		s[i] = big.NewInt(1)
+	}
+
+	p.zAB = bn256.MultiPair(a, b)
+	p.zC = new(bn256.G1).MultiScalarMult(c, s)
+	t.addGT(p.zAB)
+	t.addG1(p.zC)
+
+	var xs []*big.Int
+	for m := n / 2; m >= 1; m /= 2 {
+		aL, aR := a[:m], a[m:]
+		bL, bR := b[:m], b[m:]
+		cL, cR := c[:m], c[m:]
+		sL, sR := s[:m], s[m:]
+
+		var round gipaRound
+		round.zABL = bn256.MultiPair(aR, bL)
+		round.zABR = bn256.MultiPair(aL, bR)
+		round.zCL = new(bn256.G1).MultiScalarMult(cR, sL)
+		round.zCR = new(bn256.G1).MultiScalarMult(cL, sR)
+		for k := 0; k < 2; k++ {
+			vL, vR := v[k][:m], v[k][m:]
+			wL, wR := w[k][:m], w[k][m:]
+			round.comABL[k] = bn256.MultiPair(append(aR[:m:m], wR...),
+				append(vL[:m:m], bL...))
+			round.comABR[k] = bn256.MultiPair(append(aL[:m:m], wL...),
+				append(vR[:m:m], bR...))
+			round.comCL[k] = bn256.MultiPair(cR, vL)
+			round.comCR[k] = bn256.MultiPair(cL, vR)
+		}
+		p.rounds = append(p.rounds, round)
+
+		x := round.challenge(t)
+		xInv := frInv(x)
+		xs = append(xs, x)
+
+		a = foldG1(aL, aR, x)
+		c = foldG1(cL, cR, x)
+		b = foldG2(bL, bR, xInv)
+		for k := 0; k < 2; k++ {
+			v[k] = foldG2(v[k][:m], v[k][m:], xInv)
+			w[k] = foldG1(w[k][:m], w[k][m:], x)
+		}
+		for i := 0; i < m; i++ {
+			s[i] = frAdd(sL[i], frMul(xInv, sR[i]))
+		}
+		s = s[:m]
+	}
+
+	p.finalA, p.finalB, p.finalC = a[0], b[0], c[0]
+	for k := 0; k < 2; k++ {
+		p.finalV[k], p.finalW[k] = v[k][0], w[k][0]
+	}
+	z := p.challengeZ(t)
+
+	// KZG openings of the folded keys at z
+	vPoly := keyPolynomial(vFactors(xs, rInv))
+	wPoly := append(make([]*big.Int, n), keyPolynomial(xs)...)
+	for i := 0; i < n; i++ {
+		wPoly[i] = new(big.Int)
+	}
+	vQuot, wQuot := quotient(vPoly, z), quotient(wPoly, z)
+	p.openV[0] = g2MultiScalarMult(srs.g2A[:len(vQuot)], vQuot)
+	p.openV[1] = g2MultiScalarMult(srs.g2B[:len(vQuot)], vQuot)
+	p.openW[0] = new(bn256.G1).MultiScalarMult(srs.g1A[:len(wQuot)], wQuot)
+	p.openW[1] = new(bn256.G1).MultiScalarMult(srs.g1B[:len(wQuot)], wQuot)
+
+	return &p, nil
+}
+
+// Verify verifies an aggregated proof of the Groth16 proofs with the public
+// signals pubSignals, in the order of the aggregation.
+func Verify(srs *VerifierSRS, verificationKey []byte, p *Proof,
+	pubSignals [][]string) error {
+
+	vk, err := parseVerifyingKey(verificationKey)
+	if err != nil {
+		return err
+	}
+	if len(pubSignals) == 0 {
+		return errors.New("no proofs to verify")
+	}
+	n := paddedSize(len(pubSignals))
+	if 1<<len(p.rounds) != n {
+		return fmt.Errorf("aggregated proof has %v rounds, want %v",
+			len(p.rounds), log2(n))
+	}
+
+	ag := aggregation{vk: vk, inputs: make([][]*big.Int, n)}
+	for i := range pubSignals {
+		ag.inputs[i], err = vk.parseInputs(pubSignals[i])
+		if err != nil {
+			return fmt.Errorf("proof %v: %w", i, err)
+		}
+	}
+	for i := len(pubSignals); i < n; i++ {
+		ag.inputs[i] = ag.inputs[i-1]
+	}
+
+	t := newTranscript(transcriptLabel)
+	r := ag.challengeR(t, p.comAB, p.comC)
+	t.addGT(p.zAB)
+	t.addG1(p.zC)
+
+	// the Groth16 equation of all the proofs
+	if !gtEqual(p.zAB, ag.groth16Target(r, p.zC)) {
+		return errors.New("invalid aggregated proof: groth16 equation")
+	}
+
+	// fold the statements of the TIPP and MIPP arguments
+	zAB, zC := p.zAB, p.zC
+	comAB, comC := p.comAB, p.comC
+	xs := make([]*big.Int, len(p.rounds))
+	s := big.NewInt(1)
+	for j := range p.rounds {
+		round := &p.rounds[j]
+		x := round.challenge(t)
+		xInv := frInv(x)
+		xs[j] = x
+
+		zAB = gtFold(zAB, round.zABL, round.zABR, x, xInv)
+		zC = new(bn256.G1).Add(zC, new(bn256.G1).MultiScalarMult(
+			[]*bn256.G1{round.zCL, round.zCR}, []*big.Int{x, xInv}))
+		for k := 0; k < 2; k++ {
+			comAB[k] = gtFold(comAB[k], round.comABL[k], round.comABR[k],
+				x, xInv)
+			comC[k] = gtFold(comC[k], round.comCL[k], round.comCR[k],
+				x, xInv)
+		}
+		s = frMul(s, frAdd(xInv, big.NewInt(1)))
+	}
+	z := p.challengeZ(t)
+
+	if !gtEqual(zAB, bn256.Pair(p.finalA, p.finalB)) {
+		return errors.New("invalid aggregated proof: inner pairing product")
+	}
+	if !bytes.Equal(zC.Marshal(),
+		new(bn256.G1).ScalarMult(p.finalC, s).Marshal()) {
+		return errors.New("invalid aggregated proof: multi-exponentiation")
+	}
+	for k := 0; k < 2; k++ {
+		if !gtEqual(comAB[k], bn256.MultiPair(
+			[]*bn256.G1{p.finalA, p.finalW[k]},
+			[]*bn256.G2{p.finalV[k], p.finalB})) {
+			return errors.New("invalid aggregated proof: commitment of A and B")
+		}
+		if !gtEqual(comC[k], bn256.Pair(p.finalC, p.finalV[k])) {
+			return errors.New("invalid aggregated proof: commitment of C")
+		}
+	}
+
+	// the folded keys are the evaluations of their polynomials at the
+	// secrets
+	vz := evalKeyPolynomial(vFactors(xs, frInv(r)), z)
+	wz := frMul(frExp(z, n), evalKeyPolynomial(xs, z))
+	secrets := [2]struct {
+		g *bn256.G1
+		h *bn256.G2
+	}{{srs.gA, srs.hA}, {srs.gB, srs.hB}}
+	negZ := frNeg(z)
+	for k := 0; k < 2; k++ {
+		// e(a·g - z·g, openV) = e(g, v - v(z)·h)
+		gz := new(bn256.G1).Add(secrets[k].g,
+			new(bn256.G1).ScalarMult(srs.g, negZ))
+		hv := new(bn256.G2).Add(p.finalV[k],
+			new(bn256.G2).ScalarMult(srs.h, frNeg(vz)))
+		if !bn256.PairingCheck(
+			[]*bn256.G1{gz, new(bn256.G1).Neg(srs.g)},
+			[]*bn256.G2{p.openV[k], hv}) {
+			return errors.New("invalid aggregated proof: opening of v")
+		}
+
+		// e(openW, a·h - z·h) = e(w - w(z)·g, h)
+		hz := new(bn256.G2).Add(secrets[k].h,
+			new(bn256.G2).ScalarMult(srs.h, negZ))
+		gw := new(bn256.G1).Add(p.finalW[k],
+			new(bn256.G1).ScalarMult(srs.g, frNeg(wz)))
+		if !bn256.PairingCheck(
+			[]*bn256.G1{p.openW[k], new(bn256.G1).Neg(gw)},
+			[]*bn256.G2{hz, srs.h}) {
+			return errors.New("invalid aggregated proof: opening of w")
+		}
+	}
+	return nil
+}
+
+// challengeR adds the statement and the commitments to the transcript and
+// returns the challenge r.
+func (ag *aggregation) challengeR(t *transcript, comAB,
+	comC commitment) *big.Int {
+
+	ag.vk.addTo(t)
+	t.addScalar(big.NewInt(int64(len(ag.inputs))))
+	for _, inputs := range ag.inputs {
+		for _, in := range inputs {
+			t.addScalar(in)
+		}
+	}
+	for k := 0; k < 2; k++ {
+		t.addGT(comAB[k])
+		t.addGT(comC[k])
+	}
+	return t.challenge()
+}
+
+// groth16Target returns the right side of the Groth16 equation of all the
+// proofs combined with the powers of r:
+//
+//	e(alpha, beta)^(∑ r^i)·e(∑ r^i·vkX_i, gamma)·e(Z_C, delta)
+func (ag *aggregation) groth16Target(r *big.Int, zC *bn256.G1) *bn256.GT {
+	// icScalars[0] is ∑ r^i, the scalar of IC[0] and alpha
+	icScalars := make([]*big.Int, len(ag.vk.ic))
+	for j := range icScalars {
+		icScalars[j] = new(big.Int)
+	}
+	ri := big.NewInt(1)
+	for _, inputs := range ag.inputs {
+		icScalars[0] = frAdd(icScalars[0], ri)
+		for j, in := range inputs {
+			icScalars[j+1] = frAdd(icScalars[j+1], frMul(ri, in))
+		}
+		ri = frMul(ri, r)
+	}
+	vkX := new(bn256.G1).MultiScalarMult(ag.vk.ic, icScalars)
+	alpha := new(bn256.G1).ScalarMult(ag.vk.alpha, icScalars[0])
+	return bn256.MultiPair([]*bn256.G1{alpha, vkX, zC},
+		[]*bn256.G2{ag.vk.beta, ag.vk.gamma, ag.vk.delta})
+}
+
+// challenge adds the round to the transcript and returns its challenge.
+func (round *gipaRound) challenge(t *transcript) *big.Int {
+	t.addGT(round.zABL)
+	t.addGT(round.zABR)
+	t.addG1(round.zCL)
+	t.addG1(round.zCR)
+	for k := 0; k < 2; k++ {
+		t.addGT(round.comABL[k])
+		t.addGT(round.comABR[k])
+		t.addGT(round.comCL[k])
+		t.addGT(round.comCR[k])
+	}
+	return t.challenge()
+}
+
+// challengeZ adds the final vectors and keys to the transcript and returns
+// the point of the KZG openings.
+func (p *Proof) challengeZ(t *transcript) *big.Int {
+	t.addG1(p.finalA)
+	t.addG2(p.finalB)
+	t.addG1(p.finalC)
+	for k := 0; k < 2; k++ {
+		t.addG2(p.finalV[k])
+		t.addG1(p.finalW[k])
+	}
+	return t.challenge()
+}
+
+// vFactors returns the factors of the key polynomial of v rescaled by
+// r^-i: x_j^-1·r^-(n/2^(j+1)) for the challenge x_j of round j.
+func vFactors(xs []*big.Int, rInv *big.Int) []*big.Int {
+	n := 1 << len(xs)
+	f := make([]*big.Int, len(xs))
+	for j, x := range xs {
+		f[j] = frMul(frInv(x), frExp(rInv, n>>(j+1)))
+	}
+	return f
+}
+
+// foldG1 returns l[i] + x·r[i].
+func foldG1(l, r []*bn256.G1, x *big.Int) []*bn256.G1 {
+	f := make([]*bn256.G1, len(l))
+	for i := range f {
+		f[i] = new(bn256.G1).Add(l[i], new(bn256.G1).ScalarMult(r[i], x))
+	}
+	return f
+}
+
+// foldG2 returns l[i] + x·r[i].
+func foldG2(l, r []*bn256.G2, x *big.Int) []*bn256.G2 {
+	f := make([]*bn256.G2, len(l))
+	for i := range f {
+		f[i] = new(bn256.G2).Add(l[i], new(bn256.G2).ScalarMult(r[i], x))
+	}
+	return f
+}
+
+// g2MultiScalarMult returns ∑ scalars[i]·points[i].
+func g2MultiScalarMult(points []*bn256.G2, scalars []*big.Int) *bn256.G2 {
+	r := new(bn256.G2).ScalarBaseMult(new(big.Int))
+	for i := range points {
+		r.Add(r, new(bn256.G2).ScalarMult(points[i], scalars[i]))
+	}
+	return r
+}
+
+// gtFold returns e·l^x·r^xInv.
+func gtFold(e, l, r *bn256.GT, x, xInv *big.Int) *bn256.GT {
+	f := new(bn256.GT).ScalarMult(l, x)
+	f.Add(f, e)
+	return f.Add(f, new(bn256.GT).ScalarMult(r, xInv))
+}
+
+func gtEqual(a, b *bn256.GT) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+// paddedSize returns the smallest power of two not less than n.
+func paddedSize(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+func log2(n int) int {
+	l := 0
+	for 1<<l < n {
+		l++
+	}
+	return l
+}
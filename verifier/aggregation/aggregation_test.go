@@ -0,0 +1,245 @@
+package aggregation
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestData(t testing.TB) ([]byte, []types.ZKProof) {
+	vkJSON, err := os.ReadFile("../testdata/verification_key.json")
+	require.NoError(t, err)
+	proofsJSON, err := os.ReadFile("../testdata/proofs.json")
+	require.NoError(t, err)
+
+	var zkProofs []types.ZKProof
+	require.NoError(t, json.Unmarshal(proofsJSON, &zkProofs))
+
+	// more proofs of the circuit
+	for i := 0; len(zkProofs) < 8; i++ {
+		p, err := verifier.RerandomizeGroth16(zkProofs[i], vkJSON, rand.Reader)
+		require.NoError(t, err)
+		zkProofs = append(zkProofs, p)
+	}
+	return vkJSON, zkProofs
+}
+
+func pubSignals(zkProofs []types.ZKProof) [][]string {
+	s := make([][]string, len(zkProofs))
+	for i := range zkProofs {
+		s[i] = zkProofs[i].PubSignals
+	}
+	return s
+}
+
+func TestAggregate(t *testing.T) {
+	vkJSON, zkProofs := readTestData(t)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(t, err)
+
+	for _, n := range []int{1, 2, 3, 5, 8} {
+		p, err := Aggregate(srs, vkJSON, zkProofs[:n])
+		require.NoError(t, err)
+		require.NoError(t, Verify(srs.VerifierSRS(), vkJSON, p,
+			pubSignals(zkProofs[:n])), "%v proofs", n)
+	}
+}
+
+func TestAggregateInvalid(t *testing.T) {
+	vkJSON, zkProofs := readTestData(t)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(t, err)
+	vsrs := srs.VerifierSRS()
+
+	p, err := Aggregate(srs, vkJSON, zkProofs[:4])
+	require.NoError(t, err)
+
+	// wrong public signals
+	pub := pubSignals(zkProofs[:4])
+	pub[2] = []string{"396", "4"}
+	require.EqualError(t, Verify(vsrs, vkJSON, p, pub),
+		"invalid aggregated proof: groth16 equation")
+
+	require.EqualError(t, Verify(vsrs, vkJSON, p, pubSignals(zkProofs[:2])),
+		"aggregated proof has 2 rounds, want 1")
+
+	// an invalid proof
+	invalid := append([]types.ZKProof(nil), zkProofs[:4]...)
+	invalid[1].PubSignals = []string{"396", "4"}
+	p, err = Aggregate(srs, vkJSON, invalid)
+	require.NoError(t, err)
+	require.EqualError(t, Verify(vsrs, vkJSON, p, pubSignals(invalid)),
+		"invalid aggregated proof: groth16 equation")
+
+	// an SRS of other secrets
+	other, err := NewTestSRS(8, []byte("other"))
+	require.NoError(t, err)
+	p, err = Aggregate(other, vkJSON, zkProofs[:4])
+	require.NoError(t, err)
+	require.EqualError(t, Verify(vsrs, vkJSON, p, pubSignals(zkProofs[:4])),
+		"invalid aggregated proof: opening of v")
+}
+
+func TestAggregateTampered(t *testing.T) {
+	vkJSON, zkProofs := readTestData(t)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(t, err)
+	vsrs := srs.VerifierSRS()
+	pub := pubSignals(zkProofs[:4])
+
+	testCases := []struct {
+		name   string
+		modify func(p, q *Proof)
+	}{
+		{"zAB", func(p, q *Proof) { p.zAB = q.zAB }},
+		{"zC", func(p, q *Proof) { p.zC = q.zC }},
+		{"comAB", func(p, q *Proof) { p.comAB = q.comAB }},
+		{"round", func(p, q *Proof) { p.rounds[1] = q.rounds[1] }},
+		{"final A", func(p, q *Proof) { p.finalA = q.finalA }},
+		{"final B", func(p, q *Proof) { p.finalB = q.finalB }},
+		{"final C", func(p, q *Proof) { p.finalC = q.finalC }},
+		{"final v", func(p, q *Proof) { p.finalV = q.finalV }},
+		{"final w", func(p, q *Proof) { p.finalW = q.finalW }},
+		{"opening of v", func(p, q *Proof) { p.openV = q.openV }},
+		{"opening of w", func(p, q *Proof) { p.openW = q.openW }},
+	}
+	// q aggregates other proofs of the same statement
+	p, err := Aggregate(srs, vkJSON, zkProofs[:4])
+	require.NoError(t, err)
+	rerandomized := make([]types.ZKProof, 4)
+	for i := range rerandomized {
+		rerandomized[i], err = verifier.RerandomizeGroth16(zkProofs[i], vkJSON,
+			rand.Reader)
+		require.NoError(t, err)
+	}
+	q, err := Aggregate(srs, vkJSON, rerandomized)
+	require.NoError(t, err)
+	require.NoError(t, Verify(vsrs, vkJSON, q, pub))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tampered := *p
+			tampered.rounds = append([]gipaRound(nil), p.rounds...)
+			tc.modify(&tampered, q)
+			require.Error(t, Verify(vsrs, vkJSON, &tampered, pub))
+		})
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	vkJSON, zkProofs := readTestData(t)
+	srs, err := NewTestSRS(4, []byte("test"))
+	require.NoError(t, err)
+
+	_, err = Aggregate(srs, vkJSON, zkProofs[:5])
+	require.EqualError(t, err, "SRS is too small for 8 proofs")
+
+	_, err = Aggregate(srs, vkJSON, nil)
+	require.EqualError(t, err, "no proofs to aggregate")
+
+	invalid := append([]types.ZKProof(nil), zkProofs[:2]...)
+	invalid[1].PubSignals = []string{"396"}
+	_, err = Aggregate(srs, vkJSON, invalid)
+	require.EqualError(t, err, "proof 1: len(inputs)+1 != len(vk.IC)")
+
+	invalid[1] = types.ZKProof{PubSignals: zkProofs[1].PubSignals}
+	_, err = Aggregate(srs, vkJSON, invalid)
+	require.EqualError(t, err, "proof 1: proof is empty")
+
+	var vk map[string]interface{}
+	require.NoError(t, json.Unmarshal(vkJSON, &vk))
+	vk["curve"] = "bls12381"
+	blsVK, err := json.Marshal(vk)
+	require.NoError(t, err)
+	_, err = Aggregate(srs, blsVK, zkProofs[:2])
+	require.EqualError(t, err, "unsupported curve: bls12381")
+
+	_, err = NewTestSRS(6, []byte("test"))
+	require.EqualError(t, err, "SRS size must be a power of two")
+}
+
+func TestProofMarshal(t *testing.T) {
+	vkJSON, zkProofs := readTestData(t)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(t, err)
+
+	p, err := Aggregate(srs, vkJSON, zkProofs[:5])
+	require.NoError(t, err)
+	b, err := p.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, fixedSize+3*roundSize, len(b))
+
+	var q Proof
+	require.NoError(t, q.UnmarshalBinary(b))
+	require.NoError(t, Verify(srs.VerifierSRS(), vkJSON, &q,
+		pubSignals(zkProofs[:5])))
+	b2, err := q.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, b, b2)
+
+	require.EqualError(t, q.UnmarshalBinary(b[:len(b)-1]),
+		"invalid length of aggregated proof: 14915")
+	require.EqualError(t, q.UnmarshalBinary(b[:10]),
+		"aggregated proof is too short")
+
+	// an element of GT of other order
+	c := append([]byte(nil), b...)
+	for i := 4; i < 4+gtSize; i++ {
+		c[i] = 0
+	}
+	c[4+gtSize-1] = 2
+	require.EqualError(t, q.UnmarshalBinary(c),
+		"aggregated proof: element is not in GT")
+}
+
+func TestNewTestSRS(t *testing.T) {
+	s1, err := NewTestSRS(4, []byte("seed"))
+	require.NoError(t, err)
+	s2, err := NewTestSRS(4, []byte("seed"))
+	require.NoError(t, err)
+	require.Equal(t, 4, s1.Size())
+	require.Len(t, s1.g1A, 8)
+	for i := range s1.g1A {
+		require.Equal(t, s1.g1A[i].Marshal(), s2.g1A[i].Marshal())
+		require.Equal(t, s1.g1B[i].Marshal(), s2.g1B[i].Marshal())
+	}
+	for i := range s1.g2A {
+		require.Equal(t, s1.g2A[i].Marshal(), s2.g2A[i].Marshal())
+		require.Equal(t, s1.g2B[i].Marshal(), s2.g2B[i].Marshal())
+	}
+	require.NotEqual(t, s1.g1A[1].Marshal(), s1.g1B[1].Marshal())
+}
+
+func BenchmarkAggregate(b *testing.B) {
+	vkJSON, zkProofs := readTestData(b)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Aggregate(srs, vkJSON, zkProofs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	vkJSON, zkProofs := readTestData(b)
+	srs, err := NewTestSRS(8, []byte("test"))
+	require.NoError(b, err)
+	p, err := Aggregate(srs, vkJSON, zkProofs)
+	require.NoError(b, err)
+	vsrs, pub := srs.VerifierSRS(), pubSignals(zkProofs)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Verify(vsrs, vkJSON, p, pub); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
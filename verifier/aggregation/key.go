@@ -0,0 +1,140 @@
+package aggregation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// verifyingKey is a Groth16 verification key on bn128.
+type verifyingKey struct {
+	alpha *bn256.G1
+	beta  *bn256.G2
+	gamma *bn256.G2
+	delta *bn256.G2
+	ic    []*bn256.G1
+}
+
+// vkJSON is the part of a snarkjs verification key used by the aggregation.
+type vkJSON struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	Alpha    []string   `json:"vk_alpha_1"`
+	Beta     [][]string `json:"vk_beta_2"`
+	Gamma    [][]string `json:"vk_gamma_2"`
+	Delta    [][]string `json:"vk_delta_2"`
+	IC       [][]string `json:"IC"`
+}
+
+func parseVerifyingKey(verificationKey []byte) (*verifyingKey, error) {
+	var vkStr vkJSON
+	if err := json.Unmarshal(verificationKey, &vkStr); err != nil {
+		return nil, err
+	}
+	if vkStr.Protocol != "" && vkStr.Protocol != "groth16" {
+		return nil, fmt.Errorf("verification key protocol is not groth16: %v",
+			vkStr.Protocol)
+	}
+	if vkStr.Curve != "" && vkStr.Curve != "bn128" {
+		return nil, fmt.Errorf("unsupported curve: %v", vkStr.Curve)
+	}
+
+	var (
+		v   verifyingKey
+		err error
+	)
+	if v.alpha, err = verifier.ParseG1(vkStr.Alpha); err != nil {
+		return nil, fmt.Errorf("invalid vk_alpha_1: %w", err)
+	}
+	if v.beta, err = verifier.ParseG2(vkStr.Beta); err != nil {
+		return nil, fmt.Errorf("invalid vk_beta_2: %w", err)
+	}
+	if v.gamma, err = verifier.ParseG2(vkStr.Gamma); err != nil {
+		return nil, fmt.Errorf("invalid vk_gamma_2: %w", err)
+	}
+	if v.delta, err = verifier.ParseG2(vkStr.Delta); err != nil {
+		return nil, fmt.Errorf("invalid vk_delta_2: %w", err)
+	}
+	if len(vkStr.IC) == 0 {
+		return nil, errors.New("verification key has no IC points")
+	}
+	v.ic = make([]*bn256.G1, len(vkStr.IC))
+	for i := range vkStr.IC {
+		if v.ic[i], err = verifier.ParseG1(vkStr.IC[i]); err != nil {
+			return nil, fmt.Errorf("invalid IC[%v]: %w", i, err)
+		}
+	}
+	return &v, nil
+}
+
+// addTo adds the key to the transcript.
+func (vk *verifyingKey) addTo(t *transcript) {
+	t.addG1(vk.alpha)
+	t.addG2(vk.beta)
+	t.addG2(vk.gamma)
+	t.addG2(vk.delta)
+	for _, p := range vk.ic {
+		t.addG1(p)
+	}
+}
+
+// parseInputs parses the public signals of a proof and checks them against
+// the key.
+func (vk *verifyingKey) parseInputs(pubSignals []string) ([]*big.Int, error) {
+	if len(pubSignals)+1 != len(vk.ic) {
+		return nil, errors.New("len(inputs)+1 != len(vk.IC)")
+	}
+	inputs := make([]*big.Int, len(pubSignals))
+	for i, s := range pubSignals {
+		base := 10
+		if strings.HasPrefix(s, "0x") {
+			base = 16
+			s = strings.TrimPrefix(s, "0x")
+		}
+		n, ok := new(big.Int).SetString(s, base)
+		if !ok {
+			return nil, fmt.Errorf("can not parse string to *big.Int: %s",
+				pubSignals[i])
+		}
+		if n.Sign() < 0 || n.Cmp(constants.Q) >= 0 {
+			return nil, errors.New("input value is not in the fields")
+		}
+		inputs[i] = n
+	}
+	return inputs, nil
+}
+
+// parseProof parses the points of a Groth16 proof.
+func parseProof(pr *types.ProofData) (a *bn256.G1, b *bn256.G2, c *bn256.G1,
+	err error) {
+
+	if pr == nil {
+		return nil, nil, nil, errors.New("proof is empty")
+	}
+	if pr.Protocol != "" && pr.Protocol != "groth16" {
+		return nil, nil, nil,
+			fmt.Errorf("proof protocol is not groth16: %v", pr.Protocol)
+	}
+	if pr.Curve != "" && pr.Curve != "bn128" {
+		return nil, nil, nil, fmt.Errorf(
+			"proof curve %v does not match verification key curve bn128",
+			pr.Curve)
+	}
+	if a, err = verifier.ParseG1(pr.A); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid pi_a: %w", err)
+	}
+	if b, err = verifier.ParseG2(pr.B); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid pi_b: %w", err)
+	}
+	if c, err = verifier.ParseG1(pr.C); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid pi_c: %w", err)
+	}
+	return a, b, c, nil
+}
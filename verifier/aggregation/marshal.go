@@ -0,0 +1,177 @@
+package aggregation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// Sizes of the encodings of the points.
+const (
+	g1Size = 64
+	g2Size = 128
+	gtSize = 384
+
+	// roundSize is the size of the encoding of a GIPA round.
+	roundSize = 10*gtSize + 2*g1Size
+	// fixedSize is the size of the encoding of a proof without rounds.
+	fixedSize = 4 + 5*gtSize + g1Size + // number of rounds, commitments, Z
+		2*g1Size + g2Size + 2*g2Size + 2*g1Size + // final vectors and keys
+		2*g2Size + 2*g1Size // openings
+)
+
+// MarshalBinary encodes the proof as the number of GIPA rounds as a 32-bit
+// big-endian integer followed by the points of the proof, in the encoding
+// of bn256.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 4, fixedSize+len(p.rounds)*roundSize)
+	binary.BigEndian.PutUint32(b, uint32(len(p.rounds)))
+
+	for k := 0; k < 2; k++ {
+		b = append(b, p.comAB[k].Marshal()...)
+	}
+	for k := 0; k < 2; k++ {
+		b = append(b, p.comC[k].Marshal()...)
+	}
+	b = append(b, p.zAB.Marshal()...)
+	b = append(b, p.zC.Marshal()...)
+
+	for _, round := range p.rounds {
+		for _, e := range round.gts() {
+			b = append(b, (*e).Marshal()...)
+		}
+		b = append(b, round.zCL.Marshal()...)
+		b = append(b, round.zCR.Marshal()...)
+	}
+
+	b = append(b, p.finalA.Marshal()...)
+	b = append(b, p.finalB.Marshal()...)
+	b = append(b, p.finalC.Marshal()...)
+	for k := 0; k < 2; k++ {
+		b = append(b, p.finalV[k].Marshal()...)
+	}
+	for k := 0; k < 2; k++ {
+		b = append(b, p.finalW[k].Marshal()...)
+	}
+	for k := 0; k < 2; k++ {
+		b = append(b, p.openV[k].Marshal()...)
+	}
+	for k := 0; k < 2; k++ {
+		b = append(b, p.openW[k].Marshal()...)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary. Points are checked
+// to be in their groups.
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	if len(data) < fixedSize {
+		return errors.New("aggregated proof is too short")
+	}
+	nRounds := binary.BigEndian.Uint32(data)
+	if uint64(len(data)) != fixedSize+uint64(nRounds)*roundSize {
+		return fmt.Errorf("invalid length of aggregated proof: %v",
+			len(data))
+	}
+	d := decoder{data: data[4:]}
+
+	var q Proof
+	for k := 0; k < 2; k++ {
+		q.comAB[k] = d.gt()
+	}
+	for k := 0; k < 2; k++ {
+		q.comC[k] = d.gt()
+	}
+	q.zAB = d.gt()
+	q.zC = d.g1()
+
+	q.rounds = make([]gipaRound, nRounds)
+	for i := range q.rounds {
+		for _, e := range q.rounds[i].gts() {
+			*e = d.gt()
+		}
+		q.rounds[i].zCL = d.g1()
+		q.rounds[i].zCR = d.g1()
+	}
+
+	q.finalA = d.g1()
+	q.finalB = d.g2()
+	q.finalC = d.g1()
+	for k := 0; k < 2; k++ {
+		q.finalV[k] = d.g2()
+	}
+	for k := 0; k < 2; k++ {
+		q.finalW[k] = d.g1()
+	}
+	for k := 0; k < 2; k++ {
+		q.openV[k] = d.g2()
+	}
+	for k := 0; k < 2; k++ {
+		q.openW[k] = d.g1()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	*p = q
+	return nil
+}
+
+// gts returns the elements of GT of the round in the order of the encoding.
+func (round *gipaRound) gts() []**bn256.GT {
+	return []**bn256.GT{
+		&round.zABL, &round.zABR,
+		&round.comABL[0], &round.comABL[1], &round.comABR[0], &round.comABR[1],
+		&round.comCL[0], &round.comCL[1], &round.comCR[0], &round.comCR[1],
+	}
+}
+
+// decoder decodes points from data and keeps the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) next(size int) []byte {
+	b := d.data[:size]
+	d.data = d.data[size:]
+	return b
+}
+
+func (d *decoder) g1() *bn256.G1 {
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(d.next(g1Size)); err != nil && d.err == nil {
+		d.err = err
+	}
+	return p
+}
+
+func (d *decoder) g2() *bn256.G2 {
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(d.next(g2Size)); err != nil && d.err == nil {
+		d.err = err
+	}
+	return p
+}
+
+// gt decodes an element of GT and checks that its order is the order of
+// the group.
+func (d *decoder) gt() *bn256.GT {
+	b := d.next(gtSize)
+	if d.err != nil {
+		return nil
+	}
+	e, err := bn256.UnmarshalGT(b)
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	one := bn256.MultiPair(nil, nil)
+	if !gtEqual(new(bn256.GT).ScalarMult(e, constants.Q), one) {
+		d.err = errors.New("aggregated proof: element is not in GT")
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,73 @@
+package aggregation
+
+import (
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+)
+
+func frMul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, constants.Q)
+}
+
+func frAdd(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, constants.Q)
+}
+
+func frNeg(a *big.Int) *big.Int {
+	r := new(big.Int).Neg(a)
+	return r.Mod(r, constants.Q)
+}
+
+func frInv(a *big.Int) *big.Int {
+	return new(big.Int).ModInverse(a, constants.Q)
+}
+
+func frExp(a *big.Int, e int) *big.Int {
+	return new(big.Int).Exp(a, big.NewInt(int64(e)), constants.Q)
+}
+
+// keyPolynomial returns the coefficients of ∏ (1 + f[j]·X^(n/2^(j+1))),
+// n = 2^len(f): the polynomial of a commitment key of n points folded with
+// the factors f, one per round. The coefficient of X^k is the product of
+// the factors of the bits of k.
+func keyPolynomial(f []*big.Int) []*big.Int {
+	n := 1 << len(f)
+	c := make([]*big.Int, n)
+	for k := range c {
+		c[k] = big.NewInt(1)
+		for j := range f {
+			if k&(n>>(j+1)) != 0 {
+				c[k] = frMul(c[k], f[j])
+			}
+		}
+	}
+	return c
+}
+
+// evalKeyPolynomial returns the value of keyPolynomial(f) at z.
+func evalKeyPolynomial(f []*big.Int, z *big.Int) *big.Int {
+	n := 1 << len(f)
+	r := big.NewInt(1)
+	for j := range f {
+		t := frMul(f[j], frExp(z, n>>(j+1)))
+		r = frMul(r, frAdd(t, big.NewInt(1)))
+	}
+	return r
+}
+
+// quotient returns the coefficients of (p(X)-p(z))/(X-z).
+func quotient(p []*big.Int, z *big.Int) []*big.Int {
+	if len(p) == 0 {
+		return nil
+	}
+	q := make([]*big.Int, len(p)-1)
+	acc := new(big.Int)
+	for i := len(p) - 1; i >= 1; i-- {
+		acc = frAdd(frMul(acc, z), p[i])
+		q[i-1] = acc
+	}
+	return q
+}
@@ -0,0 +1,107 @@
+package aggregation
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+// SRS is the structured reference string of the aggregation: the powers of
+// two secrets a and b in G1 and G2. It aggregates up to Size proofs.
+type SRS struct {
+	// g1A[i] is a^i·g for i < 2·Size, g1B[i] is b^i·g.
+	g1A, g1B []*bn256.G1
+	// g2A[i] is a^i·h for i < Size, g2B[i] is b^i·h.
+	g2A, g2B []*bn256.G2
+}
+
+// VerifierSRS is the part of the SRS that verifies aggregated proofs: the
+// generators g and h and their multiples by a and b.
+type VerifierSRS struct {
+	g  *bn256.G1
+	gA *bn256.G1
+	gB *bn256.G1
+	h  *bn256.G2
+	hA *bn256.G2
+	hB *bn256.G2
+}
+
+// NewTestSRS returns an SRS for up to size proofs, a power of two. The
+// secrets are derived from seed, so the SRS is deterministic. Anyone who
+// knows seed can forge aggregated proofs: it is meant for tests, a real SRS
+// comes from a trusted setup.
+func NewTestSRS(size int, seed []byte) (*SRS, error) {
+	if size < 1 || size&(size-1) != 0 {
+		return nil, errors.New("SRS size must be a power of two")
+	}
+	a := hashToScalar(seed, "a")
+	b := hashToScalar(seed, "b")
+
+	s := &SRS{
+		g1A: g1Powers(a, 2*size),
+		g1B: g1Powers(b, 2*size),
+		g2A: g2Powers(a, size),
+		g2B: g2Powers(b, size),
+	}
+	return s, nil
+}
+
+// Size returns the maximum number of proofs aggregated with the SRS.
+func (s *SRS) Size() int {
+	return len(s.g2A)
+}
+
+// VerifierSRS returns the part of the SRS that verifies aggregated proofs.
+func (s *SRS) VerifierSRS() *VerifierSRS {
+	return &VerifierSRS{
+		g:  s.g1A[0],
+		gA: s.g1A[1],
+		gB: s.g1B[1],
+		h:  s.g2A[0],
+		hA: s.g2A[1],
+		hB: s.g2B[1],
+	}
+}
+
+// hashToScalar derives a non-zero scalar from seed and label.
+func hashToScalar(seed []byte, label string) *big.Int {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(seed)
+	_, _ = h.Write([]byte(label))
+	d := h.Sum(nil)
+	for {
+		x := new(big.Int).SetBytes(d)
+		x.Mod(x, constants.Q)
+		if x.Sign() != 0 {
+			return x
+		}
+		h.Reset()
+		_, _ = h.Write(d)
+		d = h.Sum(nil)
+	}
+}
+
+// g1Powers returns x^i·g for i < n.
+func g1Powers(x *big.Int, n int) []*bn256.G1 {
+	p := make([]*bn256.G1, n)
+	k := big.NewInt(1)
+	for i := range p {
+		p[i] = new(bn256.G1).ScalarBaseMult(k)
+		k = frMul(k, x)
+	}
+	return p
+}
+
+// g2Powers returns x^i·h for i < n.
+func g2Powers(x *big.Int, n int) []*bn256.G2 {
+	p := make([]*bn256.G2, n)
+	k := big.NewInt(1)
+	for i := range p {
+		p[i] = new(bn256.G2).ScalarBaseMult(k)
+		k = frMul(k, x)
+	}
+	return p
+}
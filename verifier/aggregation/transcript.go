@@ -0,0 +1,60 @@
+package aggregation
+
+import (
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+// transcript derives the challenges of the aggregation with the
+// Fiat-Shamir heuristic: a challenge is the Keccak-256 hash of the previous
+// one and the data added since, as a big-endian number reduced modulo the
+// scalar field. Challenges are never zero.
+type transcript struct {
+	state []byte
+	data  []byte
+}
+
+func newTranscript(label string) *transcript {
+	return &transcript{state: []byte(label)}
+}
+
+func (t *transcript) add(b []byte) {
+	t.data = append(t.data, b...)
+}
+
+func (t *transcript) addG1(p *bn256.G1) {
+	t.add(p.Marshal())
+}
+
+func (t *transcript) addG2(p *bn256.G2) {
+	t.add(p.Marshal())
+}
+
+func (t *transcript) addGT(e *bn256.GT) {
+	t.add(e.Marshal())
+}
+
+func (t *transcript) addScalar(s *big.Int) {
+	t.add(s.FillBytes(make([]byte, 32)))
+}
+
+// challenge returns the challenge of the data added to the transcript and
+// resets it.
+func (t *transcript) challenge() *big.Int {
+	for {
+		h := sha3.NewLegacyKeccak256()
+		_, _ = h.Write(t.state)
+		_, _ = h.Write(t.data)
+		t.state = h.Sum(nil)
+		t.data = t.data[:0]
+
+		c := new(big.Int).SetBytes(t.state)
+		c.Mod(c, constants.Q)
+		if c.Sign() != 0 {
+			return c
+		}
+	}
+}
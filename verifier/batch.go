@@ -0,0 +1,183 @@
+package verifier
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// batchScalarBits is the size of the random scalars of the linear
+// combination. A batch with an invalid proof passes with probability 2^-128.
+const batchScalarBits = 128
+
+// BatchError is returned by BatchVerify when some of the proofs are invalid.
+type BatchError struct {
+	// Errs has the error of every proof of the batch, nil for the valid
+	// ones.
+	Errs []error
+}
+
+// Invalid returns the indexes of the invalid proofs.
+func (e *BatchError) Invalid() []int {
+	var idx []int
+	for i, err := range e.Errs {
+		if err != nil {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+func (e *BatchError) Error() string {
+	idx := e.Invalid()
+	s := make([]string, len(idx))
+	for i, j := range idx {
+		s[i] = fmt.Sprintf("%v: %v", j, e.Errs[j])
+	}
+	return fmt.Sprintf("%v of %v proofs are invalid: %v", len(idx),
+		len(e.Errs), strings.Join(s, "; "))
+}
+
+// BatchVerify verifies many proofs against the verification key at once.
+// The proofs are folded into a single pairing check with a random linear
+// combination, which costs about len(zkProofs)+3 Miller loops and a single
+// final exponentiation. If the check fails, every proof is verified on its
+// own and a *BatchError with the invalid proofs is returned. Proofs on
+// BLS12-381 are always verified on their own.
+func (vk *VerifyingKey) BatchVerify(zkProofs []types.ZKProof) error {
+	if vk.bls != nil {
+		return vk.verifyEach(zkProofs)
+	}
+
+	var (
+		proofs = make([]proofPairingData, len(zkProofs))
+		inputs = make([][]*big.Int, len(zkProofs))
+		errs   = make([]error, len(zkProofs))
+		failed bool
+		batch  []int
+	)
+	for i := range zkProofs {
+		if zkProofs[i].Proof == nil {
+			errs[i] = errors.New("proof is empty")
+			failed = true
+			continue
+		}
+		errs[i] = vk.checkProofData(*zkProofs[i].Proof)
+		if errs[i] == nil {
+			proofs[i], errs[i] = parseProofData(*zkProofs[i].Proof)
+		}
+		if errs[i] == nil {
+			inputs[i], errs[i] = stringsToArrayBigInt(zkProofs[i].PubSignals)
+		}
+		if errs[i] == nil {
+			errs[i] = vk.checkInputs(inputs[i])
+		}
+		if errs[i] != nil {
+			failed = true
+			continue
+		}
+		batch = append(batch, i)
+	}
+
+	if len(batch) == 0 {
+		if failed {
+			return &BatchError{Errs: errs}
+		}
+		return nil
+	}
+
+	ok, err := vk.batchCheck(proofs, inputs, batch)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// find the invalid proofs
+		for _, i := range batch {
+			if errs[i] = vk.verify(proofs[i], inputs[i]); errs[i] != nil {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return &BatchError{Errs: errs}
+	}
+	return nil
+}
+
+// batchCheck checks the proofs of batch with a random linear combination
+// of their verification equations:
+//
+//	∏ e(r_i·A_i, B_i)·e(∑ r_i·vkX_i, -gamma)·e(∑ r_i·C_i, -delta)·
+//		e(∑ r_i·alpha, -beta) = 1
+//
+// The public inputs are folded in the scalars, so ∑ r_i·vkX_i is a single
+// linear combination of the IC points.
+func (vk *VerifyingKey) batchCheck(proofs []proofPairingData,
+	inputs [][]*big.Int, batch []int) (bool, error) {
+
+	max := new(big.Int).Lsh(big.NewInt(1), batchScalarBits)
+
+	g1 := make([]*bn256.G1, 0, len(batch)+3)
+	g2 := make([]*bn256.G2Prepared, 0, len(batch)+3)
+
+	// icScalars[0] is ∑ r_i, the scalar of IC[0] and alpha
+	icScalars := make([]*big.Int, len(vk.ic))
+	for j := range icScalars {
+		icScalars[j] = new(big.Int)
+	}
+	cs := make([]*bn256.G1, 0, len(batch))
+	rs := make([]*big.Int, 0, len(batch))
+	t := new(big.Int)
+
+	for _, i := range batch {
+		r, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return false, err
+		}
+		// a zero scalar would drop the proof from the check
+		r.Add(r, big.NewInt(1))
+
+		g1 = append(g1, new(bn256.G1).ScalarMult(proofs[i].A, r))
+		g2 = append(g2, bn256.NewG2Prepared(proofs[i].B))
+		cs = append(cs, proofs[i].C)
+		rs = append(rs, r)
+
+		icScalars[0].Add(icScalars[0], r)
+		for j, in := range inputs[i] {
+			icScalars[j+1].Add(icScalars[j+1], t.Mul(r, in))
+		}
+	}
+
+	for j := range icScalars {
+		icScalars[j].Mod(icScalars[j], constants.Q)
+	}
+	vkX := vk.icCombination(icScalars)
+	c := new(bn256.G1).MultiScalarMult(cs, rs)
+	alpha := new(bn256.G1).ScalarMult(vk.alpha, icScalars[0])
+
+	g1 = append(g1, vkX, c, alpha)
+	g2 = append(g2, vk.negGamma, vk.negDelta, vk.negBeta)
+	return bn256.PairingCheckPrepared(g1, g2, nil), nil
+}
+
+// verifyEach verifies every proof on its own.
+func (vk *VerifyingKey) verifyEach(zkProofs []types.ZKProof) error {
+	errs := make([]error, len(zkProofs))
+	failed := false
+	for i := range zkProofs {
+		if errs[i] = vk.Verify(zkProofs[i]); errs[i] != nil {
+			failed = true
+		}
+	}
+	if failed {
+		return &BatchError{Errs: errs}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestProofs(t testing.TB) []types.ZKProof {
+	proofsJSON, err := os.ReadFile("testdata/proofs.json")
+	require.NoError(t, err)
+
+	var zkProofs []types.ZKProof
+	require.NoError(t, json.Unmarshal(proofsJSON, &zkProofs))
+	return append(zkProofs, readTestProof(t))
+}
+
+func TestBatchVerify(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(t, err)
+
+	zkProofs := readTestProofs(t)
+	require.NoError(t, vk.BatchVerify(zkProofs))
+	require.NoError(t, vk.BatchVerify(zkProofs[:1]))
+	require.NoError(t, vk.BatchVerify(nil))
+
+	vk.Precompute()
+	require.NoError(t, vk.BatchVerify(zkProofs))
+
+	t.Run("invalid proofs", func(t *testing.T) {
+		bad := append([]types.ZKProof{}, zkProofs...)
+		bad[1].PubSignals = []string{"396", "4"}
+		bad[2].PubSignals = []string{"396"}
+		// swap C of two proofs, so the batch has two invalid pairing
+		// equations
+		p0, p3 := *bad[0].Proof, *bad[3].Proof
+		p0.C, p3.C = p3.C, p0.C
+		bad[0].Proof, bad[3].Proof = &p0, &p3
+
+		err := vk.BatchVerify(bad)
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, []int{0, 1, 2, 3}, batchErr.Invalid())
+		require.EqualError(t, batchErr.Errs[1], "invalid proofs")
+		require.EqualError(t, batchErr.Errs[2], "len(inputs)+1 != len(vk.IC)")
+
+		bad = append([]types.ZKProof{}, zkProofs...)
+		bad[2].PubSignals = []string{"396", "4"}
+		err = vk.BatchVerify(bad)
+		require.EqualError(t, err, "1 of 4 proofs are invalid: 2: invalid proofs")
+	})
+
+	t.Run("no valid proofs", func(t *testing.T) {
+		err := vk.BatchVerify([]types.ZKProof{{}})
+		require.EqualError(t, err, "1 of 1 proofs are invalid: 0: proof is empty")
+	})
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(b, err)
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(b, err)
+	zkProofs := readTestProofs(b)
+	for len(zkProofs) < 32 {
+		zkProofs = append(zkProofs, zkProofs...)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = vk.BatchVerify(zkProofs)
+	}
+}
@@ -0,0 +1,316 @@
+// Package bls12381 implements the BLS12-381 pairing-friendly curve with the
+// optimal ate pairing, as used by snarkjs for its bls12381 curve.
+//
+// G₁ is the subgroup of order Order of y²=x³+4 over GF(p), G₂ the one of
+// y²=x³+4(u+1) over GF(p²) = GF(p)[u]/(u²+1) and GT the one of
+// GF(p¹²) = GF(p²)[v, w]/(v³-(u+1), w²-v).
+//
+// The package follows the API of the bn256 packages of the verifier. It is
+// written for verification of proofs and it is not constant time.
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+)
+
+// G1 is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type G1 struct {
+	p *curvePoint
+}
+
+func (e *G1) String() string {
+	return "bls12381.G1" + e.p.String()
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and
+// then returns e.
+func (e *G1) ScalarBaseMult(k *big.Int) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Mul(curveGen, k)
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *G1) ScalarMult(a *G1, k *big.Int) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Mul(a.p, k)
+	return e
+}
+
+// Add sets e to a+b and then returns e.
+func (e *G1) Add(a, b *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Add(a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *G1) Neg(a *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Neg(a.p)
+	return e
+}
+
+// Set sets e to a and then returns e.
+func (e *G1) Set(a *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Set(a.p)
+	return e
+}
+
+// Marshal converts e to a byte slice of the big-endian affine coordinates
+// x and y, 48 bytes each. The point at infinity is all zeros.
+func (e *G1) Marshal() []byte {
+	// Each value is a 384-bit number.
+	const numBytes = 384 / 8
+
+	ret := make([]byte, numBytes*2)
+	if e.p == nil || e.p.IsInfinity() {
+		return ret
+	}
+	e.p.MakeAffine()
+	e.p.x.Marshal(ret)
+	e.p.y.Marshal(ret[numBytes:])
+	return ret
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back
+// into a group element and then returns the remaining bytes. The point is
+// checked to be in G₁.
+func (e *G1) Unmarshal(m []byte) ([]byte, error) {
+	// Each value is a 384-bit number.
+	const numBytes = 384 / 8
+	if len(m) < 2*numBytes {
+		return nil, errors.New("bls12381: not enough data")
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	var err error
+	if err = e.p.x.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	if err = e.p.y.Unmarshal(m[numBytes:]); err != nil {
+		return nil, err
+	}
+
+	if e.p.x.IsZero() && e.p.y.IsZero() {
+		// This is the point at infinity.
+		e.p.SetInfinity()
+	} else {
+		e.p.z = *newGFp(1)
+		if !e.p.IsOnCurve() {
+			return nil, errors.New("bls12381: malformed point")
+		}
+		if !e.p.IsInSubgroup() {
+			return nil, errors.New("bls12381: point is not in G1")
+		}
+	}
+	return m[2*numBytes:], nil
+}
+
+// G2 is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type G2 struct {
+	p *twistPoint
+}
+
+func (e *G2) String() string {
+	return "bls12381.G2" + e.p.String()
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and
+// then returns e.
+func (e *G2) ScalarBaseMult(k *big.Int) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Mul(twistGen, k)
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *G2) ScalarMult(a *G2, k *big.Int) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Mul(a.p, k)
+	return e
+}
+
+// Add sets e to a+b and then returns e.
+func (e *G2) Add(a, b *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Add(a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *G2) Neg(a *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Neg(a.p)
+	return e
+}
+
+// Set sets e to a and then returns e.
+func (e *G2) Set(a *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Set(a.p)
+	return e
+}
+
+// Marshal converts e into a byte slice of the big-endian affine coordinates
+// x and y, each of them as the imaginary and then the real part of 48 bytes.
+// The point at infinity is all zeros.
+func (e *G2) Marshal() []byte {
+	// Each value is a 384-bit number.
+	const numBytes = 384 / 8
+
+	ret := make([]byte, numBytes*4)
+	if e.p == nil || e.p.IsInfinity() {
+		return ret
+	}
+	e.p.MakeAffine()
+	e.p.x.x.Marshal(ret)
+	e.p.x.y.Marshal(ret[numBytes:])
+	e.p.y.x.Marshal(ret[2*numBytes:])
+	e.p.y.y.Marshal(ret[3*numBytes:])
+	return ret
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back
+// into a group element and then returns the remaining bytes. The point is
+// checked to be in G₂.
+func (e *G2) Unmarshal(m []byte) ([]byte, error) {
+	// Each value is a 384-bit number.
+	const numBytes = 384 / 8
+	if len(m) < 4*numBytes {
+		return nil, errors.New("bls12381: not enough data")
+	}
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	var err error
+	if err = e.p.x.x.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	if err = e.p.x.y.Unmarshal(m[numBytes:]); err != nil {
+		return nil, err
+	}
+	if err = e.p.y.x.Unmarshal(m[2*numBytes:]); err != nil {
+		return nil, err
+	}
+	if err = e.p.y.y.Unmarshal(m[3*numBytes:]); err != nil {
+		return nil, err
+	}
+
+	if e.p.x.IsZero() && e.p.y.IsZero() {
+		// This is the point at infinity.
+		e.p.SetInfinity()
+	} else {
+		e.p.z.SetOne()
+		if !e.p.IsOnCurve() {
+			return nil, errors.New("bls12381: malformed point")
+		}
+		if !e.p.IsInSubgroup() {
+			return nil, errors.New("bls12381: point is not in G2")
+		}
+	}
+	return m[4*numBytes:], nil
+}
+
+// GT is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type GT struct {
+	p *gfP12
+}
+
+// Pair calculates an Optimal Ate pairing.
+func Pair(g1 *G1, g2 *G2) *GT {
+	return &GT{optimalAte(g2.p, g1.p)}
+}
+
+// PairingCheck calculates the Optimal Ate pairing for a set of points and
+// reports whether the product of the results is one.
+func PairingCheck(a []*G1, b []*G2) bool {
+	acc := (&gfP12{}).SetOne()
+	for i := 0; i < len(a); i++ {
+		if a[i].p.IsInfinity() || b[i].p.IsInfinity() {
+			continue
+		}
+		acc.Mul(acc, miller(b[i].p, a[i].p))
+	}
+	return finalExponentiation(acc).IsOne()
+}
+
+func (e *GT) String() string {
+	return "bls12381.GT" + e.p.String()
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *GT) ScalarMult(a *GT, k *big.Int) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Exp(a.p, k)
+	return e
+}
+
+// Add sets e to a+b and then returns e.
+func (e *GT) Add(a, b *GT) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Mul(a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *GT) Neg(a *GT) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Conjugate(a.p)
+	return e
+}
+
+// IsOne reports whether e is the identity of GT.
+func (e *GT) IsOne() bool {
+	return e.p.IsOne()
+}
+
+// Marshal converts e into a byte slice of the 12 coefficients of GF(p¹²),
+// 48 bytes each, in the order of the tower from the highest to the lowest
+// and the imaginary part first.
+func (e *GT) Marshal() []byte {
+	// Each value is a 384-bit number.
+	const numBytes = 384 / 8
+
+	ret := make([]byte, numBytes*12)
+	coeffs := []*gfP{
+		&e.p.x.x.x, &e.p.x.x.y, &e.p.x.y.x, &e.p.x.y.y, &e.p.x.z.x, &e.p.x.z.y,
+		&e.p.y.x.x, &e.p.y.x.y, &e.p.y.y.x, &e.p.y.y.y, &e.p.y.z.x, &e.p.y.z.y,
+	}
+	for i, c := range coeffs {
+		c.Marshal(ret[i*numBytes:])
+	}
+	return ret
+}
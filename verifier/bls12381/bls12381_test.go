@@ -0,0 +1,183 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestGenerators(t *testing.T) {
+	g1 := &curvePoint{}
+	g1.Set(curveGen)
+	if !g1.IsOnCurve() || !g1.IsInSubgroup() {
+		t.Fatal("G1 generator is not in G1")
+	}
+	g2 := &twistPoint{}
+	g2.Set(twistGen)
+	if !g2.IsOnCurve() || !g2.IsInSubgroup() {
+		t.Fatal("G2 generator is not in G2")
+	}
+}
+
+func TestG1Marshal(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	ma := new(G1).ScalarBaseMult(k).Marshal()
+
+	pa := new(G1)
+	if _, err := pa.Unmarshal(ma); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ma, pa.Marshal()) {
+		t.Fatal("bytes are different")
+	}
+
+	inf := new(G1).ScalarBaseMult(Order).Marshal()
+	if !bytes.Equal(inf, make([]byte, 96)) {
+		t.Fatal("infinity is not zero")
+	}
+	if _, err := pa.Unmarshal(inf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestG2Marshal(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	ma := new(G2).ScalarBaseMult(k).Marshal()
+
+	pa := new(G2)
+	if _, err := pa.Unmarshal(ma); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ma, pa.Marshal()) {
+		t.Fatal("bytes are different")
+	}
+}
+
+func TestG1UnmarshalNotInSubgroup(t *testing.T) {
+	// (0, 2) is on y²=x³+4 but it is not in G₁
+	m := make([]byte, 96)
+	m[95] = 2
+	if _, err := new(G1).Unmarshal(m); err == nil ||
+		err.Error() != "bls12381: point is not in G1" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m[95] = 3
+	if _, err := new(G1).Unmarshal(m); err == nil ||
+		err.Error() != "bls12381: malformed point" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestG2UnmarshalNotInSubgroup(t *testing.T) {
+	// (2, y) is on y²=x³+4(u+1) but it is not in G₂
+	m, _ := hex.DecodeString(
+		"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000202d27e0ec3356299a346a09ad7dc4ef68a483c3aed53f9139d2f929a3eecebf72082e5e58c6da24ee32e03040c406d4f013a59858b6809fca4d9a3b6539246a70051a3c88899964a42bc9a69cf9acdd9dd387cfa9086b894185b9a46a402be73")
+	if _, err := new(G2).Unmarshal(m); err == nil ||
+		err.Error() != "bls12381: point is not in G2" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m[191] ^= 1
+	if _, err := new(G2).Unmarshal(m); err == nil ||
+		err.Error() != "bls12381: malformed point" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestScalarMult(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+	ab := new(big.Int).Add(a, b)
+
+	pa, pb := new(G1).ScalarBaseMult(a), new(G1).ScalarBaseMult(b)
+	if !bytes.Equal(new(G1).Add(pa, pb).Marshal(),
+		new(G1).ScalarBaseMult(ab).Marshal()) {
+		t.Fatal("G1: a·g + b·g != (a+b)·g")
+	}
+	if !bytes.Equal(new(G1).Add(pa, pa).Marshal(),
+		new(G1).ScalarMult(pa, big.NewInt(2)).Marshal()) {
+		t.Fatal("G1: a·g + a·g != 2·a·g")
+	}
+
+	qa, qb := new(G2).ScalarBaseMult(a), new(G2).ScalarBaseMult(b)
+	if !bytes.Equal(new(G2).Add(qa, qb).Marshal(),
+		new(G2).ScalarBaseMult(ab).Marshal()) {
+		t.Fatal("G2: a·g + b·g != (a+b)·g")
+	}
+}
+
+func TestBilinearity(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+
+	pa := new(G1).ScalarBaseMult(a)
+	qb := new(G2).ScalarBaseMult(b)
+	e1 := Pair(pa, qb)
+
+	g := Pair(new(G1).ScalarBaseMult(big.NewInt(1)),
+		new(G2).ScalarBaseMult(big.NewInt(1)))
+	e2 := new(GT).ScalarMult(g, new(big.Int).Mul(a, b))
+
+	if !bytes.Equal(e1.Marshal(), e2.Marshal()) {
+		t.Fatal("e(a·g1, b·g2) != e(g1, g2)^ab")
+	}
+	if g.IsOne() {
+		t.Fatal("pairing is degenerate")
+	}
+	if !new(GT).ScalarMult(g, Order).IsOne() {
+		t.Fatal("e(g1, g2)^Order != 1")
+	}
+}
+
+func TestPairingCheck(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+	ab := new(big.Int).Mul(a, b)
+
+	g1 := new(G1).ScalarBaseMult(big.NewInt(1))
+	g2 := new(G2).ScalarBaseMult(big.NewInt(1))
+
+	// e(a·g1, b·g2)·e(-ab·g1, g2) = 1
+	g1s := []*G1{new(G1).ScalarBaseMult(a),
+		new(G1).Neg(new(G1).ScalarBaseMult(ab))}
+	g2s := []*G2{new(G2).ScalarBaseMult(b), g2}
+	if !PairingCheck(g1s, g2s) {
+		t.Fatal("pairing check failed")
+	}
+
+	g2s[1] = new(G2).Neg(g2)
+	if PairingCheck(g1s, g2s) {
+		t.Fatal("pairing check succeeded")
+	}
+
+	if !PairingCheck([]*G1{g1, new(G1).ScalarBaseMult(Order)},
+		[]*G2{new(G2).ScalarBaseMult(Order), g2}) {
+		t.Fatal("pairing check with infinity failed")
+	}
+}
+
+func TestPairVector(t *testing.T) {
+	// e(3·g1, 5·g2) computed with an independent implementation whose final
+	// exponentiation is to the power of 3·(p¹²-1)/Order, which is
+	// e(9·g1, 5·g2) here
+	want := "0b2ac4004e3062b4c83d3c73f121f011afe4e5ce5f7c9defefc7664ef04e01d864d5a2525bfd007d12f4425986b8fc4e0ad9e6cdd6f7ca48eac6474d43cf11fbd2b1eed3b6c36a55d7be4da8e381cd9c9c2766249752a43a747a3a20ed73b9700cf34ddfa181da8e4b7cb9deb0b0a119daee08d5cbae1bc7cd11a5c22e7c39223a02e476025d4f3882463909875a4fbd071f92c79a2dc21b5905ee88d9f6adb18c78ec457448587186054cf8ab9a19d15f70193f882f422b794e3f1a18d5fc9016a283f5a2a2f9269cea0acc04092bfaf9ef9bc206add391f6792a0029a10067ac2c46fabdf9c0a6121e94de1ba8c4c40cc4b1acdb82976dd93403b8801b316e5dd7cfa8e0b5892218eca8ce26ffce117ce3cd0d37fa68c52dffcacd18965262060465b208a9981b8eccb9d8a1f02cc0c6c92a4f47d1485e755ff1c24d87478ebe85abb4dd4895269af84f23afa846681376af24809aef68d691619e3d42bed7bbb43d03ccbfa31cf05fa3b14b7336ecd5bbcfd5fd0dec705b6b486cf91a4a371662059fd0ab52364c2814a8e66c4d6fc8c4b4b037e5e6d7886b454608d4242a854d2c6518f2708ee36bd1795112de890a6abe49aa2f02033b4fda2dd2553925e2c3addbcdd99b1c78d2fea45df477bf5414dec2866bcbcbd182f31a6fa3bbb20a659eeee23850abc9f0cbeea730d08dea71b51b48a642481d7617be41a6897bb9beaca5432d327c951321c1f5bd79f212a0076a4a1f0498f1aad6944b5d1271eb61381d19f444ee867b8e2e2077f2d0c438a706fd165a27dd36fc98860e8d2a"
+
+	e := Pair(new(G1).ScalarBaseMult(big.NewInt(9)),
+		new(G2).ScalarBaseMult(big.NewInt(5)))
+	if got := hex.EncodeToString(e.Marshal()); got != want {
+		t.Fatalf("unexpected pairing:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func BenchmarkPairing(b *testing.B) {
+	g1 := new(G1).ScalarBaseMult(big.NewInt(3))
+	g2 := new(G2).ScalarBaseMult(big.NewInt(5))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Pair(g1, g2)
+	}
+}
@@ -0,0 +1,78 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+func bigFromBase10(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+func bigFromBase16(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+// u is the BLS parameter of the curve, x = -u.
+var u = bigFromBase16("d201000000010000")
+
+// Order is the number of elements in both G₁ and G₂: x⁴-x²+1.
+var Order = bigFromBase10("52435875175126190479447740508185965837690552500527637822603658699938581184513")
+
+// P is a prime over which we form a basic field: (x-1)²(x⁴-x²+1)/3+x.
+var P = bigFromBase16("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+
+// p is P in little-endian 64-bit limbs.
+var p = limbs(P)
+
+// np is -p⁻¹ mod 2⁶⁴.
+var np = func() uint64 {
+	r := new(big.Int).Lsh(big.NewInt(1), 64)
+	n := new(big.Int).ModInverse(P, r)
+	n.Sub(r, n)
+	return n.Uint64()
+}()
+
+// rN1 is R mod p and r2 is R² mod p, where R = 2³⁸⁴ is the Montgomery
+// constant.
+var (
+	rN1 = func() *gfP {
+		r := new(big.Int).Lsh(big.NewInt(1), 384)
+		e := gfP(limbs(r.Mod(r, P)))
+		return &e
+	}()
+	r2 = func() *gfP {
+		r := new(big.Int).Lsh(big.NewInt(1), 768)
+		e := gfP(limbs(r.Mod(r, P)))
+		return &e
+	}()
+)
+
+// xiToPMinus1Over3 is ξ^((p-1)/3) where ξ = u+1.
+var xiToPMinus1Over3 = xiToPower(new(big.Int).Div(new(big.Int).Sub(P, big.NewInt(1)), big.NewInt(3)))
+
+// xiTo2PMinus2Over3 is ξ^((2p-2)/3) where ξ = u+1.
+var xiTo2PMinus2Over3 = xiToPower(new(big.Int).Div(new(big.Int).Sub(new(big.Int).Lsh(P, 1), big.NewInt(2)), big.NewInt(3)))
+
+// xiToPMinus1Over6 is ξ^((p-1)/6) where ξ = u+1.
+var xiToPMinus1Over6 = xiToPower(new(big.Int).Div(new(big.Int).Sub(P, big.NewInt(1)), big.NewInt(6)))
+
+// limbs returns the little-endian 64-bit limbs of 0 <= n < 2³⁸⁴.
+func limbs(n *big.Int) [6]uint64 {
+	var l [6]uint64
+	b := n.FillBytes(make([]byte, 48))
+	for i := range l {
+		for j := 0; j < 8; j++ {
+			l[i] |= uint64(b[47-8*i-j]) << (8 * j)
+		}
+	}
+	return l
+}
+
+func xiToPower(k *big.Int) *gfP2 {
+	xi := &gfP2{}
+	xi.x.Set(rN1)
+	xi.y.Set(rN1)
+	return (&gfP2{}).Exp(xi, k)
+}
@@ -0,0 +1,196 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// gfP is an element of the base field in Montgomery form, as little-endian
+// 64-bit limbs.
+type gfP [6]uint64
+
+func newGFp(x int64) (out *gfP) {
+	if x >= 0 {
+		out = &gfP{uint64(x)}
+	} else {
+		out = &gfP{uint64(-x)}
+		gfpNeg(out, out)
+	}
+
+	montEncode(out, out)
+	return out
+}
+
+func (e *gfP) String() string {
+	return fmt.Sprintf("%16.16x%16.16x%16.16x%16.16x%16.16x%16.16x",
+		e[5], e[4], e[3], e[2], e[1], e[0])
+}
+
+func (e *gfP) Set(f *gfP) {
+	*e = *f
+}
+
+func (e *gfP) IsZero() bool {
+	return *e == gfP{}
+}
+
+// Invert sets e to f⁻¹ as f^(p-2).
+func (e *gfP) Invert(f *gfP) {
+	e.exp(f, pMinus2)
+}
+
+// Sqrt sets e to a square root of f as f^((p+1)/4), and reports whether f
+// is a square.
+func (e *gfP) Sqrt(f *gfP) bool {
+	s := &gfP{}
+	s.exp(f, pPlus1Over4)
+	t := &gfP{}
+	gfpMul(t, s, s)
+	if *t != *f {
+		return false
+	}
+	e.Set(s)
+	return true
+}
+
+func (e *gfP) exp(f *gfP, k [6]uint64) {
+	sum, power := &gfP{}, &gfP{}
+	sum.Set(rN1)
+	power.Set(f)
+
+	for word := 0; word < 6; word++ {
+		for bit := uint(0); bit < 64; bit++ {
+			if (k[word]>>bit)&1 == 1 {
+				gfpMul(sum, sum, power)
+			}
+			gfpMul(power, power, power)
+		}
+	}
+	e.Set(sum)
+}
+
+var (
+	pMinus2     = limbs(new(big.Int).Sub(P, big.NewInt(2)))
+	pPlus1Over4 = limbs(new(big.Int).Rsh(new(big.Int).Add(P, big.NewInt(1)), 2))
+)
+
+// Marshal writes e as 48 big-endian bytes.
+func (e *gfP) Marshal(out []byte) {
+	d := &gfP{}
+	montDecode(d, e)
+	for w := uint(0); w < 6; w++ {
+		for b := uint(0); b < 8; b++ {
+			out[8*w+b] = byte(d[5-w] >> (56 - 8*b))
+		}
+	}
+}
+
+// Unmarshal reads e from 48 big-endian bytes.
+func (e *gfP) Unmarshal(in []byte) error {
+	// Unmarshal the bytes into little endian form
+	for w := uint(0); w < 6; w++ {
+		e[5-w] = 0
+		for b := uint(0); b < 8; b++ {
+			e[5-w] += uint64(in[8*w+b]) << (56 - 8*b)
+		}
+	}
+	// Ensure the point respects the curve modulus
+	for i := 5; i >= 0; i-- {
+		if e[i] < p[i] {
+			montEncode(e, e)
+			return nil
+		}
+		if e[i] > p[i] {
+			return errors.New("bls12381: coordinate exceeds modulus")
+		}
+	}
+	return errors.New("bls12381: coordinate equals modulus")
+}
+
+func montEncode(c, a *gfP) { gfpMul(c, a, r2) }
+func montDecode(c, a *gfP) { gfpMul(c, a, &gfP{1}) }
+
+// gfpReduce subtracts p from a if a >= p, where head is the carry of a.
+func gfpReduce(a *gfP, head uint64) {
+	var b gfP
+	var borrow uint64
+	for i := range a {
+		b[i], borrow = bits.Sub64(a[i], p[i], borrow)
+	}
+	if head != 0 || borrow == 0 {
+		*a = b
+	}
+}
+
+func gfpNeg(c, a *gfP) {
+	if a.IsZero() {
+		*c = gfP{}
+		return
+	}
+	var borrow uint64
+	for i := range c {
+		c[i], borrow = bits.Sub64(p[i], a[i], borrow)
+	}
+}
+
+func gfpAdd(c, a, b *gfP) {
+	var carry uint64
+	for i := range c {
+		c[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	gfpReduce(c, carry)
+}
+
+func gfpSub(c, a, b *gfP) {
+	var borrow uint64
+	for i := range c {
+		c[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	if borrow != 0 {
+		var carry uint64
+		for i := range c {
+			c[i], carry = bits.Add64(c[i], p[i], carry)
+		}
+	}
+}
+
+// gfpMul sets c to a·b·R⁻¹ with the CIOS Montgomery multiplication.
+func gfpMul(c, a, b *gfP) {
+	var t [8]uint64
+	for i := 0; i < 6; i++ {
+		// t += a·b[i]
+		var carry uint64
+		for j := 0; j < 6; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, carry, 0)
+			hi += cc
+			t[j], carry = lo, hi
+		}
+		t[6], t[7] = bits.Add64(t[6], carry, 0)
+
+		// t = (t + m·p) / 2⁶⁴
+		m := t[0] * np
+		hi, lo := bits.Mul64(m, p[0])
+		_, cc := bits.Add64(lo, t[0], 0)
+		carry = hi + cc
+		for j := 1; j < 6; j++ {
+			hi, lo = bits.Mul64(m, p[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, carry, 0)
+			hi += cc
+			t[j-1], carry = lo, hi
+		}
+		t[5], cc = bits.Add64(t[6], carry, 0)
+		t[6] = t[7] + cc
+		t[7] = 0
+	}
+
+	copy(c[:], t[:6])
+	gfpReduce(c, t[6])
+}
@@ -0,0 +1,150 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// gfP2 implements a field of size p² as a quadratic extension of the base
+// field where u²=-1.
+type gfP2 struct {
+	x, y gfP // value is xu+y.
+}
+
+func (e *gfP2) String() string {
+	return "(" + e.x.String() + ", " + e.y.String() + ")"
+}
+
+func (e *gfP2) Set(a *gfP2) *gfP2 {
+	e.x.Set(&a.x)
+	e.y.Set(&a.y)
+	return e
+}
+
+func (e *gfP2) SetZero() *gfP2 {
+	e.x = gfP{}
+	e.y = gfP{}
+	return e
+}
+
+func (e *gfP2) SetOne() *gfP2 {
+	e.x = gfP{}
+	e.y = *rN1
+	return e
+}
+
+func (e *gfP2) IsZero() bool {
+	return e.x.IsZero() && e.y.IsZero()
+}
+
+func (e *gfP2) IsOne() bool {
+	return e.x.IsZero() && e.y == *rN1
+}
+
+func (e *gfP2) Conjugate(a *gfP2) *gfP2 {
+	e.y.Set(&a.y)
+	gfpNeg(&e.x, &a.x)
+	return e
+}
+
+func (e *gfP2) Neg(a *gfP2) *gfP2 {
+	gfpNeg(&e.x, &a.x)
+	gfpNeg(&e.y, &a.y)
+	return e
+}
+
+func (e *gfP2) Add(a, b *gfP2) *gfP2 {
+	gfpAdd(&e.x, &a.x, &b.x)
+	gfpAdd(&e.y, &a.y, &b.y)
+	return e
+}
+
+func (e *gfP2) Sub(a, b *gfP2) *gfP2 {
+	gfpSub(&e.x, &a.x, &b.x)
+	gfpSub(&e.y, &a.y, &b.y)
+	return e
+}
+
+// Mul sets e to a·b with Karatsuba multiplication:
+// (a.x·u+a.y)(b.x·u+b.y) = (a.x·b.y+a.y·b.x)u + a.y·b.y-a.x·b.x
+func (e *gfP2) Mul(a, b *gfP2) *gfP2 {
+	tx, ty, t := &gfP{}, &gfP{}, &gfP{}
+	gfpMul(ty, &a.y, &b.y)
+	gfpMul(t, &a.x, &b.x)
+
+	s1, s2 := &gfP{}, &gfP{}
+	gfpAdd(s1, &a.x, &a.y)
+	gfpAdd(s2, &b.x, &b.y)
+	gfpMul(tx, s1, s2)
+	gfpSub(tx, tx, ty)
+	gfpSub(tx, tx, t)
+	gfpSub(ty, ty, t)
+
+	e.x.Set(tx)
+	e.y.Set(ty)
+	return e
+}
+
+func (e *gfP2) MulScalar(a *gfP2, b *gfP) *gfP2 {
+	gfpMul(&e.x, &a.x, b)
+	gfpMul(&e.y, &a.y, b)
+	return e
+}
+
+// MulXi sets e=ξa where ξ=u+1 and then returns e.
+func (e *gfP2) MulXi(a *gfP2) *gfP2 {
+	// (xu+y)(u+1) = (x+y)u + y-x
+	tx, ty := &gfP{}, &gfP{}
+	gfpAdd(tx, &a.x, &a.y)
+	gfpSub(ty, &a.y, &a.x)
+
+	e.x.Set(tx)
+	e.y.Set(ty)
+	return e
+}
+
+func (e *gfP2) Square(a *gfP2) *gfP2 {
+	// (xu+y)² = 2xy·u + (y+x)(y-x)
+	tx, ty, t := &gfP{}, &gfP{}, &gfP{}
+	gfpSub(tx, &a.y, &a.x)
+	gfpAdd(ty, &a.x, &a.y)
+	gfpMul(ty, tx, ty)
+
+	gfpMul(tx, &a.x, &a.y)
+	gfpAdd(t, tx, tx)
+
+	e.x.Set(t)
+	e.y.Set(ty)
+	return e
+}
+
+func (e *gfP2) Invert(a *gfP2) *gfP2 {
+	// See "Implementing cryptographic pairings", M. Scott, section 3.2.
+	// ftp://136.206.11.249/pub/crypto/pairings.pdf
+	t1, t2 := &gfP{}, &gfP{}
+	gfpMul(t1, &a.x, &a.x)
+	gfpMul(t2, &a.y, &a.y)
+	gfpAdd(t1, t1, t2)
+
+	inv := &gfP{}
+	inv.Invert(t1)
+
+	gfpNeg(t1, &a.x)
+
+	gfpMul(&e.x, t1, inv)
+	gfpMul(&e.y, &a.y, inv)
+	return e
+}
+
+// Exp sets e to a^k and then returns e.
+func (e *gfP2) Exp(a *gfP2, k *big.Int) *gfP2 {
+	sum, t := (&gfP2{}).SetOne(), &gfP2{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		t.Square(sum)
+		if k.Bit(i) != 0 {
+			sum.Mul(t, a)
+		} else {
+			sum.Set(t)
+		}
+	}
+	return e.Set(sum)
+}
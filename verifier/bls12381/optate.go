@@ -0,0 +1,126 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// lineFunction returns the line through the point (xT, yT) of the twist
+// with slope lambda, untwisted and evaluated at p and multiplied by w³,
+// which the final exponentiation cancels:
+//
+//	(lambda·xT - yT) - lambda·xP·v + yP·v·w
+func lineFunction(lambda, xT, yT *gfP2, p *curvePoint) *gfP12 {
+	l := &gfP12{}
+	l.y.z.Mul(lambda, xT).Sub(&l.y.z, yT)
+	l.y.y.MulScalar(lambda, &p.x).Neg(&l.y.y)
+	l.x.y.y.Set(&p.y)
+	return l
+}
+
+// miller runs the Miller loop of the optimal ate pairing on affine points.
+// The loop is driven by |x| and the result is conjugated because x is
+// negative.
+func miller(q *twistPoint, p *curvePoint) *gfP12 {
+	ret := (&gfP12{}).SetOne()
+
+	aAffine := &twistPoint{}
+	aAffine.Set(q)
+	aAffine.MakeAffine()
+	bAffine := &curvePoint{}
+	bAffine.Set(p)
+	bAffine.MakeAffine()
+	if aAffine.IsInfinity() || bAffine.IsInfinity() {
+		return ret
+	}
+
+	xQ, yQ := &aAffine.x, &aAffine.y
+	xT, yT := (&gfP2{}).Set(xQ), (&gfP2{}).Set(yQ)
+	lambda, t, x3 := &gfP2{}, &gfP2{}, &gfP2{}
+
+	for i := u.BitLen() - 2; i >= 0; i-- {
+		ret.Square(ret)
+
+		// T = 2T with lambda = 3xT²/2yT
+		t.Add(yT, yT).Invert(t)
+		lambda.Square(xT)
+		x3.Add(lambda, lambda)
+		lambda.Add(lambda, x3).Mul(lambda, t)
+		ret.Mul(ret, lineFunction(lambda, xT, yT, bAffine))
+
+		x3.Square(lambda).Sub(x3, xT).Sub(x3, xT)
+		t.Sub(xT, x3).Mul(t, lambda)
+		yT.Sub(t, yT)
+		xT.Set(x3)
+
+		if u.Bit(i) == 0 {
+			continue
+		}
+
+		// T = T+Q with lambda = (yQ-yT)/(xQ-xT)
+		t.Sub(xQ, xT).Invert(t)
+		lambda.Sub(yQ, yT).Mul(lambda, t)
+		ret.Mul(ret, lineFunction(lambda, xT, yT, bAffine))
+
+		x3.Square(lambda).Sub(x3, xT).Sub(x3, xQ)
+		t.Sub(xT, x3).Mul(t, lambda)
+		yT.Sub(t, yT)
+		xT.Set(x3)
+	}
+
+	return ret.Conjugate(ret)
+}
+
+// hardPartExponent is (x-1)²/3. The hard part of the final exponentiation,
+// (p⁴-p²+1)/Order, is (x-1)²/3·(x+p)·(x²+p²-1) + 1.
+var hardPartExponent = func() *big.Int {
+	k := new(big.Int).Add(u, big.NewInt(1))
+	k.Mul(k, k)
+	return k.Div(k, big.NewInt(3))
+}()
+
+// expByX sets e to a^x for a in the cyclotomic subgroup.
+func (e *gfP12) expByX(a *gfP12) *gfP12 {
+	return e.Exp(a, u).Conjugate(e)
+}
+
+// finalExponentiation computes the (p¹²-1)/Order-th power of an element of
+// GF(p¹²) to obtain an element of GT.
+func finalExponentiation(in *gfP12) *gfP12 {
+	// easy part: in^((p⁶-1)(p²+1))
+	t1 := (&gfP12{}).Conjugate(in)
+	inv := (&gfP12{}).Invert(in)
+	t1.Mul(t1, inv)
+	t2 := (&gfP12{}).Frobenius(t1)
+	t2.Frobenius(t2)
+	t1.Mul(t1, t2)
+
+	// hard part, see "Efficient Final Exponentiation via Cyclotomic
+	// Structure for Pairings over Families of Elliptic Curves", Hayashida,
+	// Hayasaka and Teruya.
+	a := (&gfP12{}).Exp(t1, hardPartExponent)
+
+	// b = a^(x+p)
+	b := (&gfP12{}).expByX(a)
+	t2.Frobenius(a)
+	b.Mul(b, t2)
+
+	// c = b^(x²+p²-1)
+	c := (&gfP12{}).expByX(b)
+	c.expByX(c)
+	t2.Frobenius(b).Frobenius(t2)
+	c.Mul(c, t2)
+	t2.Conjugate(b)
+	c.Mul(c, t2)
+
+	return c.Mul(c, t1)
+}
+
+func optimalAte(a *twistPoint, b *curvePoint) *gfP12 {
+	e := miller(a, b)
+	ret := finalExponentiation(e)
+
+	if a.IsInfinity() || b.IsInfinity() {
+		ret.SetOne()
+	}
+	return ret
+}
@@ -8,7 +8,12 @@
 // Package bn256 implements the Optimal Ate pairing over a 256-bit Barreto-Naehrig curve.
 package bn256
 
-import bn256 "github.com/iden3/go-rapidsnark/verifier/bn256/google"
+import (
+	"errors"
+	"math/big"
+
+	bn256 "github.com/iden3/go-rapidsnark/verifier/bn256/google"
+)
 
 // G1 is an abstract cyclic group. The zero value is suitable for use as the
 // output of an operation, but cannot be used as an input.
@@ -18,7 +23,60 @@ type G1 = bn256.G1
 // output of an operation, but cannot be used as an input.
 type G2 = bn256.G2
 
+// GT is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type GT = bn256.GT
+
+// Pair calculates an Optimal Ate pairing.
+func Pair(g1 *G1, g2 *G2) *GT {
+	return bn256.Pair(g1, g2)
+}
+
 // PairingCheck calculates the Optimal Ate pairing for a set of points.
 func PairingCheck(a []*G1, b []*G2) bool {
 	return bn256.PairingCheck(a, b)
 }
+
+// MultiPair calculates the product of the Optimal Ate pairings of a set of
+// points.
+func MultiPair(a []*G1, b []*G2) *GT {
+	return bn256.MultiPair(a, b)
+}
+
+// G2Prepared is a point of G2 prepared to be used in many pairings.
+type G2Prepared = bn256.G2Prepared
+
+// NewG2Prepared precomputes the pairing data of q.
+func NewG2Prepared(q *G2) *G2Prepared {
+	return bn256.NewG2Prepared(q)
+}
+
+// PairingCheckPrepared reports whether the product of the pairings
+// e(a[i], b[i]) is equal to target. A nil target is the identity of GT.
+func PairingCheckPrepared(a []*G1, b []*G2Prepared, target *GT) bool {
+	return bn256.PairingCheckPrepared(a, b, target)
+}
+
+// UnmarshalGT converts the output of GT.Marshal back into an element of GT.
+func UnmarshalGT(m []byte) (*GT, error) {
+	const numBytes = 256 / 8
+	for i := 0; i+numBytes <= len(m); i += numBytes {
+		if new(big.Int).SetBytes(m[i:i+numBytes]).Cmp(bn256.P) >= 0 {
+			return nil, errors.New("bn256: coordinate exceeds modulus")
+		}
+	}
+	e, ok := new(GT).Unmarshal(m)
+	if !ok {
+		return nil, errors.New("bn256: not enough data")
+	}
+	return e, nil
+}
+
+// G1Table is a point of G1 with precomputed multiples for
+// G1.MultiScalarMultTable.
+type G1Table = bn256.G1Table
+
+// NewG1Table precomputes the multiples of p used by G1.MultiScalarMultTable.
+func NewG1Table(p *G1) *G1Table {
+	return bn256.NewG1Table(p)
+}
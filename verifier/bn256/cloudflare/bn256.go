@@ -303,6 +303,9 @@ func (e *G2) Unmarshal(m []byte) ([]byte, error) {
 		if !e.p.IsOnCurve() {
 			return nil, errors.New("bn256: malformed point")
 		}
+		if !e.p.IsInSubgroup() {
+			return nil, errors.New("bn256: point is not in G2")
+		}
 	}
 	return m[4*numBytes:], nil
 }
@@ -342,6 +345,21 @@ func PairingCheck(a []*G1, b []*G2) bool {
 	return finalExponentiation(acc).IsOne()
 }
 
+// MultiPair calculates the product of the Optimal Ate pairings of a set of
+// points with a single final exponentiation.
+func MultiPair(a []*G1, b []*G2) *GT {
+	acc := new(gfP12)
+	acc.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].p.IsInfinity() || b[i].p.IsInfinity() {
+			continue
+		}
+		acc.Mul(acc, miller(b[i].p, a[i].p))
+	}
+	return &GT{finalExponentiation(acc)}
+}
+
 // Miller applies Miller's algorithm, which is a bilinear function from the
 // source groups to F_p^12. Miller(g1, g2).Finalize() is equivalent to Pair(g1,
 // g2).
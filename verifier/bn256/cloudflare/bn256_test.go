@@ -3,6 +3,7 @@ package bn256
 import (
 	"bytes"
 	"crypto/rand"
+	"math/big"
 	"testing"
 )
 
@@ -127,3 +128,60 @@ func BenchmarkPairing(b *testing.B) {
 		Pair(&G1{curveGen}, &G2{twistGen})
 	}
 }
+
+func TestG2NegAffine(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	// an unmarshaled point is affine
+	q2 := new(G2)
+	if _, err := q2.Unmarshal(q.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !PairingCheck([]*G1{p1, p1}, []*G2{q2, new(G2).Neg(q2)}) {
+		t.Fatal("e(p, q)·e(p, -q) != 1")
+	}
+}
+
+func TestG2UnmarshalNotInSubgroup(t *testing.T) {
+	// (1, y) is on the twist but, as almost every point of the twist, not
+	// in the subgroup of order Order
+	x, _ := new(big.Int).SetString("1", 10)
+	y0, _ := new(big.Int).SetString("18278151005453108793778860132295291098363647455926340152056652516292830556603", 10)
+	y1, _ := new(big.Int).SetString("5912654199736721486680175016176231956195085055698687135131307249486702594212", 10)
+	m := make([]byte, 128)
+	x.FillBytes(m[32:64])
+	y1.FillBytes(m[64:96])
+	y0.FillBytes(m[96:128])
+
+	_, err := new(G2).Unmarshal(m)
+	if err == nil || err.Error() != "bn256: point is not in G2" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// y+1 is not on the twist
+	y0.Add(y0, big.NewInt(1)).FillBytes(m[96:128])
+	_, err = new(G2).Unmarshal(m)
+	if err == nil || err.Error() != "bn256: malformed point" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiPair(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG1(rand.Reader)
+	_, q1, _ := RandomG2(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+
+	e := new(GT).Add(Pair(p1, q1), Pair(p2, q2))
+	if !bytes.Equal(MultiPair([]*G1{p1, p2}, []*G2{q1, q2}).Marshal(),
+		e.Marshal()) {
+		t.Fatal("MultiPair is not the product of the pairings")
+	}
+
+	inf := new(G1).ScalarBaseMult(big.NewInt(0))
+	if !bytes.Equal(MultiPair([]*G1{p1, inf}, []*G2{q1, q2}).Marshal(),
+		Pair(p1, q1).Marshal()) {
+		t.Fatal("MultiPair of the point at infinity is not one")
+	}
+}
@@ -50,6 +50,32 @@ func (e *gfP) Invert(f *gfP) {
 	e.Set(sum)
 }
 
+// Sqrt sets e to a square root of f and reports whether f is a square. As
+// p ≡ 3 mod 4, the square root is f^((p+1)/4).
+func (e *gfP) Sqrt(f *gfP) bool {
+	bits := [4]uint64{0x4f082305b61f3f52, 0x65e05aa45a1c72a3, 0x6e14116da0605617, 0x0c19139cb84c680a}
+
+	sum, power := &gfP{}, &gfP{}
+	sum.Set(newGFp(1))
+	power.Set(f)
+
+	for word := 0; word < 4; word++ {
+		for bit := uint(0); bit < 64; bit++ {
+			if (bits[word]>>bit)&1 == 1 {
+				gfpMul(sum, sum, power)
+			}
+			gfpMul(power, power, power)
+		}
+	}
+
+	gfpMul(power, sum, sum)
+	if *power != *f {
+		return false
+	}
+	e.Set(sum)
+	return true
+}
+
 func (e *gfP) Marshal(out []byte) {
 	for w := uint(0); w < 4; w++ {
 		for b := uint(0); b < 8; b++ {
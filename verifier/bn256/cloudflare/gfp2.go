@@ -154,3 +154,54 @@ func (e *gfP2) Invert(a *gfP2) *gfP2 {
 	gfpMul(&e.y, &a.y, inv)
 	return e
 }
+
+// gfpHalf is 1/2 in Montgomery form.
+var gfpHalf = &gfP{0x87bee7d24f060572, 0xd0fd2add2f1c6ae5, 0x8f5f7492fcfd4f44, 0x1f37631a3d9cbfac}
+
+// Sqrt sets e to a square root of a and reports whether a is a square. If
+// xi+y is the square of ci+d, then d² = (y ± √(x²+y²))/2, only one of them
+// being a square when x ≠ 0 as -1 is not a square, and c = x/2d.
+func (e *gfP2) Sqrt(a *gfP2) bool {
+	zero := gfP{0}
+	if a.x == zero {
+		s := &gfP{}
+		if s.Sqrt(&a.y) {
+			e.x, e.y = zero, *s
+			return true
+		}
+		// (si)² = -s²
+		gfpNeg(s, &a.y)
+		if s.Sqrt(s) {
+			e.x, e.y = *s, zero
+			return true
+		}
+		return false
+	}
+
+	n, t := &gfP{}, &gfP{}
+	gfpMul(n, &a.x, &a.x)
+	gfpMul(t, &a.y, &a.y)
+	gfpAdd(n, n, t)
+	if !n.Sqrt(n) {
+		return false
+	}
+
+	d := &gfP{}
+	gfpAdd(t, &a.y, n)
+	gfpMul(t, t, gfpHalf)
+	if !d.Sqrt(t) {
+		gfpSub(t, &a.y, n)
+		gfpMul(t, t, gfpHalf)
+		if !d.Sqrt(t) {
+			return false
+		}
+	}
+
+	c := &gfP{}
+	c.Invert(d)
+	gfpMul(c, c, &a.x)
+	gfpMul(c, c, gfpHalf)
+
+	e.x, e.y = *c, *d
+	return true
+}
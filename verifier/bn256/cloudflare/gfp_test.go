@@ -58,3 +58,27 @@ func TestGFpMul(t *testing.T) {
 		t.Errorf("multiplication mismatch: have %#x, want %#x", *h, *w)
 	}
 }
+
+func TestGFp2Sqrt(t *testing.T) {
+	for _, a := range []*gfP2{
+		{*newGFp(0), *newGFp(4)},
+		// (2i)² = -4 is not a square in GF(p)
+		{*newGFp(2), *newGFp(0)},
+		{*newGFp(23423492374), *newGFp(1293487239847239)},
+		{*newGFp(-1), *newGFp(-2)},
+	} {
+		sq := (&gfP2{}).Square(a)
+		r := &gfP2{}
+		if !r.Sqrt(sq) {
+			t.Fatalf("%v is a square", sq)
+		}
+		if r2 := (&gfP2{}).Square(r); *r2 != *sq {
+			t.Fatalf("bad square root of %v: %v", sq, r)
+		}
+	}
+
+	// ξ = i+9 is not a square
+	if (&gfP2{}).Sqrt(&gfP2{*newGFp(1), *newGFp(9)}) {
+		t.Fatal("i+9 is not a square")
+	}
+}
@@ -0,0 +1,181 @@
+package bn256
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// tableWindow is the window size, in bits, of the precomputed tables of
+// G1Table.
+const tableWindow = 4
+
+// msmMinPoints is the number of points from which the bucket method is
+// faster than the GLV scalar multiplication of every point.
+const msmMinPoints = 32
+
+// G1Table is a point of G1 with precomputed multiples, for multi-scalar
+// multiplications with a fixed set of points, like the IC points of a
+// verification key.
+type G1Table struct {
+	// windows[w] is 2^(tableWindow·w)·p
+	windows []curvePoint
+}
+
+// NewG1Table precomputes the multiples of p used by MultiScalarMultTable.
+func NewG1Table(p *G1) *G1Table {
+	n := (Order.BitLen() + tableWindow - 1) / tableWindow
+	t := &G1Table{windows: make([]curvePoint, n)}
+
+	q := &curvePoint{}
+	q.Set(p.p)
+	q.MakeAffine()
+	d := &curvePoint{}
+	for w := range t.windows {
+		t.windows[w].Set(q)
+		t.windows[w].MakeAffine()
+		for i := 0; i < tableWindow; i++ {
+			d.Double(q)
+			q.Set(d)
+		}
+	}
+	return t
+}
+
+// MultiScalarMult sets e to ∑ scalars[i]·points[i] using Pippenger's bucket
+// method and then returns e.
+func (e *G1) MultiScalarMult(points []*G1, scalars []*big.Int) *G1 {
+	if len(points) != len(scalars) {
+		panic("bn256: len(points) != len(scalars)")
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+
+	if len(points) < msmMinPoints {
+		sum, t, k := &curvePoint{}, &curvePoint{}, &curvePoint{}
+		sum.SetInfinity()
+		for i := range points {
+			k.Mul(points[i].p, scalars[i])
+			t.Add(sum, k)
+			sum.Set(t)
+		}
+		e.p.Set(sum)
+		return e
+	}
+
+	ks, maxBits := reduceScalars(scalars)
+	c := msmWindow(len(points))
+
+	buckets := make([]curvePoint, 1<<c-1)
+	sum, total, running, t := &curvePoint{}, &curvePoint{}, &curvePoint{}, &curvePoint{}
+	sum.SetInfinity()
+	for w := (maxBits+c-1)/c - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			t.Double(sum)
+			sum.Set(t)
+		}
+
+		for b := range buckets {
+			buckets[b].SetInfinity()
+		}
+		for i, k := range ks {
+			if d := window(k, w*c, c); d != 0 {
+				t.Add(&buckets[d-1], points[i].p)
+				buckets[d-1].Set(t)
+			}
+		}
+
+		sumBuckets(total, running, t, buckets)
+		t.Add(sum, total)
+		sum.Set(t)
+	}
+
+	e.p.Set(sum)
+	return e
+}
+
+// MultiScalarMultTable sets e to ∑ scalars[i]·p_i, where tables[i] is the
+// table of p_i, and then returns e.
+func (e *G1) MultiScalarMultTable(tables []*G1Table, scalars []*big.Int) *G1 {
+	if len(tables) != len(scalars) {
+		panic("bn256: len(tables) != len(scalars)")
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+
+	ks, maxBits := reduceScalars(scalars)
+
+	// every window of every point has its own multiple in the table, so all
+	// the windows share the buckets and no doubling is needed
+	var buckets [1<<tableWindow - 1]curvePoint
+	for b := range buckets {
+		buckets[b].SetInfinity()
+	}
+	t := &curvePoint{}
+	for i, k := range ks {
+		for w := 0; w*tableWindow < maxBits; w++ {
+			if d := window(k, w*tableWindow, tableWindow); d != 0 {
+				t.Add(&buckets[d-1], &tables[i].windows[w])
+				buckets[d-1].Set(t)
+			}
+		}
+	}
+
+	total, running := &curvePoint{}, &curvePoint{}
+	sumBuckets(total, running, t, buckets[:])
+	e.p.Set(total)
+	return e
+}
+
+// sumBuckets sets total to ∑ (b+1)·buckets[b]. running and t are used as
+// temporary points.
+func sumBuckets(total, running, t *curvePoint, buckets []curvePoint) {
+	total.SetInfinity()
+	running.SetInfinity()
+	for b := len(buckets) - 1; b >= 0; b-- {
+		t.Add(running, &buckets[b])
+		running.Set(t)
+		t.Add(total, running)
+		total.Set(t)
+	}
+}
+
+// reduceScalars reduces the scalars modulo Order and returns them together
+// with the size in bits of the biggest one.
+func reduceScalars(scalars []*big.Int) ([]*big.Int, int) {
+	ks := make([]*big.Int, len(scalars))
+	maxBits := 0
+	for i, k := range scalars {
+		if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+			k = new(big.Int).Mod(k, Order)
+		}
+		ks[i] = k
+		if k.BitLen() > maxBits {
+			maxBits = k.BitLen()
+		}
+	}
+	return ks, maxBits
+}
+
+// msmWindow returns the window size, in bits, of a multi-scalar
+// multiplication of n points.
+func msmWindow(n int) int {
+	c := bits.Len(uint(n))
+	if c < 2 {
+		return 2
+	}
+	if c > 16 {
+		return 16
+	}
+	return c
+}
+
+// window returns the c bits of k starting at bit i.
+func window(k *big.Int, i, c int) int {
+	d := 0
+	for j := c - 1; j >= 0; j-- {
+		d = d<<1 | int(k.Bit(i+j))
+	}
+	return d
+}
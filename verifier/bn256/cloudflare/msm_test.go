@@ -0,0 +1,56 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 40} {
+		points := make([]*G1, n)
+		tables := make([]*G1Table, n)
+		scalars := make([]*big.Int, n)
+		want := new(G1).ScalarBaseMult(big.NewInt(0))
+		for i := range points {
+			_, points[i], _ = RandomG1(rand.Reader)
+			tables[i] = NewG1Table(points[i])
+			scalars[i], _ = rand.Int(rand.Reader, Order)
+			want = new(G1).Add(want, new(G1).ScalarMult(points[i], scalars[i]))
+		}
+		if n > 2 {
+			// small, repeated and out of range scalars
+			scalars[0] = big.NewInt(1)
+			scalars[1] = new(big.Int).Add(scalars[2], Order)
+			points[1], tables[1] = points[2], tables[2]
+			want = new(G1).ScalarBaseMult(big.NewInt(0))
+			for i := range points {
+				want = new(G1).Add(want, new(G1).ScalarMult(points[i], scalars[i]))
+			}
+		}
+
+		got := new(G1).MultiScalarMult(points, scalars)
+		if !bytes.Equal(got.Marshal(), want.Marshal()) {
+			t.Fatalf("MultiScalarMult of %v points differs from ScalarMult", n)
+		}
+		got = new(G1).MultiScalarMultTable(tables, scalars)
+		if !bytes.Equal(got.Marshal(), want.Marshal()) {
+			t.Fatalf("MultiScalarMultTable of %v points differs from ScalarMult", n)
+		}
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	points := make([]*G1, 100)
+	scalars := make([]*big.Int, len(points))
+	for i := range points {
+		_, points[i], _ = RandomG1(rand.Reader)
+		scalars[i], _ = rand.Int(rand.Reader, Order)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(G1).MultiScalarMult(points, scalars)
+	}
+}
@@ -0,0 +1,133 @@
+package bn256
+
+// lineCoeffs are the coefficients of a line of the Miller loop. They only
+// depend on the G2 point, the line is evaluated at a G1 point p as
+// (a, b·p.x, c·p.y).
+type lineCoeffs struct {
+	a, b, c gfP2
+	// double is set for the lines of the doubling steps, which start a new
+	// iteration of the loop.
+	double bool
+}
+
+// G2Prepared is a point of G2 with the lines of the Miller loop
+// precomputed, so pairings with a fixed G2 point, like the ones of a
+// verification key, are cheaper.
+type G2Prepared struct {
+	lines []lineCoeffs
+}
+
+// NewG2Prepared precomputes the Miller loop lines of q.
+func NewG2Prepared(q *G2) *G2Prepared {
+	if q.p == nil || q.p.IsInfinity() {
+		return &G2Prepared{}
+	}
+
+	// the lines evaluated at (1, 1) give the coefficients before the
+	// multiplication by the coordinates of the G1 point
+	one := &curvePoint{}
+	one.x.Set(newGFp(1))
+	one.y.Set(newGFp(1))
+	one.z.Set(newGFp(1))
+	one.t.Set(newGFp(1))
+
+	prep := &G2Prepared{lines: make([]lineCoeffs, 0, len(sixuPlus2NAF)+28)}
+	add := func(a, b, c *gfP2, double bool) {
+		prep.lines = append(prep.lines,
+			lineCoeffs{a: *a, b: *b, c: *c, double: double})
+	}
+
+	// same steps as miller
+	aAffine := &twistPoint{}
+	aAffine.Set(q.p)
+	aAffine.MakeAffine()
+
+	minusA := &twistPoint{}
+	minusA.Neg(aAffine)
+
+	r := &twistPoint{}
+	r.Set(aAffine)
+
+	r2 := (&gfP2{}).Square(&aAffine.y)
+
+	for i := len(sixuPlus2NAF) - 1; i > 0; i-- {
+		a, b, c, newR := lineFunctionDouble(r, one)
+		add(a, b, c, true)
+		r = newR
+
+		switch sixuPlus2NAF[i-1] {
+		case 1:
+			a, b, c, newR = lineFunctionAdd(r, aAffine, one, r2)
+		case -1:
+			a, b, c, newR = lineFunctionAdd(r, minusA, one, r2)
+		default:
+			continue
+		}
+		add(a, b, c, false)
+		r = newR
+	}
+
+	q1 := &twistPoint{}
+	q1.x.Conjugate(&aAffine.x).Mul(&q1.x, xiToPMinus1Over3)
+	q1.y.Conjugate(&aAffine.y).Mul(&q1.y, xiToPMinus1Over2)
+	q1.z.SetOne()
+	q1.t.SetOne()
+
+	minusQ2 := &twistPoint{}
+	minusQ2.x.MulScalar(&aAffine.x, xiToPSquaredMinus1Over3)
+	minusQ2.y.Set(&aAffine.y)
+	minusQ2.z.SetOne()
+	minusQ2.t.SetOne()
+
+	r2.Square(&q1.y)
+	a, b, c, newR := lineFunctionAdd(r, q1, one, r2)
+	add(a, b, c, false)
+	r = newR
+
+	r2.Square(&minusQ2.y)
+	a, b, c, _ = lineFunctionAdd(r, minusQ2, one, r2)
+	add(a, b, c, false)
+
+	return prep
+}
+
+// millerPrepared is miller with the lines of q precomputed.
+func millerPrepared(q *G2Prepared, p *curvePoint) *gfP12 {
+	ret := (&gfP12{}).SetOne()
+
+	bAffine := &curvePoint{}
+	bAffine.Set(p)
+	bAffine.MakeAffine()
+
+	b, c := &gfP2{}, &gfP2{}
+	for i := range q.lines {
+		l := &q.lines[i]
+		if l.double && i != 0 {
+			ret.Square(ret)
+		}
+		b.MulScalar(&l.b, &bAffine.x)
+		c.MulScalar(&l.c, &bAffine.y)
+		mulLine(ret, &l.a, b, c)
+	}
+	return ret
+}
+
+// PairingCheckPrepared reports whether the product of the pairings
+// e(a[i], b[i]) is equal to target. A nil target is the identity of GT.
+// The Miller loops of all the pairs share a single final exponentiation.
+func PairingCheckPrepared(a []*G1, b []*G2Prepared, target *GT) bool {
+	acc := new(gfP12)
+	acc.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].p.IsInfinity() || len(b[i].lines) == 0 {
+			continue
+		}
+		acc.Mul(acc, millerPrepared(b[i], a[i].p))
+	}
+	ret := finalExponentiation(acc)
+	if target == nil {
+		return ret.IsOne()
+	}
+	return string((&GT{ret}).Marshal()) == string(target.Marshal())
+}
@@ -0,0 +1,65 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMillerPrepared(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+
+	want := miller(p2.p, p1.p)
+	got := millerPrepared(NewG2Prepared(p2), p1.p)
+	if *got != *want {
+		t.Fatal("prepared Miller loop differs from miller")
+	}
+}
+
+func TestPairingCheckPrepared(t *testing.T) {
+	a, p1, _ := RandomG1(rand.Reader)
+	b, p2, _ := RandomG2(rand.Reader)
+
+	// e(a·G1, b·G2)·e(-ab·G1, G2) = 1
+	ab := new(big.Int).Mul(a, b)
+	p3 := new(G1).ScalarBaseMult(ab)
+	p3.Neg(p3)
+	g2 := NewG2Prepared(new(G2).ScalarBaseMult(big.NewInt(1)))
+
+	if !PairingCheckPrepared([]*G1{p1, p3},
+		[]*G2Prepared{NewG2Prepared(p2), g2}, nil) {
+		t.Fatal("pairing check failed")
+	}
+	if PairingCheckPrepared([]*G1{p1, p1},
+		[]*G2Prepared{NewG2Prepared(p2), g2}, nil) {
+		t.Fatal("pairing check must fail")
+	}
+
+	target := Pair(p1, p2)
+	if !PairingCheckPrepared([]*G1{p1}, []*G2Prepared{NewG2Prepared(p2)},
+		target) {
+		t.Fatal("pairing check with target failed")
+	}
+	if PairingCheckPrepared([]*G1{p3}, []*G2Prepared{NewG2Prepared(p2)},
+		target) {
+		t.Fatal("pairing check with target must fail")
+	}
+
+	// the point at infinity is skipped
+	inf := new(G2).ScalarBaseMult(big.NewInt(0))
+	if !PairingCheckPrepared([]*G1{p1}, []*G2Prepared{NewG2Prepared(inf)},
+		nil) {
+		t.Fatal("pairing check with infinity failed")
+	}
+}
+
+func BenchmarkPairingCheckPrepared(b *testing.B) {
+	g1 := []*G1{{curveGen}}
+	g2 := []*G2Prepared{NewG2Prepared(&G2{twistGen})}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		PairingCheckPrepared(g1, g2, nil)
+	}
+}
@@ -54,9 +54,12 @@ func (c *twistPoint) IsOnCurve() bool {
 	y2.Square(&c.y)
 	x3.Square(&c.x).Mul(x3, &c.x).Add(x3, twistB)
 
-	if *y2 != *x3 {
-		return false
-	}
+	return *y2 == *x3
+}
+
+// IsInSubgroup returns true iff c is in the subgroup of order Order of the
+// twist, that is G₂.
+func (c *twistPoint) IsInSubgroup() bool {
 	cneg := &twistPoint{}
 	cneg.Mul(c, Order)
 	return cneg.z.IsZero()
@@ -200,5 +203,5 @@ func (c *twistPoint) Neg(a *twistPoint) {
 	c.x.Set(&a.x)
 	c.y.Neg(&a.y)
 	c.z.Set(&a.z)
-	c.t.SetZero()
+	c.t.Set(&a.t)
 }
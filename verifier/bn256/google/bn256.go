@@ -223,6 +223,15 @@ func (e *G2) Add(a, b *G2) *G2 {
 	return e
 }
 
+// Neg sets e to -a and then returns e.
+func (e *G2) Neg(a *G2) *G2 {
+	if e.p == nil {
+		e.p = newTwistPoint(nil)
+	}
+	e.p.Negative(a.p, nil)
+	return e
+}
+
 // Marshal converts n into a byte slice.
 func (n *G2) Marshal() []byte {
 	// Each value is a 256-bit number.
@@ -292,6 +301,9 @@ func (e *G2) Unmarshal(m []byte) ([]byte, error) {
 		if !e.p.IsOnCurve() {
 			return nil, errors.New("bn256: malformed point")
 		}
+		if !e.p.IsInSubgroup() {
+			return nil, errors.New("bn256: point is not in G2")
+		}
 	}
 	return m[4*numBytes:], nil
 }
@@ -424,6 +436,26 @@ func PairingCheck(a []*G1, b []*G2) bool {
 	return ret.IsOne()
 }
 
+// MultiPair calculates the product of the Optimal Ate pairings of a set of
+// points with a single final exponentiation.
+func MultiPair(a []*G1, b []*G2) *GT {
+	pool := new(bnPool)
+
+	acc := newGFp12(pool)
+	acc.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].p.IsInfinity() || b[i].p.IsInfinity() {
+			continue
+		}
+		acc.Mul(acc, miller(b[i].p, a[i].p, pool), pool)
+	}
+	ret := finalExponentiation(acc, pool)
+	acc.Put(pool)
+
+	return &GT{ret}
+}
+
 // bnPool implements a tiny cache of *big.Int objects that's used to reduce the
 // number of allocations made during processing.
 type bnPool struct {
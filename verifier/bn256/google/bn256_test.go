@@ -35,6 +35,34 @@ func TestGFp2Invert(t *testing.T) {
 	}
 }
 
+func TestGFp2Sqrt(t *testing.T) {
+	for _, c := range [][2]int64{
+		{0, 4},
+		// (2i)² = -4 is not a square in GF(p)
+		{2, 0},
+		{23423492374, 1293487239847239},
+		{-1, -2},
+	} {
+		a := &gfP2{big.NewInt(c[0]), big.NewInt(c[1])}
+		sq := newGFp2(nil).Square(a, nil)
+		r := newGFp2(nil)
+		if !r.Sqrt(sq) {
+			t.Fatalf("%v is a square", sq)
+		}
+		r2 := newGFp2(nil).Square(r, nil)
+		r2.Minimal()
+		sq.Minimal()
+		if r2.x.Cmp(sq.x) != 0 || r2.y.Cmp(sq.y) != 0 {
+			t.Fatalf("bad square root of %v: %v", sq, r)
+		}
+	}
+
+	// ξ = i+9 is not a square
+	if newGFp2(nil).Sqrt(&gfP2{big.NewInt(1), big.NewInt(9)}) {
+		t.Fatal("i+9 is not a square")
+	}
+}
+
 func isZero(n *big.Int) bool {
 	return new(big.Int).Mod(n, P).Int64() == 0
 }
@@ -309,3 +337,69 @@ func BenchmarkPairing(b *testing.B) {
 		Pair(&G1{curveGen}, &G2{twistGen})
 	}
 }
+
+func TestG2NegAffine(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	// an unmarshaled point is affine
+	q2 := new(G2)
+	if _, err := q2.Unmarshal(q.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !PairingCheck([]*G1{p1, p1}, []*G2{q2, new(G2).Neg(q2)}) {
+		t.Fatal("e(p, q)·e(p, -q) != 1")
+	}
+}
+
+func TestG2NegInPlace(t *testing.T) {
+	_, q, _ := RandomG2(rand.Reader)
+	want := new(G2).Neg(q).Marshal()
+
+	if got := q.Neg(q).Marshal(); !bytes.Equal(got, want) {
+		t.Fatalf("q.Neg(q) = %x, want %x", got, want)
+	}
+}
+
+func TestG2UnmarshalNotInSubgroup(t *testing.T) {
+	// (1, y) is on the twist but, as almost every point of the twist, not
+	// in the subgroup of order Order
+	x, _ := new(big.Int).SetString("1", 10)
+	y0, _ := new(big.Int).SetString("18278151005453108793778860132295291098363647455926340152056652516292830556603", 10)
+	y1, _ := new(big.Int).SetString("5912654199736721486680175016176231956195085055698687135131307249486702594212", 10)
+	m := make([]byte, 128)
+	x.FillBytes(m[32:64])
+	y1.FillBytes(m[64:96])
+	y0.FillBytes(m[96:128])
+
+	_, err := new(G2).Unmarshal(m)
+	if err == nil || err.Error() != "bn256: point is not in G2" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// y+1 is not on the twist
+	y0.Add(y0, big.NewInt(1)).FillBytes(m[96:128])
+	_, err = new(G2).Unmarshal(m)
+	if err == nil || err.Error() != "bn256: malformed point" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiPair(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG1(rand.Reader)
+	_, q1, _ := RandomG2(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+
+	e := new(GT).Add(Pair(p1, q1), Pair(p2, q2))
+	if !bytes.Equal(MultiPair([]*G1{p1, p2}, []*G2{q1, q2}).Marshal(),
+		e.Marshal()) {
+		t.Fatal("MultiPair is not the product of the pairings")
+	}
+
+	inf := new(G1).ScalarBaseMult(big.NewInt(0))
+	if !bytes.Equal(MultiPair([]*G1{p1, inf}, []*G2{q1, q2}).Marshal(),
+		Pair(p1, q1).Marshal()) {
+		t.Fatal("MultiPair of the point at infinity is not one")
+	}
+}
@@ -0,0 +1,184 @@
+package bn256
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+// The compressed encodings of the points keep the x coordinate and two flags
+// in the most significant bits of the first byte, as gnark-crypto does: the
+// point at infinity or whether y is the lexicographically largest of y and
+// -y.
+const (
+	compressedMask     = 0b11 << 6
+	compressedSmallest = 0b10 << 6
+	compressedLargest  = 0b11 << 6
+	compressedInfinity = 0b01 << 6
+)
+
+// halfP is (p-1)/2 as a 32-byte big-endian number: y is the largest of y and
+// -y if it is greater than halfP.
+var halfP = []byte{
+	0x18, 0x32, 0x27, 0x39, 0x70, 0x98, 0xd0, 0x14,
+	0xdc, 0x28, 0x22, 0xdb, 0x40, 0xc0, 0xac, 0x2e,
+	0xcb, 0xc0, 0xb5, 0x48, 0xb4, 0x38, 0xe5, 0x46,
+	0x9e, 0x10, 0x46, 0x0b, 0x6c, 0x3e, 0x7e, 0xa3,
+}
+
+// MarshalCompressed converts e into a 32-byte slice: the x coordinate with
+// the compression flags.
+func (e *G1) MarshalCompressed() []byte {
+	m := e.Marshal()
+	ret := m[:32]
+	if allZero(m) {
+		ret[0] = compressedInfinity
+		return ret
+	}
+	if isLargest(m[32:]) {
+		ret[0] |= compressedLargest
+	} else {
+		ret[0] |= compressedSmallest
+	}
+	return ret
+}
+
+// UnmarshalCompressed sets e to the result of converting the output of
+// MarshalCompressed back into a group element and then returns e.
+func (e *G1) UnmarshalCompressed(m []byte) ([]byte, error) {
+	const numBytes = 256 / 8
+	if len(m) < numBytes {
+		return nil, errors.New("bn256: not enough data")
+	}
+	buf := make([]byte, 2*numBytes)
+	flags, err := uncompressedX(buf[:numBytes], m[:numBytes])
+	if err != nil {
+		return nil, err
+	}
+
+	if flags != compressedInfinity {
+		x := new(big.Int).SetBytes(buf[:numBytes])
+		if x.Cmp(P) >= 0 {
+			return nil, errors.New("bn256: coordinate exceeds modulus")
+		}
+		y := new(big.Int).Mul(x, x)
+		y.Mul(y, x).Add(y, curveB).Mod(y, P)
+		if y.ModSqrt(y, P) == nil {
+			return nil, errors.New("bn256: malformed point")
+		}
+
+		y.FillBytes(buf[numBytes:])
+		if isLargest(buf[numBytes:]) != (flags == compressedLargest) {
+			y.Sub(P, y).FillBytes(buf[numBytes:])
+		}
+	}
+
+	if _, err = e.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return m[numBytes:], nil
+}
+
+// MarshalCompressed converts e into a 64-byte slice: the x coordinate, the
+// imaginary part first, with the compression flags.
+func (e *G2) MarshalCompressed() []byte {
+	m := e.Marshal()
+	ret := m[:64]
+	if allZero(m) {
+		ret[0] = compressedInfinity
+		return ret
+	}
+	if isLargestGFp2(m[64:]) {
+		ret[0] |= compressedLargest
+	} else {
+		ret[0] |= compressedSmallest
+	}
+	return ret
+}
+
+// UnmarshalCompressed sets e to the result of converting the output of
+// MarshalCompressed back into a group element and then returns e.
+func (e *G2) UnmarshalCompressed(m []byte) ([]byte, error) {
+	const numBytes = 256 / 8
+	if len(m) < 2*numBytes {
+		return nil, errors.New("bn256: not enough data")
+	}
+	buf := make([]byte, 4*numBytes)
+	flags, err := uncompressedX(buf[:2*numBytes], m[:2*numBytes])
+	if err != nil {
+		return nil, err
+	}
+
+	if flags != compressedInfinity {
+		x, y := newGFp2(nil), newGFp2(nil)
+		x.x.SetBytes(buf[:numBytes])
+		x.y.SetBytes(buf[numBytes : 2*numBytes])
+		if x.x.Cmp(P) >= 0 || x.y.Cmp(P) >= 0 {
+			return nil, errors.New("bn256: coordinate exceeds modulus")
+		}
+		y.Square(x, nil)
+		y.Mul(y, x, nil)
+		y.Add(y, twistB)
+		if !y.Sqrt(y) {
+			return nil, errors.New("bn256: malformed point")
+		}
+
+		y.x.FillBytes(buf[2*numBytes : 3*numBytes])
+		y.y.FillBytes(buf[3*numBytes:])
+		if isLargestGFp2(buf[2*numBytes:]) != (flags == compressedLargest) {
+			y.Negative(y)
+			y.Minimal()
+			y.x.FillBytes(buf[2*numBytes : 3*numBytes])
+			y.y.FillBytes(buf[3*numBytes:])
+		}
+	}
+
+	if _, err = e.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return m[2*numBytes:], nil
+}
+
+// uncompressedX copies the x coordinate of the compressed point m to x
+// without the flags, which it returns. The coordinates of the point at
+// infinity must be zero.
+func uncompressedX(x, m []byte) (byte, error) {
+	flags := m[0] & compressedMask
+	copy(x, m)
+	x[0] &^= compressedMask
+
+	switch flags {
+	case compressedSmallest, compressedLargest:
+	case compressedInfinity:
+		if !allZero(x) {
+			return 0, errors.New("bn256: malformed point")
+		}
+	default:
+		return 0, errors.New("bn256: point is not compressed")
+	}
+	return flags, nil
+}
+
+// isLargest reports whether the 32-byte big-endian element y of GF(p) is
+// greater than -y.
+func isLargest(y []byte) bool {
+	return bytes.Compare(y, halfP) > 0
+}
+
+// isLargestGFp2 reports whether the element y of GF(p²), marshaled with the
+// imaginary part first, is lexicographically greater than -y.
+func isLargestGFp2(y []byte) bool {
+	if allZero(y[:32]) {
+		return isLargest(y[32:])
+	}
+	return isLargest(y[:32])
+}
+
+func allZero(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// compressedVectors are the compressed encodings of k·g₁ and k·g₂ computed
+// with gnark-crypto.
+var compressedVectors = []struct {
+	k      int64
+	g1, g2 string
+}{
+	{0,
+		"4000000000000000000000000000000000000000000000000000000000000000",
+		"4000000000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000000000000000000000000000000000000000"},
+	{1,
+		"8000000000000000000000000000000000000000000000000000000000000001",
+		"998e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2" +
+			"1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed"},
+	{2,
+		"830644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd3",
+		"e03e205db4f19b37b60121b83a7333706db86431c6d835849957ed8c3928ad79" +
+			"27dc7234fd11d3e8c36c59277c3e6f149d5cd3cfa9a62aee49f8130962b4b3b9"},
+	{3,
+		"c769bf9ac56bea3ff40232bcb1b6bd159315d84715b8e679f2d355961915abf0",
+		"9014772f57bb9742735191cd5dcfe4ebbc04156b6878a0a7c9824f32ffb66e85" +
+			"06064e784db10e9051e52826e192715e8d7e478cb09a5e0012defa0694fbc7f5"},
+	{7,
+		"97072b2ed3bb8d759a5325f477629386cb6fc6ecb801bd76983a6b86abffe078",
+		"a903ba015a9abde26a5d081e84551e63be0fd4516e46ee6d593edeba46362455" +
+			"224bdc5d4327fcf8ed702e01de1c2f1657a253ba75e32a89c390142aaa28b308"},
+	{1234567,
+		"8ba173a9155665e0f39b925d3118c2e68a63e5da3563e34603ffc5eb3e638584",
+		"d0645339fdc868892703e87b0d0f0e2549271dead58a1c099a213ead44ecce14" +
+			"25e244a7842cccff3f3e0cf4d9b40f567d59c54a7c2ac0d2c972ac796cb266bb"},
+}
+
+func TestCompressedVectors(t *testing.T) {
+	for _, v := range compressedVectors {
+		p := new(G1).ScalarBaseMult(big.NewInt(v.k))
+		if got := hex.EncodeToString(p.MarshalCompressed()); got != v.g1 {
+			t.Fatalf("%v·g₁: got %v, want %v", v.k, got, v.g1)
+		}
+		m, _ := hex.DecodeString(v.g1)
+		p2 := new(G1)
+		if _, err := p2.UnmarshalCompressed(m); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p.Marshal(), p2.Marshal()) {
+			t.Fatalf("%v·g₁: points are different", v.k)
+		}
+
+		q := new(G2).ScalarBaseMult(big.NewInt(v.k))
+		if got := hex.EncodeToString(q.MarshalCompressed()); got != v.g2 {
+			t.Fatalf("%v·g₂: got %v, want %v", v.k, got, v.g2)
+		}
+		m, _ = hex.DecodeString(v.g2)
+		q2 := new(G2)
+		if _, err := q2.UnmarshalCompressed(m); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(q.Marshal(), q2.Marshal()) {
+			t.Fatalf("%v·g₂: points are different", v.k)
+		}
+	}
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		_, p, err := RandomG1(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, q, err := RandomG2(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 1 {
+			p.Neg(p)
+			q.Neg(q)
+		}
+
+		rest, err := new(G1).UnmarshalCompressed(
+			append(p.MarshalCompressed(), 1, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(rest, []byte{1, 2}) {
+			t.Fatalf("unexpected rest: %x", rest)
+		}
+		p2 := new(G1)
+		if _, err = p2.UnmarshalCompressed(p.MarshalCompressed()); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p.Marshal(), p2.Marshal()) {
+			t.Fatal("G1 points are different")
+		}
+
+		q2 := new(G2)
+		if _, err = q2.UnmarshalCompressed(q.MarshalCompressed()); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(q.Marshal(), q2.Marshal()) {
+			t.Fatal("G2 points are different")
+		}
+	}
+}
+
+func TestUnmarshalCompressedErrors(t *testing.T) {
+	g1 := new(G1).ScalarBaseMult(big.NewInt(1)).MarshalCompressed()
+	g2 := new(G2).ScalarBaseMult(big.NewInt(1)).MarshalCompressed()
+
+	// (1, y) is on the twist but not in G₂
+	notInG2 := make([]byte, 64)
+	notInG2[0] = compressedSmallest
+	notInG2[63] = 1
+
+	// x = 4 is not the x coordinate of a point of the curve y² = x³+3
+	notOnCurve := make([]byte, 32)
+	notOnCurve[0] = compressedSmallest
+	notOnCurve[31] = 4
+
+	testCases := []struct {
+		name    string
+		g2      bool
+		m       []byte
+		wantErr string
+	}{
+		{"G1 short", false, g1[:31], "bn256: not enough data"},
+		{"G2 short", true, g2[:63], "bn256: not enough data"},
+		{"G1 uncompressed", false, append([]byte{g1[0] &^ compressedMask},
+			g1[1:]...), "bn256: point is not compressed"},
+		{"G2 uncompressed", true, append([]byte{g2[0] &^ compressedMask},
+			g2[1:]...), "bn256: point is not compressed"},
+		{"G1 infinity with x", false, append([]byte{compressedInfinity},
+			g1[1:]...), "bn256: malformed point"},
+		{"G1 not on curve", false, notOnCurve, "bn256: malformed point"},
+		{"G1 x exceeds modulus", false, append([]byte{0xff}, g1[1:]...),
+			"bn256: coordinate exceeds modulus"},
+		{"G2 not in subgroup", true, notInG2, "bn256: point is not in G2"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.g2 {
+				_, err = new(G2).UnmarshalCompressed(tc.m)
+			} else {
+				_, err = new(G1).UnmarshalCompressed(tc.m)
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
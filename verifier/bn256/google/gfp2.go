@@ -225,3 +225,53 @@ func (e *gfP2) Real() *big.Int {
 func (e *gfP2) Imag() *big.Int {
 	return e.y
 }
+
+// Sqrt sets e to a square root of a and reports whether a is a square. If
+// xi+y is the square of ci+d, then d² = (y ± √(x²+y²))/2, only one of them
+// being a square when x ≠ 0 as -1 is not a square, and c = x/2d.
+func (e *gfP2) Sqrt(a *gfP2) bool {
+	x := new(big.Int).Mod(a.x, P)
+	y := new(big.Int).Mod(a.y, P)
+	if x.Sign() == 0 {
+		if s := new(big.Int).ModSqrt(y, P); s != nil {
+			e.x.SetInt64(0)
+			e.y.Set(s)
+			return true
+		}
+		// (si)² = -s²
+		if s := new(big.Int).ModSqrt(y.Sub(P, y), P); s != nil {
+			e.x.Set(s)
+			e.y.SetInt64(0)
+			return true
+		}
+		return false
+	}
+
+	n := new(big.Int).Mul(x, x)
+	n.Add(n, new(big.Int).Mul(y, y))
+	n.Mod(n, P)
+	if n.ModSqrt(n, P) == nil {
+		return false
+	}
+
+	t := new(big.Int).Add(y, n)
+	t.Mul(t, gfpHalf).Mod(t, P)
+	d := new(big.Int).ModSqrt(t, P)
+	if d == nil {
+		t.Sub(y, n).Mul(t, gfpHalf).Mod(t, P)
+		if d = new(big.Int).ModSqrt(t, P); d == nil {
+			return false
+		}
+	}
+
+	c := new(big.Int).Lsh(d, 1)
+	c.ModInverse(c, P)
+	c.Mul(c, x).Mod(c, P)
+
+	e.x.Set(c)
+	e.y.Set(d)
+	return true
+}
+
+// gfpHalf is 1/2 in GF(p).
+var gfpHalf = new(big.Int).Rsh(new(big.Int).Add(P, big.NewInt(1)), 1)
@@ -0,0 +1,43 @@
+package bn256
+
+// G2Prepared is a point of G2 prepared to be used in many pairings, like
+// the ones of a verification key. This implementation only keeps the point
+// in affine form.
+type G2Prepared struct {
+	p *twistPoint
+}
+
+// NewG2Prepared prepares q for PairingCheckPrepared.
+func NewG2Prepared(q *G2) *G2Prepared {
+	if q.p == nil || q.p.IsInfinity() {
+		return &G2Prepared{}
+	}
+	p := newTwistPoint(nil)
+	p.Set(q.p)
+	p.MakeAffine(new(bnPool))
+	return &G2Prepared{p: p}
+}
+
+// PairingCheckPrepared reports whether the product of the pairings
+// e(a[i], b[i]) is equal to target. A nil target is the identity of GT.
+// The Miller loops of all the pairs share a single final exponentiation.
+func PairingCheckPrepared(a []*G1, b []*G2Prepared, target *GT) bool {
+	pool := new(bnPool)
+
+	acc := newGFp12(pool)
+	acc.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].p.IsInfinity() || b[i].p == nil {
+			continue
+		}
+		acc.Mul(acc, miller(b[i].p, a[i].p, pool), pool)
+	}
+	ret := finalExponentiation(acc, pool)
+	acc.Put(pool)
+
+	if target == nil {
+		return ret.IsOne()
+	}
+	return string((&GT{ret}).Marshal()) == string(target.Marshal())
+}
@@ -77,9 +77,13 @@ func (c *twistPoint) IsOnCurve() bool {
 	yy.Sub(yy, twistB)
 	yy.Minimal()
 
-	if yy.x.Sign() != 0 || yy.y.Sign() != 0 {
-		return false
-	}
+	return yy.x.Sign() == 0 && yy.y.Sign() == 0
+}
+
+// IsInSubgroup returns true iff c is in the subgroup of order Order of the
+// twist, that is G₂.
+func (c *twistPoint) IsInSubgroup() bool {
+	pool := new(bnPool)
 	cneg := newTwistPoint(pool)
 	cneg.Mul(c, Order, pool)
 	return cneg.z.IsZero()
@@ -256,8 +260,7 @@ func (c *twistPoint) MakeAffine(pool *bnPool) *twistPoint {
 
 func (c *twistPoint) Negative(a *twistPoint, pool *bnPool) {
 	c.x.Set(a.x)
-	c.y.SetZero()
-	c.y.Sub(c.y, a.y)
+	c.y.Negative(a.y)
 	c.z.Set(a.z)
-	c.t.SetZero()
+	c.t.Set(a.t)
 }
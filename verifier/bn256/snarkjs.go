@@ -0,0 +1,24 @@
+package bn256
+
+import "math/big"
+
+var (
+	// snarkjs computes the hard part of the final exponentiation as in
+	// Fuentes-Castañeda et al., so its pairing is the one of this package
+	// raised to λ = 2u(6u²+3u+1).
+	snarkjsExp, _ = new(big.Int).SetString("1469306990098747947464455738335385361638823152381947992820", 10)
+	snarkjsExpInv = new(big.Int).ModInverse(snarkjsExp, order)
+
+	order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+)
+
+// ToSnarkjsGT converts the result of Pair into the value snarkjs computes
+// for the same points, e.g. vk_alphabeta_12 of a verification key.
+func ToSnarkjsGT(e *GT) *GT {
+	return new(GT).ScalarMult(e, snarkjsExp)
+}
+
+// FromSnarkjsGT is the inverse of ToSnarkjsGT.
+func FromSnarkjsGT(e *GT) *GT {
+	return new(GT).ScalarMult(e, snarkjsExpInv)
+}
@@ -0,0 +1,20 @@
+package verifier
+
+import (
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyProofCallData(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+
+	data, err := readTestProof(t).VerifyProofCallData()
+	require.NoError(t, err)
+	zkProof, err := types.ParseVerifyProofCallData(data)
+	require.NoError(t, err)
+	require.NoError(t, VerifyGroth16(zkProof, vkJSON))
+}
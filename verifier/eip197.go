@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// ecPairingPairSize is the size of a pair of points in the input of the
+// ecPairing precompiled contract: a G1 point of 64 bytes followed by a G2
+// point of 128 bytes.
+const ecPairingPairSize = 64 + 128
+
+// PrecompileInputs are the inputs of the EVM precompiled contracts of
+// EIP-196 and EIP-197 called by the Solidity Groth16 verifier, as generated
+// by ExportSolidityVerifier or snarkjs, to verify a proof.
+type PrecompileInputs struct {
+	// ECMul are the inputs of ecMul (0x07) that compute the products of the
+	// IC points and the public inputs: the point IC[i+1] followed by the
+	// public input i, 96 bytes.
+	ECMul [][]byte
+	// ECAdd are the inputs of ecAdd (0x06) that accumulate the products in
+	// vk_x: the output of ECMul[i] followed by vk_x so far, starting at IC[0],
+	// 128 bytes.
+	ECAdd [][]byte
+	// ECPairing is the input of ecPairing (0x08): the pairs (-A, B),
+	// (alpha, beta), (vk_x, gamma) and (C, delta), 192 bytes each.
+	ECPairing []byte
+}
+
+// Groth16PrecompileInputs returns the inputs of the EVM precompiled
+// contracts called to verify zkProof with a verification key in the snarkjs
+// JSON format. Points are encoded as in EIP-197: the affine coordinates as
+// 32-byte big-endian numbers, the imaginary part first for G2, and (0, 0)
+// for the point at infinity.
+func Groth16PrecompileInputs(zkProof types.ZKProof,
+	verificationKey []byte) (*PrecompileInputs, error) {
+
+	vk, err := ParseVerifyingKey(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	return vk.PrecompileInputs(zkProof)
+}
+
+// PrecompileInputs returns the inputs of the EVM precompiled contracts
+// called to verify zkProof. See Groth16PrecompileInputs.
+func (vk *VerifyingKey) PrecompileInputs(
+	zkProof types.ZKProof) (*PrecompileInputs, error) {
+
+	if vk.bls != nil {
+		return nil, fmt.Errorf(
+			"EVM precompiles are not supported on curve %v", vk.curve)
+	}
+	if zkProof.Proof == nil {
+		return nil, errors.New("proof is empty")
+	}
+	if err := vk.checkProofData(*zkProof.Proof); err != nil {
+		return nil, err
+	}
+	p, err := parseProofData(*zkProof.Proof)
+	if err != nil {
+		return nil, err
+	}
+	pubSignals, err := stringsToArrayBigInt(zkProof.PubSignals)
+	if err != nil {
+		return nil, err
+	}
+	if err = vk.checkInputs(pubSignals); err != nil {
+		return nil, err
+	}
+
+	in := &PrecompileInputs{}
+	vkX := vk.ic[0]
+	for i, s := range pubSignals {
+		var scalar [32]byte
+		s.FillBytes(scalar[:])
+		mulInput := append(vk.ic[i+1].Marshal(), scalar[:]...)
+		in.ECMul = append(in.ECMul, mulInput)
+
+		mul := new(bn256.G1).ScalarMult(vk.ic[i+1], s)
+		in.ECAdd = append(in.ECAdd, append(mul.Marshal(), vkX.Marshal()...))
+		vkX = new(bn256.G1).Add(mul, vkX)
+	}
+
+	g1 := []*bn256.G1{new(bn256.G1).Neg(p.A), vk.alpha, vkX, p.C}
+	g2 := []*bn256.G2{p.B, vk.beta, vk.gamma, vk.delta}
+	in.ECPairing = make([]byte, 0, len(g1)*ecPairingPairSize)
+	for i := range g1 {
+		in.ECPairing = append(in.ECPairing, g1[i].Marshal()...)
+		in.ECPairing = append(in.ECPairing, g2[i].Marshal()...)
+	}
+	return in, nil
+}
+
+// VerifyECPairingInput checks the input of the ecPairing precompiled
+// contract as the EVM does: it returns nil if the product of the pairings of
+// the pairs of points is one, and an error if it is not or the input is
+// malformed.
+func VerifyECPairingInput(input []byte) error {
+	if len(input)%ecPairingPairSize != 0 {
+		return fmt.Errorf("invalid ecPairing input length: %v", len(input))
+	}
+
+	n := len(input) / ecPairingPairSize
+	g1 := make([]*bn256.G1, n)
+	g2 := make([]*bn256.G2, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*ecPairingPairSize : (i+1)*ecPairingPairSize]
+		g1[i] = new(bn256.G1)
+		if _, err := g1[i].Unmarshal(pair[:64]); err != nil {
+			return fmt.Errorf("invalid G1 point of pair %v: %w", i, err)
+		}
+		g2[i] = new(bn256.G2)
+		if _, err := g2[i].Unmarshal(pair[64:]); err != nil {
+			return fmt.Errorf("invalid G2 point of pair %v: %w", i, err)
+		}
+	}
+
+	if !bn256.PairingCheck(g1, g2) {
+		return errors.New("invalid proofs")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroth16PrecompileInputs(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	in, err := Groth16PrecompileInputs(zkProof, vkJSON)
+	require.NoError(t, err)
+	require.Len(t, in.ECMul, 2)
+	require.Len(t, in.ECAdd, 2)
+	require.Len(t, in.ECPairing, 4*192)
+	require.NoError(t, VerifyECPairingInput(in.ECPairing))
+
+	// B, C and the x coordinate of A are passed as they are in the calldata
+	// of verifyProof
+	callData, err := zkProof.VerifyProofCallData()
+	require.NoError(t, err)
+	require.Equal(t, callData[4:4+32], in.ECPairing[:32])
+	require.Equal(t, callData[4+64:4+192], in.ECPairing[64:192])
+	require.Equal(t, callData[4+192:4+256], in.ECPairing[3*192:3*192+64])
+
+	// the last ecAdd outputs vk_x
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(t, err)
+	require.Equal(t, vk.ic[0].Marshal(), in.ECAdd[0][64:])
+	var a, b bn256.G1
+	_, err = a.Unmarshal(in.ECAdd[1][:64])
+	require.NoError(t, err)
+	_, err = b.Unmarshal(in.ECAdd[1][64:])
+	require.NoError(t, err)
+	require.Equal(t, new(bn256.G1).Add(&a, &b).Marshal(),
+		in.ECPairing[2*192:2*192+64])
+	for i := range in.ECMul {
+		require.Equal(t, vk.ic[i+1].Marshal(), in.ECMul[i][:64])
+	}
+
+	zkProof.PubSignals[0] = "1"
+	in, err = Groth16PrecompileInputs(zkProof, vkJSON)
+	require.NoError(t, err)
+	require.EqualError(t, VerifyECPairingInput(in.ECPairing), "invalid proofs")
+}
+
+func TestVerifyECPairingInputErrors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	in, err := Groth16PrecompileInputs(readTestProof(t), vkJSON)
+	require.NoError(t, err)
+
+	require.EqualError(t, VerifyECPairingInput(in.ECPairing[:100]),
+		"invalid ecPairing input length: 100")
+
+	input := append([]byte{}, in.ECPairing...)
+	input[192+63] ^= 1
+	require.EqualError(t, VerifyECPairingInput(input),
+		"invalid G1 point of pair 1: bn256: malformed point")
+
+	input = append([]byte{}, in.ECPairing...)
+	input[2*192+64+127] ^= 1
+	require.EqualError(t, VerifyECPairingInput(input),
+		"invalid G2 point of pair 2: bn256: malformed point")
+
+	// the empty product of pairings is one
+	require.NoError(t, VerifyECPairingInput(nil))
+
+	blsVKJSON, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(t, err)
+	_, err = Groth16PrecompileInputs(readTestProof(t), blsVKJSON)
+	require.EqualError(t, err,
+		"EVM precompiles are not supported on curve bls12381")
+}
@@ -0,0 +1,463 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// fflonkVK is the fflonk Verification Key data structure in bn256 format.
+type fflonkVK struct {
+	nPublic int
+	power   int
+	k1, k2  *big.Int
+	// w is the generator of the domain, w3, w4 and w8 are roots of unity of
+	// order 3, 4 and 8 and wr is a cube root of w.
+	w, w3, w4, w8, wr *big.Int
+
+	C0 *bn256.G1
+	X2 *bn256.G2
+}
+
+// fflonkVKJSON is the fflonk Verification Key data structure in string
+// format (from json).
+type fflonkVKJSON struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	W        string     `json:"w"`
+	W3       string     `json:"w3"`
+	W4       string     `json:"w4"`
+	W8       string     `json:"w8"`
+	Wr       string     `json:"wr"`
+	X2       [][]string `json:"X_2"`
+	C0       []string   `json:"C0"`
+}
+
+// fflonkProof is the fflonk proof in bn256 format.
+type fflonkProof struct {
+	C1, C2, W1, W2 *bn256.G1
+
+	ql, qr, qm, qo, qc, s1, s2, s3 *big.Int
+	a, b, c, z, zw, t1w, t2w       *big.Int
+}
+
+// fflonkChallenges are the challenges of the transcript of a fflonk proof
+// and the opening points derived from them.
+type fflonkChallenges struct {
+	beta, gamma, alpha, y *big.Int
+	// xi is xiSeed^24
+	xiSeed, xi *big.Int
+
+	// h0w8 are the 8th roots of xi, h1w4 the 4th roots of xi, h2w3 the cube
+	// roots of xi and h3w3 the cube roots of xi·w.
+	h0w8 [8]*big.Int
+	h1w4 [4]*big.Int
+	h2w3 [3]*big.Int
+	h3w3 [3]*big.Int
+}
+
+// VerifyFflonk performs a verification of a snarkjs fflonk zkp based on
+// verification key and public inputs
+func VerifyFflonk(zkProof types.FflonkZKProof, verificationKey []byte) error {
+	if zkProof.Proof == nil {
+		return errors.New("proof is empty")
+	}
+
+	// 1. cast external verification key data to internal model.
+	var vkStr fflonkVKJSON
+	err := json.Unmarshal(verificationKey, &vkStr)
+	if err != nil {
+		return err
+	}
+	vk, err := parseFflonkVK(vkStr)
+	if err != nil {
+		return err
+	}
+
+	// 2. cast external proof data to internal model.
+	p, err := parseFflonkProof(*zkProof.Proof)
+	if err != nil {
+		return err
+	}
+
+	// 3. cast external public inputs data to internal model.
+	pubSignals, err := stringsToArrayBigInt(zkProof.PubSignals)
+	if err != nil {
+		return err
+	}
+
+	return verifyFflonk(vk, p, pubSignals)
+}
+
+// verifyFflonk performs the verification of the fflonk zkSNARK proofs as the
+// snarkjs verifier does. The proof commits to
+//
+//	C0(X) = ql(X⁸) + X·qr(X⁸) + X²·qo(X⁸) + X³·qm(X⁸) + X⁴·qc(X⁸) +
+//		X⁵·s1(X⁸) + X⁶·s2(X⁸) + X⁷·s3(X⁸)
+//	C1(X) = a(X⁴) + X·b(X⁴) + X²·c(X⁴) + X³·T0(X⁴)
+//	C2(X) = z(X³) + X·T1(X³) + X²·T2(X³)
+//
+// and the evaluations at xi and xi·w are checked with a single KZG opening
+// of the three of them at the roots of xi and xi·w. The inv evaluation of
+// the proof, a batched inverse for the Solidity verifier, is not used.
+func verifyFflonk(vk *fflonkVK, proof *fflonkProof, inputs []*big.Int) error {
+	if err := checkPublicInputs(inputs, vk.nPublic); err != nil {
+		return err
+	}
+
+	ch := fflonkCalculateChallenges(vk, proof, inputs)
+
+	xin := frPow2(ch.xi, vk.power)
+	zh := frSub(new(big.Int), xin, big.NewInt(1))
+	if zh.Sign() == 0 {
+		return fmt.Errorf("invalid proofs")
+	}
+	invZh := new(big.Int).ModInverse(zh, constants.Q)
+
+	L, err := lagrangeEvaluations(ch.xi, zh, vk.w, vk.power, vk.nPublic)
+	if err != nil {
+		return err
+	}
+	pi := publicInputsEvaluation(inputs, L)
+
+	// T0(xi) = (ql·a + qr·b + qm·a·b + qo·c + qc + PI(xi)) / ZH(xi)
+	t0 := frMul(new(big.Int), proof.ql, proof.a)
+	frAdd(t0, t0, frMul(new(big.Int), proof.qr, proof.b))
+	frAdd(t0, t0, frMul(new(big.Int), proof.qm,
+		frMul(new(big.Int), proof.a, proof.b)))
+	frAdd(t0, t0, frMul(new(big.Int), proof.qo, proof.c))
+	frAdd(t0, t0, proof.qc)
+	frAdd(t0, t0, pi)
+	frMul(t0, t0, invZh)
+
+	// T1(xi) = (z - 1)·L1(xi) / ZH(xi)
+	t1 := frSub(new(big.Int), proof.z, big.NewInt(1))
+	frMul(t1, t1, L[1])
+	frMul(t1, t1, invZh)
+
+	// T2(xi) = ((a + beta·xi + gamma)·(b + beta·xi·k1 + gamma)·
+	//	(c + beta·xi·k2 + gamma)·z - (a + beta·s1 + gamma)·
+	//	(b + beta·s2 + gamma)·(c + beta·s3 + gamma)·zw) / ZH(xi)
+	betaXi := frMul(new(big.Int), ch.beta, ch.xi)
+	t21 := plonkPermutationTerm(proof.a, betaXi, big.NewInt(1), ch.gamma)
+	frMul(t21, t21, plonkPermutationTerm(proof.b, betaXi, vk.k1, ch.gamma))
+	frMul(t21, t21, plonkPermutationTerm(proof.c, betaXi, vk.k2, ch.gamma))
+	frMul(t21, t21, proof.z)
+	t22 := plonkPermutationTerm(proof.a, ch.beta, proof.s1, ch.gamma)
+	frMul(t22, t22, plonkPermutationTerm(proof.b, ch.beta, proof.s2, ch.gamma))
+	frMul(t22, t22, plonkPermutationTerm(proof.c, ch.beta, proof.s3, ch.gamma))
+	frMul(t22, t22, proof.zw)
+	t2 := frSub(new(big.Int), t21, t22)
+	frMul(t2, t2, invZh)
+
+	// r0, r1 and r2 are the polynomials interpolating C0, C1 and C2 at their
+	// opening points, evaluated at y
+	c0 := []*big.Int{proof.ql, proof.qr, proof.qo, proof.qm, proof.qc,
+		proof.s1, proof.s2, proof.s3}
+	c1 := []*big.Int{proof.a, proof.b, proof.c, t0}
+	c2 := []*big.Int{proof.z, t1, t2}
+	c2w := []*big.Int{proof.zw, proof.t1w, proof.t2w}
+
+	var (
+		s2           = append(ch.h2w3[:], ch.h3w3[:]...)
+		v0, v1, v2   []*big.Int
+		mulH0, mulH1 = big.NewInt(1), big.NewInt(1)
+		mulH2        = big.NewInt(1)
+		r0, r1, r2   *big.Int
+	)
+	for _, h := range ch.h0w8 {
+		v0 = append(v0, evalCombined(c0, h))
+		frMul(mulH0, mulH0, frSub(new(big.Int), ch.y, h))
+	}
+	for _, h := range ch.h1w4 {
+		v1 = append(v1, evalCombined(c1, h))
+		frMul(mulH1, mulH1, frSub(new(big.Int), ch.y, h))
+	}
+	for i, h := range s2 {
+		if i < len(ch.h2w3) {
+			v2 = append(v2, evalCombined(c2, h))
+		} else {
+			v2 = append(v2, evalCombined(c2w, h))
+		}
+		frMul(mulH2, mulH2, frSub(new(big.Int), ch.y, h))
+	}
+	if mulH1.Sign() == 0 || mulH2.Sign() == 0 {
+		return fmt.Errorf("invalid proofs")
+	}
+	if r0, err = interpolateAt(ch.h0w8[:], v0, ch.y); err != nil {
+		return err
+	}
+	if r1, err = interpolateAt(ch.h1w4[:], v1, ch.y); err != nil {
+		return err
+	}
+	if r2, err = interpolateAt(s2, v2, ch.y); err != nil {
+		return err
+	}
+
+	// quotient1 = alpha·ZS0(y)/ZS1(y), quotient2 = alpha²·ZS0(y)/ZS2(y)
+	quotient1 := new(big.Int).ModInverse(mulH1, constants.Q)
+	frMul(quotient1, quotient1, mulH0)
+	frMul(quotient1, quotient1, ch.alpha)
+	quotient2 := new(big.Int).ModInverse(mulH2, constants.Q)
+	frMul(quotient2, quotient2, mulH0)
+	frMul(quotient2, quotient2, frSquare(new(big.Int), ch.alpha))
+
+	// [F] = [C0] + quotient1·[C1] + quotient2·[C2]
+	// [E] = (r0 + quotient1·r1 + quotient2·r2)·[1]
+	// [J] = ZS0(y)·[W1]
+	// A1 = [F] - [E] - [J] + y·[W2]
+	e := frMul(new(big.Int), quotient1, r1)
+	frAdd(e, e, r0)
+	frAdd(e, e, frMul(new(big.Int), quotient2, r2))
+	a1 := new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{vk.C0, proof.C1, proof.C2, g1Generator, proof.W1,
+			proof.W2},
+		[]*big.Int{big.NewInt(1), quotient1, quotient2, frNeg(e),
+			frNeg(mulH0), ch.y})
+
+	// e(-A1, [1]_2)·e([W2], [x]_2) = 1
+	res := bn256.PairingCheck([]*bn256.G1{new(bn256.G1).Neg(a1), proof.W2},
+		[]*bn256.G2{g2Generator, vk.X2})
+	if !res {
+		return fmt.Errorf("invalid proofs")
+	}
+	return nil
+}
+
+// fflonkCalculateChallenges runs the Keccak transcript of a fflonk proof.
+func fflonkCalculateChallenges(vk *fflonkVK, proof *fflonkProof,
+	inputs []*big.Int) *fflonkChallenges {
+
+	var (
+		ch fflonkChallenges
+		t  keccakTranscript
+	)
+
+	// round 1: beta and gamma
+	t.addG1(vk.C0)
+	for _, in := range inputs {
+		t.addScalar(in)
+	}
+	t.addG1(proof.C1)
+	ch.beta = t.challenge()
+
+	t.addScalar(ch.beta)
+	ch.gamma = t.challenge()
+
+	// round 2: xi seed
+	t.addScalar(ch.gamma)
+	t.addG1(proof.C2)
+	ch.xiSeed = t.challenge()
+
+	// h0 = xiSeed³, h1 = xiSeed⁶, h2 = xiSeed⁸, h3 = h2·wr and xi = xiSeed²⁴
+	xiSeed2 := frSquare(new(big.Int), ch.xiSeed)
+	h0 := frMul(new(big.Int), xiSeed2, ch.xiSeed)
+	h1 := frSquare(new(big.Int), h0)
+	h2 := frMul(new(big.Int), h1, xiSeed2)
+	h3 := frMul(new(big.Int), h2, vk.wr)
+	rootsOfUnityCoset(ch.h0w8[:], h0, vk.w8)
+	rootsOfUnityCoset(ch.h1w4[:], h1, vk.w4)
+	rootsOfUnityCoset(ch.h2w3[:], h2, vk.w3)
+	rootsOfUnityCoset(ch.h3w3[:], h3, vk.w3)
+	ch.xi = frSquare(new(big.Int), h2)
+	frMul(ch.xi, ch.xi, h2)
+
+	// round 3: alpha
+	t.addScalar(ch.xiSeed)
+	for _, eval := range []*big.Int{proof.ql, proof.qr, proof.qm, proof.qo,
+		proof.qc, proof.s1, proof.s2, proof.s3, proof.a, proof.b, proof.c,
+		proof.z, proof.zw, proof.t1w, proof.t2w} {
+		t.addScalar(eval)
+	}
+	ch.alpha = t.challenge()
+
+	// round 4: y
+	t.addScalar(ch.alpha)
+	t.addG1(proof.W1)
+	ch.y = t.challenge()
+
+	return &ch
+}
+
+// rootsOfUnityCoset sets roots[i] to h·w^i.
+func rootsOfUnityCoset(roots []*big.Int, h, w *big.Int) {
+	roots[0] = h
+	for i := 1; i < len(roots); i++ {
+		roots[i] = frMul(new(big.Int), roots[i-1], w)
+	}
+}
+
+// evalCombined returns ∑ c[i]·x^i, the evaluation of a combined polynomial
+// of fflonk at a root x of the opening point of its parts.
+func evalCombined(c []*big.Int, x *big.Int) *big.Int {
+	r := new(big.Int)
+	for i := len(c) - 1; i >= 0; i-- {
+		frMul(r, r, x)
+		frAdd(r, r, c[i])
+	}
+	return r
+}
+
+// interpolateAt returns the evaluation at y of the polynomial of degree
+// len(points)-1 that takes values[i] at points[i].
+func interpolateAt(points, values []*big.Int, y *big.Int) (*big.Int, error) {
+	res := new(big.Int)
+	num, den := new(big.Int), new(big.Int)
+	for i := range points {
+		num.SetInt64(1)
+		den.SetInt64(1)
+		for j := range points {
+			if i == j {
+				continue
+			}
+			frMul(num, num, frSub(new(big.Int), y, points[j]))
+			frMul(den, den, frSub(new(big.Int), points[i], points[j]))
+		}
+		if den.Sign() == 0 {
+			return nil, fmt.Errorf("invalid proofs")
+		}
+		den.ModInverse(den, constants.Q)
+		frMul(num, num, den)
+		frAdd(res, res, frMul(num, num, values[i]))
+	}
+	return res, nil
+}
+
+func parseFflonkVK(vkStr fflonkVKJSON) (*fflonkVK, error) {
+	if vkStr.Protocol != "" && vkStr.Protocol != "fflonk" {
+		return nil, fmt.Errorf("verification key protocol is not fflonk: %v",
+			vkStr.Protocol)
+	}
+	if vkStr.Curve != "" && vkStr.Curve != "bn128" {
+		return nil, fmt.Errorf("unsupported curve: %v", vkStr.Curve)
+	}
+	if vkStr.NPublic < 0 {
+		return nil, fmt.Errorf("invalid nPublic: %v", vkStr.NPublic)
+	}
+	if vkStr.Power < 1 || vkStr.Power > maxPower {
+		return nil, fmt.Errorf("invalid power: %v", vkStr.Power)
+	}
+
+	v := fflonkVK{nPublic: vkStr.NPublic, power: vkStr.Power}
+	var err error
+	for _, s := range []struct {
+		name string
+		str  string
+		dst  **big.Int
+	}{
+		{"k1", vkStr.K1, &v.k1},
+		{"k2", vkStr.K2, &v.k2},
+		{"w", vkStr.W, &v.w},
+		{"w3", vkStr.W3, &v.w3},
+		{"w4", vkStr.W4, &v.w4},
+		{"w8", vkStr.W8, &v.w8},
+		{"wr", vkStr.Wr, &v.wr},
+	} {
+		*s.dst, err = stringToScalar(s.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", s.name, err)
+		}
+	}
+
+	if !isRootOfUnity(v.w, v.power) {
+		return nil, fmt.Errorf("invalid w: not a root of unity of order 2^%v",
+			v.power)
+	}
+	if !isRootOfUnity(v.w4, 2) {
+		return nil, errors.New("invalid w4: not a root of unity of order 4")
+	}
+	if !isRootOfUnity(v.w8, 3) {
+		return nil, errors.New("invalid w8: not a root of unity of order 8")
+	}
+	w3Cube := new(big.Int).Exp(v.w3, big.NewInt(3), constants.Q)
+	if v.w3.Cmp(big.NewInt(1)) == 0 || w3Cube.Cmp(big.NewInt(1)) != 0 {
+		return nil, errors.New("invalid w3: not a root of unity of order 3")
+	}
+	wrCube := new(big.Int).Exp(v.wr, big.NewInt(3), constants.Q)
+	if wrCube.Cmp(v.w) != 0 {
+		return nil, errors.New("invalid wr: not a cube root of w")
+	}
+
+	v.C0, err = stringToG1(vkStr.C0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid C0: %w", err)
+	}
+
+	v.X2, err = stringToG2(vkStr.X2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X_2: %w", err)
+	}
+
+	return &v, nil
+}
+
+func parseFflonkProof(pr types.FflonkProofData) (*fflonkProof, error) {
+	if pr.Protocol != "" && pr.Protocol != "fflonk" {
+		return nil, fmt.Errorf("proof protocol is not fflonk: %v",
+			pr.Protocol)
+	}
+	if pr.Curve != "" && pr.Curve != "bn128" {
+		return nil, fmt.Errorf(
+			"proof curve %v does not match verification key curve bn128",
+			pr.Curve)
+	}
+
+	var (
+		p   fflonkProof
+		err error
+	)
+	for _, c := range []struct {
+		name string
+		str  []string
+		dst  **bn256.G1
+	}{
+		{"C1", pr.Polynomials.C1, &p.C1},
+		{"C2", pr.Polynomials.C2, &p.C2},
+		{"W1", pr.Polynomials.W1, &p.W1},
+		{"W2", pr.Polynomials.W2, &p.W2},
+	} {
+		*c.dst, err = stringToG1(c.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", c.name, err)
+		}
+	}
+
+	ev := pr.Evaluations
+	for _, e := range []struct {
+		name string
+		str  string
+		dst  **big.Int
+	}{
+		{"ql", ev.Ql, &p.ql},
+		{"qr", ev.Qr, &p.qr},
+		{"qm", ev.Qm, &p.qm},
+		{"qo", ev.Qo, &p.qo},
+		{"qc", ev.Qc, &p.qc},
+		{"s1", ev.S1, &p.s1},
+		{"s2", ev.S2, &p.s2},
+		{"s3", ev.S3, &p.s3},
+		{"a", ev.A, &p.a},
+		{"b", ev.B, &p.b},
+		{"c", ev.C, &p.c},
+		{"z", ev.Z, &p.z},
+		{"zw", ev.Zw, &p.zw},
+		{"t1w", ev.T1w, &p.t1w},
+		{"t2w", ev.T2w, &p.t2w},
+	} {
+		*e.dst, err = stringToScalar(e.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid evaluation %v: %w", e.name, err)
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,167 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+// readTestFflonkProof reads the fflonk proof of the test vectors, see
+// testdata/README.md for their origin.
+func readTestFflonkProof(t testing.TB) types.FflonkZKProof {
+	proofJSON, err := os.ReadFile("testdata/fflonk_proof.json")
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile("testdata/fflonk_public.json")
+	require.NoError(t, err)
+
+	var zkProof types.FflonkZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+func TestVerifyFflonk(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/fflonk_verification_key.json")
+	require.NoError(t, err)
+
+	zkProof := readTestFflonkProof(t)
+	require.NoError(t, VerifyFflonk(zkProof, vkJSON))
+
+	zkProof.PubSignals = []string{"385", "83"}
+	require.EqualError(t, VerifyFflonk(zkProof, vkJSON), "invalid proofs")
+
+	zkProof = readTestFflonkProof(t)
+	zkProof.Proof.Evaluations.T2w = "1"
+	require.EqualError(t, VerifyFflonk(zkProof, vkJSON), "invalid proofs")
+
+	zkProof = readTestFflonkProof(t)
+	zkProof.Proof.Polynomials.C1 = zkProof.Proof.Polynomials.C2
+	require.EqualError(t, VerifyFflonk(zkProof, vkJSON), "invalid proofs")
+
+	// inv is not used by the verifier
+	zkProof = readTestFflonkProof(t)
+	zkProof.Proof.Evaluations.Inv = "1"
+	require.NoError(t, VerifyFflonk(zkProof, vkJSON))
+}
+
+func TestVerifyFflonkErrors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/fflonk_verification_key.json")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		vk      []byte
+		modify  func(p *types.FflonkZKProof)
+		wantErr string
+	}{
+		{
+			name:    "empty proof",
+			vk:      vkJSON,
+			modify:  func(p *types.FflonkZKProof) { p.Proof = nil },
+			wantErr: "proof is empty",
+		},
+		{
+			name:    "wrong nPublic",
+			vk:      modifyVK(t, vkJSON, "nPublic", 3),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "len(inputs) != vk.nPublic",
+		},
+		{
+			name: "input not in the field",
+			vk:   vkJSON,
+			modify: func(p *types.FflonkZKProof) {
+				p.PubSignals[1] = constants.Q.String()
+			},
+			wantErr: "input value is not in the fields",
+		},
+		{
+			name: "evaluation not in the field",
+			vk:   vkJSON,
+			modify: func(p *types.FflonkZKProof) {
+				p.Proof.Evaluations.Qm = constants.Q.String()
+			},
+			wantErr: "invalid evaluation qm: scalar is not in the field: " +
+				constants.Q.String(),
+		},
+		{
+			name: "malformed commitment",
+			vk:   vkJSON,
+			modify: func(p *types.FflonkZKProof) {
+				p.Proof.Polynomials.W1 = nil
+			},
+			wantErr: "invalid W1: G1 point must have 3 coordinates, got 0",
+		},
+		{
+			name: "plonk proof",
+			vk:   vkJSON,
+			modify: func(p *types.FflonkZKProof) {
+				p.Proof.Protocol = "plonk"
+			},
+			wantErr: "proof protocol is not fflonk: plonk",
+		},
+		{
+			name:    "plonk key",
+			vk:      modifyVK(t, vkJSON, "protocol", "plonk"),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "verification key protocol is not fflonk: plonk",
+		},
+		{
+			name: "bls12381 proof",
+			vk:   vkJSON,
+			modify: func(p *types.FflonkZKProof) {
+				p.Proof.Curve = "bls12381"
+			},
+			wantErr: "proof curve bls12381 does not match verification key " +
+				"curve bn128",
+		},
+		{
+			name:    "bls12381 key",
+			vk:      modifyVK(t, vkJSON, "curve", "bls12381"),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "unsupported curve: bls12381",
+		},
+		{
+			name:    "invalid w3",
+			vk:      modifyVK(t, vkJSON, "w3", "1"),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "invalid w3: not a root of unity of order 3",
+		},
+		{
+			name:    "invalid wr",
+			vk:      modifyVK(t, vkJSON, "wr", "2"),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "invalid wr: not a cube root of w",
+		},
+		{
+			name:    "missing C0",
+			vk:      modifyVK(t, vkJSON, "C0", nil),
+			modify:  func(p *types.FflonkZKProof) {},
+			wantErr: "invalid C0: G1 point must have 3 coordinates, got 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zkProof := readTestFflonkProof(t)
+			tc.modify(&zkProof)
+			require.EqualError(t, VerifyFflonk(zkProof, tc.vk), tc.wantErr)
+		})
+	}
+}
+
+func BenchmarkVerifyFflonk(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/fflonk_verification_key.json")
+	require.NoError(b, err)
+	zkProof := readTestFflonkProof(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyFflonk(zkProof, vkJSON); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+var (
+	// g1Generator and g2Generator are the generators of G1 and G2 used by
+	// snarkjs.
+	g1Generator = new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	g2Generator = new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+)
+
+// stringToScalar parses an element of the scalar field, in decimal or in
+// hexadecimal with the 0x prefix.
+func stringToScalar(s string) (*big.Int, error) {
+	n, err := stringToBigInt(s)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(constants.Q) >= 0 {
+		return nil, fmt.Errorf("scalar is not in the field: %s", s)
+	}
+	return n, nil
+}
+
+// frAdd sets z to x + y mod Q and returns z.
+func frAdd(z, x, y *big.Int) *big.Int {
+	z.Add(x, y)
+	return z.Mod(z, constants.Q)
+}
+
+// frSub sets z to x - y mod Q and returns z.
+func frSub(z, x, y *big.Int) *big.Int {
+	z.Sub(x, y)
+	return z.Mod(z, constants.Q)
+}
+
+// frMul sets z to x·y mod Q and returns z.
+func frMul(z, x, y *big.Int) *big.Int {
+	z.Mul(x, y)
+	return z.Mod(z, constants.Q)
+}
+
+// frSquare sets z to x² mod Q and returns z.
+func frSquare(z, x *big.Int) *big.Int {
+	return frMul(z, x, x)
+}
+
+// frNeg returns -x mod Q.
+func frNeg(x *big.Int) *big.Int {
+	return frSub(new(big.Int), big.NewInt(0), x)
+}
+
+// frPow2 returns x^(2^k) mod Q.
+func frPow2(x *big.Int, k int) *big.Int {
+	z := new(big.Int).Set(x)
+	for i := 0; i < k; i++ {
+		frSquare(z, z)
+	}
+	return z
+}
@@ -0,0 +1,317 @@
+package verifier
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// The points of the gnark-crypto binary encoding are marshaled as in
+// EIP-197, the point at infinity being all zeros, or compressed to their x
+// coordinate with flags in the two most significant bits of the first byte.
+// The flags of an uncompressed point are zero.
+const (
+	gnarkMask         = 0b11 << 6
+	gnarkUncompressed = 0b00 << 6
+)
+
+// ZKProofToGnark converts a Groth16 proof on the bn128 curve to the binary
+// encodings of gnark: the proof as written by groth16.Proof.WriteTo of the
+// BN254 backend, or by WriteRawTo without point compression if raw is set,
+// and the public signals as the public witness written by
+// witness.Witness.MarshalBinary. A, B and C of the proof are Ar, Bs and Krs
+// in gnark, the proof has no commitments.
+func ZKProofToGnark(zkProof types.ZKProof,
+	raw bool) (proof, publicWitness []byte, err error) {
+
+	if zkProof.Proof == nil {
+		return nil, nil, errors.New("proof is empty")
+	}
+	pr := *zkProof.Proof
+	if pr.Protocol != "" && pr.Protocol != "groth16" {
+		return nil, nil, fmt.Errorf("proof protocol is not groth16: %v",
+			pr.Protocol)
+	}
+	if pr.Curve != "" && pr.Curve != "bn128" {
+		return nil, nil, fmt.Errorf(
+			"gnark proofs are not supported on curve %v", pr.Curve)
+	}
+	p, err := parseProofData(pr)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubSignals, err := stringsToArrayBigInt(zkProof.PubSignals)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := gnarkEncoder{raw: raw}
+	e.g1(p.A)
+	e.g2(p.B)
+	e.g1(p.C)
+	// no commitments and the point at infinity as their proof of knowledge
+	e.uint32(0)
+	e.g1(new(bn256.G1).ScalarBaseMult(new(big.Int)))
+	proof = e.buf
+
+	// the number of public and secret variables followed by the vector of
+	// the public ones, 32-byte big-endian numbers
+	w := gnarkEncoder{}
+	w.uint32(len(pubSignals))
+	w.uint32(0)
+	w.uint32(len(pubSignals))
+	for i, s := range pubSignals {
+		if s.Sign() < 0 || s.Cmp(constants.Q) != -1 {
+			return nil, nil, fmt.Errorf(
+				"public signal %v is not in the field", i)
+		}
+		w.buf = append(w.buf, s.FillBytes(make([]byte, 32))...)
+	}
+	return proof, w.buf, nil
+}
+
+// ZKProofFromGnark is the inverse of ZKProofToGnark: it converts a gnark
+// BN254 Groth16 proof, compressed or not, and its public witness to a proof
+// in the snarkjs format. Proofs with commitments are not supported. The
+// secret part of a full witness is ignored.
+func ZKProofFromGnark(proof, publicWitness []byte) (types.ZKProof, error) {
+	d := gnarkDecoder{buf: proof}
+	a := d.g1()
+	b := d.g2()
+	c := d.g1()
+	if n := d.uint32(); d.err == nil && n != 0 {
+		return types.ZKProof{}, errors.New("gnark proof has commitments")
+	}
+	d.g1()
+	if err := d.end("proof"); err != nil {
+		return types.ZKProof{}, err
+	}
+
+	w := gnarkDecoder{buf: publicWitness}
+	nbPublic := w.uint32()
+	nbSecret := w.uint32()
+	n := w.uint32()
+	if w.err == nil && uint64(n) != uint64(nbPublic)+uint64(nbSecret) {
+		return types.ZKProof{}, fmt.Errorf(
+			"invalid gnark witness: %v elements for %v public and %v secret "+
+				"variables", n, nbPublic, nbSecret)
+	}
+	pubSignals := []string{}
+	for i := 0; w.err == nil && i < int(n); i++ {
+		s := new(big.Int).SetBytes(w.next(32))
+		if w.err == nil && s.Cmp(constants.Q) != -1 {
+			return types.ZKProof{}, fmt.Errorf(
+				"invalid gnark witness: element %v is not in the field", i)
+		}
+		if i < int(nbPublic) {
+			pubSignals = append(pubSignals, s.String())
+		}
+	}
+	if err := w.end("witness"); err != nil {
+		return types.ZKProof{}, err
+	}
+
+	return types.ZKProof{
+		Proof: &types.ProofData{
+			A:        g1Strings(a.Marshal(), 32),
+			B:        g2Strings(b.Marshal(), 32),
+			C:        g1Strings(c.Marshal(), 32),
+			Protocol: "groth16",
+			Curve:    "bn128",
+		},
+		PubSignals: pubSignals,
+	}, nil
+}
+
+// VerificationKeyToGnark converts a Groth16 verification key on the bn128
+// curve in the snarkjs JSON format to the binary encoding of
+// groth16.VerifyingKey.WriteTo of the gnark BN254 backend, or of WriteRawTo
+// without point compression if raw is set. The IC points are the K points
+// of gnark. [β]₁ and [δ]₁, which gnark keeps for compatibility but does not
+// use to verify proofs, are not part of snarkjs keys and are set to the
+// point at infinity.
+func VerificationKeyToGnark(verificationKey []byte, raw bool) ([]byte, error) {
+	vk, err := ParseVerifyingKey(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	if vk.bls != nil {
+		return nil, fmt.Errorf(
+			"gnark verification keys are not supported on curve %v", vk.curve)
+	}
+
+	infinity := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	e := gnarkEncoder{raw: raw}
+	e.g1(vk.alpha)
+	e.g1(infinity)
+	e.g2(vk.beta)
+	e.g2(vk.gamma)
+	e.g1(infinity)
+	e.g2(vk.delta)
+	e.uint32(len(vk.ic))
+	for _, p := range vk.ic {
+		e.g1(p)
+	}
+	// no public variables committed to and no commitment keys
+	e.uint32(0)
+	e.uint32(0)
+	return e.buf, nil
+}
+
+// VerificationKeyFromGnark is the inverse of VerificationKeyToGnark: it
+// converts a gnark BN254 Groth16 verification key, compressed or not, to the
+// snarkjs JSON format, formatted the same way as snarkjs does. Keys of
+// circuits with commitments are not supported.
+func VerificationKeyFromGnark(data []byte) ([]byte, error) {
+	d := gnarkDecoder{buf: data}
+	alpha := d.g1()
+	d.g1()
+	beta := d.g2()
+	gamma := d.g2()
+	d.g1()
+	delta := d.g2()
+	n := d.uint32()
+	var ic []*bn256.G1
+	for i := 0; d.err == nil && i < int(n); i++ {
+		ic = append(ic, d.g1())
+	}
+	committed := d.uint32()
+	nbCommitments := d.uint32()
+	if d.err == nil && (committed != 0 || nbCommitments != 0) {
+		return nil, errors.New("gnark verification key has commitments")
+	}
+	if err := d.end("verification key"); err != nil {
+		return nil, err
+	}
+	if len(ic) == 0 {
+		return nil, errors.New("verification key has no IC points")
+	}
+
+	nPublic := len(ic) - 1
+	vkStr := vkJSON{
+		Protocol:  "groth16",
+		Curve:     "bn128",
+		NPublic:   &nPublic,
+		Alpha:     g1Strings(alpha.Marshal(), 32),
+		Beta:      g2Strings(beta.Marshal(), 32),
+		Gamma:     g2Strings(gamma.Marshal(), 32),
+		Delta:     g2Strings(delta.Marshal(), 32),
+		AlphaBeta: gtStrings(bn256.Pair(alpha, beta)),
+		IC:        make([][]string, len(ic)),
+	}
+	for i, p := range ic {
+		vkStr.IC[i] = g1Strings(p.Marshal(), 32)
+	}
+	return json.MarshalIndent(vkStr, "", " ")
+}
+
+// gnarkEncoder appends values to buf in the binary encoding of gnark-crypto.
+type gnarkEncoder struct {
+	buf []byte
+	raw bool
+}
+
+func (e *gnarkEncoder) g1(p *bn256.G1) {
+	if e.raw {
+		e.buf = append(e.buf, p.Marshal()...)
+	} else {
+		e.buf = append(e.buf, p.MarshalCompressed()...)
+	}
+}
+
+func (e *gnarkEncoder) g2(p *bn256.G2) {
+	if e.raw {
+		e.buf = append(e.buf, p.Marshal()...)
+	} else {
+		e.buf = append(e.buf, p.MarshalCompressed()...)
+	}
+}
+
+func (e *gnarkEncoder) uint32(n int) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n))
+	e.buf = append(e.buf, b[:]...)
+}
+
+// gnarkDecoder reads values in the binary encoding of gnark-crypto from buf.
+// The points can be compressed or not, as the flags of each one tell. After
+// the first error the reads return zero values and err keeps the error.
+type gnarkDecoder struct {
+	buf []byte
+	err error
+}
+
+// next returns the next n bytes of buf.
+func (d *gnarkDecoder) next(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if len(d.buf) < n {
+		d.err = errors.New("not enough data")
+		return make([]byte, n)
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+// point returns the next point, size bytes if it is uncompressed or size/2
+// bytes if it is compressed, and whether it is compressed.
+func (d *gnarkDecoder) point(size int) ([]byte, bool) {
+	compressed := len(d.buf) > 0 && d.buf[0]&gnarkMask != gnarkUncompressed
+	if compressed {
+		size /= 2
+	}
+	return d.next(size), compressed
+}
+
+func (d *gnarkDecoder) g1() *bn256.G1 {
+	p := new(bn256.G1)
+	m, compressed := d.point(64)
+	if d.err != nil {
+		return p
+	}
+	if compressed {
+		_, d.err = p.UnmarshalCompressed(m)
+	} else {
+		_, d.err = p.Unmarshal(m)
+	}
+	return p
+}
+
+func (d *gnarkDecoder) g2() *bn256.G2 {
+	p := new(bn256.G2)
+	m, compressed := d.point(128)
+	if d.err != nil {
+		return p
+	}
+	if compressed {
+		_, d.err = p.UnmarshalCompressed(m)
+	} else {
+		_, d.err = p.Unmarshal(m)
+	}
+	return p
+}
+
+func (d *gnarkDecoder) uint32() uint32 {
+	return binary.BigEndian.Uint32(d.next(4))
+}
+
+// end returns the error of the decoding of the gnark value called name, if
+// any, or if there are bytes left.
+func (d *gnarkDecoder) end(name string) error {
+	if d.err != nil {
+		return fmt.Errorf("invalid gnark %v: %w", name, d.err)
+	}
+	if len(d.buf) != 0 {
+		return fmt.Errorf("invalid gnark %v: %v trailing bytes", name,
+			len(d.buf))
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package verifier
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The gnark_*.bin files were written by gnark v0.11.0 from the test proof
+// and verification key and verified with groth16.Verify of gnark.
+
+func TestZKProofToGnark(t *testing.T) {
+	zkProof := readTestProof(t)
+	wantWitness, err := os.ReadFile("testdata/gnark_public.bin")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		raw    bool
+		golden string
+	}{
+		{false, "testdata/gnark_proof.bin"},
+		{true, "testdata/gnark_proof_raw.bin"},
+	} {
+		want, err := os.ReadFile(tc.golden)
+		require.NoError(t, err)
+
+		proof, witness, err := ZKProofToGnark(zkProof, tc.raw)
+		require.NoError(t, err)
+		require.Equal(t, want, proof, tc.golden)
+		require.Equal(t, wantWitness, witness)
+	}
+}
+
+func TestZKProofFromGnark(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	witness, err := os.ReadFile("testdata/gnark_public.bin")
+	require.NoError(t, err)
+
+	for _, golden := range []string{"testdata/gnark_proof.bin",
+		"testdata/gnark_proof_raw.bin"} {
+
+		proof, err := os.ReadFile(golden)
+		require.NoError(t, err)
+
+		zkProof, err := ZKProofFromGnark(proof, witness)
+		require.NoError(t, err)
+		want := readTestProof(t)
+		want.Proof.Curve = "bn128"
+		require.Equal(t, want, zkProof, golden)
+		require.NoError(t, VerifyGroth16(zkProof, vkJSON))
+	}
+
+	proof, err := os.ReadFile("testdata/gnark_proof.bin")
+	require.NoError(t, err)
+	_, err = ZKProofFromGnark(proof[:len(proof)-1], witness)
+	require.EqualError(t, err, "invalid gnark proof: not enough data")
+	_, err = ZKProofFromGnark(append(proof, 0), witness)
+	require.EqualError(t, err, "invalid gnark proof: 1 trailing bytes")
+
+	// a commitment
+	withCommitment := append([]byte{}, proof[:128]...)
+	withCommitment = append(withCommitment, 0, 0, 0, 1)
+	withCommitment = append(withCommitment, proof[:32]...)
+	withCommitment = append(withCommitment, proof[132:]...)
+	_, err = ZKProofFromGnark(withCommitment, witness)
+	require.EqualError(t, err, "gnark proof has commitments")
+
+	// the element 396 replaced by the order of the field
+	badWitness := append([]byte{}, witness...)
+	copy(badWitness[12:44], []byte{
+		0x30, 0x64, 0x4e, 0x72, 0xe1, 0x31, 0xa0, 0x29,
+		0xb8, 0x50, 0x45, 0xb6, 0x81, 0x81, 0x58, 0x5d,
+		0x28, 0x33, 0xe8, 0x48, 0x79, 0xb9, 0x70, 0x91,
+		0x43, 0xe1, 0xf5, 0x93, 0xf0, 0x00, 0x00, 0x01})
+	_, err = ZKProofFromGnark(proof, badWitness)
+	require.EqualError(t, err,
+		"invalid gnark witness: element 0 is not in the field")
+	_, err = ZKProofFromGnark(proof, witness[:8])
+	require.EqualError(t, err, "invalid gnark witness: not enough data")
+}
+
+func TestVerificationKeyToGnark(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		raw    bool
+		golden string
+	}{
+		{false, "testdata/gnark_verification_key.bin"},
+		{true, "testdata/gnark_verification_key_raw.bin"},
+	} {
+		want, err := os.ReadFile(tc.golden)
+		require.NoError(t, err)
+
+		vk, err := VerificationKeyToGnark(vkJSON, tc.raw)
+		require.NoError(t, err)
+		require.Equal(t, want, vk, tc.golden)
+	}
+
+	blsVK, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(t, err)
+	_, err = VerificationKeyToGnark(blsVK, false)
+	require.EqualError(t, err,
+		"gnark verification keys are not supported on curve bls12381")
+}
+
+func TestVerificationKeyFromGnark(t *testing.T) {
+	want, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+
+	for _, golden := range []string{"testdata/gnark_verification_key.bin",
+		"testdata/gnark_verification_key_raw.bin"} {
+
+		vk, err := os.ReadFile(golden)
+		require.NoError(t, err)
+
+		vkJSON, err := VerificationKeyFromGnark(vk)
+		require.NoError(t, err)
+		require.Equal(t, string(want), string(vkJSON), golden)
+		require.NoError(t, VerifyGroth16(readTestProof(t), vkJSON))
+	}
+
+	vk, err := os.ReadFile("testdata/gnark_verification_key.bin")
+	require.NoError(t, err)
+	_, err = VerificationKeyFromGnark(vk[:100])
+	require.EqualError(t, err,
+		"invalid gnark verification key: not enough data")
+
+	// a commitment key
+	withCommitment := append([]byte{}, vk...)
+	withCommitment[len(withCommitment)-1] = 1
+	_, err = VerificationKeyFromGnark(withCommitment)
+	require.EqualError(t, err, "gnark verification key has commitments")
+}
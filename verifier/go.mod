@@ -4,8 +4,9 @@ go 1.18
 
 require (
 	github.com/iden3/go-iden3-crypto v0.0.15
-	github.com/iden3/go-rapidsnark/types v0.0.3
+	github.com/iden3/go-rapidsnark/types v0.0.4
 	github.com/stretchr/testify v1.8.2
+	golang.org/x/crypto v0.7.0
 	golang.org/x/sys v0.6.0
 )
 
@@ -14,3 +15,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/iden3/go-rapidsnark/types => ../types
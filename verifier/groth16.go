@@ -2,30 +2,154 @@ package verifier
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 
 	"github.com/iden3/go-iden3-crypto/constants"
 	"github.com/iden3/go-rapidsnark/types"
 	"github.com/iden3/go-rapidsnark/verifier/bn256"
 )
 
-// VerifyGroth16 performs a verification of zkp  based on verification key and public inputs
-func VerifyGroth16(zkProof types.ZKProof, verificationKey []byte) error {
+// VerifyingKey is a Groth16 verification key parsed and prepared for the
+// verification of many proofs. It is safe for concurrent use, including
+// Precompute concurrently with Verify.
+type VerifyingKey struct {
+	// curve is the curve of the key as named by snarkjs.
+	curve   string
+	nPublic int
+	// alphaBeta is e(alpha, beta), either computed when the key is parsed
+	// or taken from vk_alphabeta_12.
+	alphaBeta *bn256.GT
+	alpha     *bn256.G1
+	ic        []*bn256.G1
+	negBeta   *bn256.G2Prepared
+	negGamma  *bn256.G2Prepared
+	negDelta  *bn256.G2Prepared
+	beta      *bn256.G2
+	gamma     *bn256.G2
+	delta     *bn256.G2
+	// icTables holds the []*bn256.G1Table of ic once Precompute built them.
+	icTables   atomic.Value
+	precompute sync.Once
+	// bls is the key of the verification keys on the BLS12-381 curve, the
+	// other fields are not set then.
+	bls *blsVerifyingKey
+}
 
-	// 1. cast external proof data to internal model.
-	p, err := parseProofData(*zkProof.Proof)
+// ParseVerifyingKey parses a verification key in the snarkjs JSON format.
+// The curve field of the key selects the curve: bn128, the default, or
+// bls12381. If a bn128 key has vk_alphabeta_12 it is used instead of
+// computing e(alpha, beta).
+//
+// The protocol of the key must be groth16 and nPublic must match the number
+// of IC points, if they are set.
+func ParseVerifyingKey(verificationKey []byte) (*VerifyingKey, error) {
+	var vkStr vkJSON
+	err := json.Unmarshal(verificationKey, &vkStr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 2. cast external verification key data to internal model.
-	var vkStr vkJSON
-	err = json.Unmarshal(verificationKey, &vkStr)
+	if vkStr.Protocol != "" && vkStr.Protocol != "groth16" {
+		return nil, fmt.Errorf("verification key protocol is not groth16: %v",
+			vkStr.Protocol)
+	}
+	if vkStr.NPublic != nil && len(vkStr.IC) > 0 &&
+		*vkStr.NPublic != len(vkStr.IC)-1 {
+		return nil, fmt.Errorf(
+			"verification key nPublic %v does not match len(IC)-1 = %v",
+			*vkStr.NPublic, len(vkStr.IC)-1)
+	}
+
+	switch vkStr.Curve {
+	case "", "bn128":
+	case "bls12381":
+		blsKey, err := parseBLSVK(vkStr)
+		if err != nil {
+			return nil, err
+		}
+		return &VerifyingKey{curve: vkStr.Curve, nPublic: len(blsKey.ic) - 1,
+			bls: blsKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve: %v", vkStr.Curve)
+	}
+
+	vkKey, err := parseVK(vkStr)
 	if err != nil {
+		return nil, err
+	}
+
+	var alphaBeta *bn256.GT
+	if vkStr.AlphaBeta != nil {
+		alphaBeta, err = stringToGT(vkStr.AlphaBeta)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vk_alphabeta_12: %w", err)
+		}
+	} else {
+		alphaBeta = bn256.Pair(vkKey.Alpha, vkKey.Beta)
+	}
+
+	return &VerifyingKey{
+		curve:     "bn128",
+		nPublic:   len(vkKey.IC) - 1,
+		alphaBeta: alphaBeta,
+		alpha:     vkKey.Alpha,
+		ic:        vkKey.IC,
+		negBeta:   bn256.NewG2Prepared(new(bn256.G2).Neg(vkKey.Beta)),
+		negGamma:  bn256.NewG2Prepared(new(bn256.G2).Neg(vkKey.Gamma)),
+		negDelta:  bn256.NewG2Prepared(new(bn256.G2).Neg(vkKey.Delta)),
+		beta:      vkKey.Beta,
+		gamma:     vkKey.Gamma,
+		delta:     vkKey.Delta,
+	}, nil
+}
+
+// NPublic returns the number of public inputs of the circuit.
+func (vk *VerifyingKey) NPublic() int {
+	return vk.nPublic
+}
+
+// Curve returns the name of the curve of the key as used by snarkjs: bn128
+// or bls12381.
+func (vk *VerifyingKey) Curve() string {
+	return vk.curve
+}
+
+// Precompute builds tables of multiples of the IC points of the key, which
+// speed up the linear combination of the public inputs at the cost of about
+// 8KiB of memory per public input. It is worth it for keys that verify many
+// proofs. The verifications running while the tables are built do not use
+// them. It does nothing for BLS12-381 keys.
+func (vk *VerifyingKey) Precompute() {
+	if vk.bls != nil {
+		return
+	}
+	vk.precompute.Do(func() {
+		tables := make([]*bn256.G1Table, len(vk.ic))
+		for i := range vk.ic {
+			tables[i] = bn256.NewG1Table(vk.ic[i])
+		}
+		vk.icTables.Store(tables)
+	})
+}
+
+// Verify performs a verification of zkp against the verification key.
+func (vk *VerifyingKey) Verify(zkProof types.ZKProof) error {
+	if zkProof.Proof == nil {
+		return errors.New("proof is empty")
+	}
+	if err := vk.checkProofData(*zkProof.Proof); err != nil {
 		return err
 	}
-	vkKey, err := parseVK(vkStr)
+	if vk.bls != nil {
+		return vk.bls.verifyZKProof(zkProof)
+	}
+
+	// 1. cast external proof data to internal model.
+	p, err := parseProofData(*zkProof.Proof)
 	if err != nil {
 		return err
 	}
@@ -36,30 +160,78 @@ func VerifyGroth16(zkProof types.ZKProof, verificationKey []byte) error {
 		return err
 	}
 
-	return verify(vkKey, p, pubSignals)
+	return vk.verify(p, pubSignals)
+}
+
+// VerifyGroth16 performs a verification of zkp  based on verification key and public inputs
+func VerifyGroth16(zkProof types.ZKProof, verificationKey []byte) error {
+	vk, err := ParseVerifyingKey(verificationKey)
+	if err != nil {
+		return err
+	}
+	return vk.Verify(zkProof)
 }
 
 // verify performs the verification the Groth16 zkSNARK proofs
-func verify(vk *vk, proof proofPairingData, inputs []*big.Int) error {
-	if len(inputs)+1 != len(vk.IC) {
+func (vk *VerifyingKey) verify(proof proofPairingData, inputs []*big.Int) error {
+	if err := vk.checkInputs(inputs); err != nil {
+		return err
+	}
+	scalars := make([]*big.Int, 0, len(vk.ic))
+	scalars = append(scalars, big.NewInt(1))
+	scalars = append(scalars, inputs...)
+	vkX := vk.icCombination(scalars)
+
+	// e(A, B)·e(vkX, -gamma)·e(C, -delta) = e(alpha, beta)
+	g1 := []*bn256.G1{proof.A, vkX, proof.C}
+	g2 := []*bn256.G2Prepared{bn256.NewG2Prepared(proof.B), vk.negGamma,
+		vk.negDelta}
+
+	res := bn256.PairingCheckPrepared(g1, g2, vk.alphaBeta)
+	if !res {
+		return fmt.Errorf("invalid proofs")
+	}
+	return nil
+}
+
+// checkProofData checks that the protocol and the curve of the proof, if
+// they are set, match the verification key.
+func (vk *VerifyingKey) checkProofData(pr types.ProofData) error {
+	if pr.Protocol != "" && pr.Protocol != "groth16" {
+		return fmt.Errorf("proof protocol is not groth16: %v", pr.Protocol)
+	}
+	if pr.Curve != "" && pr.Curve != vk.curve {
+		return fmt.Errorf(
+			"proof curve %v does not match verification key curve %v",
+			pr.Curve, vk.curve)
+	}
+	return nil
+}
+
+// checkInputs checks that the public inputs match the verification key.
+func (vk *VerifyingKey) checkInputs(inputs []*big.Int) error {
+	return checkInputs(inputs, len(vk.ic), constants.Q)
+}
+
+// checkInputs checks that there is a public input for every IC point but the
+// first and that they are in the scalar field of order.
+func checkInputs(inputs []*big.Int, nIC int, order *big.Int) error {
+	if len(inputs)+1 != nIC {
 		return fmt.Errorf("len(inputs)+1 != len(vk.IC)")
 	}
-	vkX := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
 	for i := 0; i < len(inputs); i++ {
 		// check input inside field
-		if inputs[i].Cmp(constants.Q) != -1 {
+		if inputs[i].Sign() < 0 || inputs[i].Cmp(order) != -1 {
 			return fmt.Errorf("input value is not in the fields")
 		}
-		vkX = new(bn256.G1).Add(vkX, new(bn256.G1).ScalarMult(vk.IC[i+1], inputs[i]))
 	}
-	vkX = new(bn256.G1).Add(vkX, vk.IC[0])
-
-	g1 := []*bn256.G1{proof.A, new(bn256.G1).Neg(vk.Alpha), vkX.Neg(vkX), new(bn256.G1).Neg(proof.C)}
-	g2 := []*bn256.G2{proof.B, vk.Beta, vk.Gamma, vk.Delta}
+	return nil
+}
 
-	res := bn256.PairingCheck(g1, g2)
-	if !res {
-		return fmt.Errorf("invalid proofs")
+// icCombination returns ∑ scalars[i]·IC[i].
+func (vk *VerifyingKey) icCombination(scalars []*big.Int) *bn256.G1 {
+	if tables, ok := vk.icTables.Load().([]*bn256.G1Table); ok {
+		return new(bn256.G1).MultiScalarMultTable(tables, scalars)
 	}
-	return nil
+	return new(bn256.G1).MultiScalarMult(vk.ic, scalars)
 }
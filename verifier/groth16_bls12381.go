@@ -0,0 +1,158 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bls12381"
+)
+
+// blsVerifyingKey is a Groth16 verification key on the BLS12-381 curve.
+//
+// vk_alphabeta_12 is not used: e(alpha, beta) is one more pair of the
+// pairing check.
+type blsVerifyingKey struct {
+	alpha    *bls12381.G1
+	ic       []*bls12381.G1
+	negBeta  *bls12381.G2
+	negGamma *bls12381.G2
+	negDelta *bls12381.G2
+	delta    *bls12381.G2
+}
+
+// blsProofPairingData describes three components of zkp proof in BLS12-381
+// format.
+type blsProofPairingData struct {
+	A *bls12381.G1
+	B *bls12381.G2
+	C *bls12381.G1
+}
+
+func parseBLSVK(vkStr vkJSON) (*blsVerifyingKey, error) {
+	var v blsVerifyingKey
+	var err error
+	v.alpha, err = stringToBLSG1(vkStr.Alpha)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vk_alpha_1: %w", err)
+	}
+
+	beta, err := stringToBLSG2(vkStr.Beta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vk_beta_2: %w", err)
+	}
+	v.negBeta = new(bls12381.G2).Neg(beta)
+
+	gamma, err := stringToBLSG2(vkStr.Gamma)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vk_gamma_2: %w", err)
+	}
+	v.negGamma = new(bls12381.G2).Neg(gamma)
+
+	delta, err := stringToBLSG2(vkStr.Delta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vk_delta_2: %w", err)
+	}
+	v.negDelta = new(bls12381.G2).Neg(delta)
+	v.delta = delta
+
+	if len(vkStr.IC) == 0 {
+		return nil, errors.New("verification key has no IC points")
+	}
+	for i := 0; i < len(vkStr.IC); i++ {
+		p, err := stringToBLSG1(vkStr.IC[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid IC[%v]: %w", i, err)
+		}
+		v.ic = append(v.ic, p)
+	}
+
+	return &v, nil
+}
+
+func parseBLSProofData(pr types.ProofData) (blsProofPairingData, error) {
+	var (
+		p   blsProofPairingData
+		err error
+	)
+
+	p.A, err = stringToBLSG1(pr.A)
+	if err != nil {
+		return p, fmt.Errorf("invalid pi_a: %w", err)
+	}
+
+	p.B, err = stringToBLSG2(pr.B)
+	if err != nil {
+		return p, fmt.Errorf("invalid pi_b: %w", err)
+	}
+
+	p.C, err = stringToBLSG1(pr.C)
+	if err != nil {
+		return p, fmt.Errorf("invalid pi_c: %w", err)
+	}
+
+	return p, err
+}
+
+// verifyZKProof performs a verification of zkp against the verification key.
+func (vk *blsVerifyingKey) verifyZKProof(zkProof types.ZKProof) error {
+	p, err := parseBLSProofData(*zkProof.Proof)
+	if err != nil {
+		return err
+	}
+	pubSignals, err := stringsToArrayBigInt(zkProof.PubSignals)
+	if err != nil {
+		return err
+	}
+	return vk.verify(p, pubSignals)
+}
+
+// verify performs the verification of a Groth16 proof on BLS12-381.
+func (vk *blsVerifyingKey) verify(proof blsProofPairingData,
+	inputs []*big.Int) error {
+
+	if err := checkInputs(inputs, len(vk.ic), bls12381.Order); err != nil {
+		return err
+	}
+	vkX := new(bls12381.G1).Set(vk.ic[0])
+	t := new(bls12381.G1)
+	for i, in := range inputs {
+		vkX.Add(vkX, t.ScalarMult(vk.ic[i+1], in))
+	}
+
+	// e(A, B)·e(alpha, -beta)·e(vkX, -gamma)·e(C, -delta) = 1
+	g1 := []*bls12381.G1{proof.A, vk.alpha, vkX, proof.C}
+	g2 := []*bls12381.G2{proof.B, vk.negBeta, vk.negGamma, vk.negDelta}
+	if !bls12381.PairingCheck(g1, g2) {
+		return fmt.Errorf("invalid proofs")
+	}
+	return nil
+}
+
+// stringToBLSG1 parses a point of G1 of BLS12-381 in the snarkjs format.
+func stringToBLSG1(h []string) (*bls12381.G1, error) {
+	b, err := g1Bytes(h, bls12381.P, 48)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bls12381.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// stringToBLSG2 parses a point of G2 of BLS12-381 in the snarkjs format.
+// Points are checked to be in G2.
+func stringToBLSG2(h [][]string) (*bls12381.G2, error) {
+	b, err := g2Bytes(h, bls12381.P, 48)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bls12381.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
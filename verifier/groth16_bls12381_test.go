@@ -0,0 +1,112 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bls12381"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestBLSProof(t testing.TB) types.ZKProof {
+	proofJSON, err := os.ReadFile("testdata/bls12381_proof.json")
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile("testdata/bls12381_public.json")
+	require.NoError(t, err)
+
+	var zkProof types.ZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+func TestVerifyGroth16BLS12381(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestBLSProof(t)
+
+	require.NoError(t, VerifyGroth16(zkProof, vkJSON))
+
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(t, err)
+	require.Equal(t, 2, vk.NPublic())
+	require.Equal(t, "bls12381", vk.Curve())
+	vk.Precompute()
+	require.NoError(t, vk.Verify(zkProof))
+
+	wrong := zkProof
+	wrong.PubSignals = []string{"33", "4"}
+	require.EqualError(t, vk.Verify(wrong), "invalid proofs")
+
+	// inputs are in the scalar field of BLS12-381, larger than the one of
+	// bn128
+	wrong.PubSignals = []string{"33", bls12381.Order.String()}
+	require.EqualError(t, vk.Verify(wrong), "input value is not in the fields")
+
+	err = vk.BatchVerify([]types.ZKProof{zkProof, wrong, zkProof})
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1}, batchErr.Invalid())
+	require.NoError(t, vk.BatchVerify([]types.ZKProof{zkProof, zkProof}))
+
+	// a bn128 proof is not on BLS12-381
+	require.EqualError(t, vk.Verify(readTestProof(t)),
+		"invalid pi_a: bls12381: malformed point")
+	bnProof := readTestProof(t)
+	bnProof.Proof.Curve = "bn128"
+	require.EqualError(t, vk.Verify(bnProof),
+		"proof curve bn128 does not match verification key curve bls12381")
+}
+
+func TestParseVerifyingKeyCurve(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(t, err)
+
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "curve", "bls12377"))
+	require.EqualError(t, err, "unsupported curve: bls12377")
+
+	// 48-byte coordinates are not in the base field of bn128
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "curve", "bn128"))
+	require.ErrorContains(t, err,
+		"invalid vk_alpha_1: coordinate is not in the field")
+
+	// the point at infinity
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "vk_alpha_1",
+		[]string{"1", "1", "0"}))
+	require.NoError(t, err)
+
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "vk_alpha_1",
+		[]string{bls12381.P.String(), "1", "1"}))
+	require.EqualError(t, err, "invalid vk_alpha_1: coordinate is not in "+
+		"the field: "+bls12381.P.String())
+
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "vk_alpha_1",
+		[]string{"0", "2", "1"}))
+	require.EqualError(t, err,
+		"invalid vk_alpha_1: bls12381: point is not in G1")
+
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "vk_delta_2",
+		[][]string{{"1", "0"}, {"1", "0"}, {"1", "0"}}))
+	require.EqualError(t, err,
+		"invalid vk_delta_2: bls12381: malformed point")
+
+	_, err = ParseVerifyingKey(modifyVK(t, vkJSON, "IC", [][]string{}))
+	require.EqualError(t, err, "verification key has no IC points")
+}
+
+func BenchmarkVerifyGroth16BLS12381(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(b, err)
+	zkProof := readTestBLSProof(b)
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := vk.Verify(zkProof); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestProof(t testing.TB) types.ZKProof {
+	proofJSON, err := os.ReadFile("testdata/proof.json")
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile("testdata/public.json")
+	require.NoError(t, err)
+
+	var zkProof types.ZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+// vkWithoutAlphaBeta removes vk_alphabeta_12 from a verification key.
+func vkWithoutAlphaBeta(t testing.TB, vkJSON []byte) []byte {
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(vkJSON, &m))
+	delete(m, "vk_alphabeta_12")
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVerifyGroth16(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	require.NoError(t, VerifyGroth16(zkProof, vkJSON))
+	require.NoError(t, VerifyGroth16(zkProof, vkWithoutAlphaBeta(t, vkJSON)))
+
+	zkProof.PubSignals = []string{"396", "4"}
+	require.EqualError(t, VerifyGroth16(zkProof, vkJSON), "invalid proofs")
+}
+
+func TestVerifyingKey(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(t, err)
+	require.Equal(t, 2, vk.NPublic())
+	require.Equal(t, "bn128", vk.Curve())
+
+	// e(alpha, beta) computed from the key matches vk_alphabeta_12
+	vk2, err := ParseVerifyingKey(vkWithoutAlphaBeta(t, vkJSON))
+	require.NoError(t, err)
+	require.Equal(t, vk.alphaBeta.Marshal(), vk2.alphaBeta.Marshal())
+
+	// Precompute may run while the key verifies proofs. The errors are
+	// checked in the test goroutine, require must not be called from others.
+	const n = 4
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- vk.Verify(zkProof)
+		}()
+		go func() {
+			defer wg.Done()
+			vk.Precompute()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	badProof := zkProof
+	badProof.PubSignals = []string{"396", "4"}
+	require.EqualError(t, vk.Verify(badProof), "invalid proofs")
+
+	vk2.Precompute()
+	require.NoError(t, vk2.Verify(zkProof))
+	require.EqualError(t, vk2.Verify(badProof), "invalid proofs")
+
+	badProof.PubSignals = []string{"396"}
+	require.EqualError(t, vk.Verify(badProof), "len(inputs)+1 != len(vk.IC)")
+
+	badProof.PubSignals = []string{"396",
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617"}
+	require.EqualError(t, vk.Verify(badProof), "input value is not in the fields")
+}
+
+func BenchmarkVerifyGroth16(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(b, err)
+	zkProof := readTestProof(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = VerifyGroth16(zkProof, vkJSON)
+	}
+}
+
+func BenchmarkVerifyingKey(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(b, err)
+	zkProof := readTestProof(b)
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(b, err)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = vk.Verify(zkProof)
+	}
+}
+
+func TestVerifyBinaryProof(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	data, err := zkProof.MarshalBinary()
+	require.NoError(t, err)
+
+	// the points are compressed as bn256 does
+	p, err := parseProofData(*zkProof.Proof)
+	require.NoError(t, err)
+	require.Equal(t, p.A.MarshalCompressed(), data[:32])
+	require.Equal(t, p.B.MarshalCompressed(), data[32:96])
+	require.Equal(t, p.C.MarshalCompressed(), data[96:128])
+
+	var got types.ZKProof
+	require.NoError(t, got.UnmarshalBinary(data))
+	require.NoError(t, VerifyGroth16(got, vkJSON))
+}
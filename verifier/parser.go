@@ -2,7 +2,7 @@ package verifier
 
 import (
 	"bytes"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -11,6 +11,10 @@ import (
 	"github.com/iden3/go-rapidsnark/verifier/bn256"
 )
 
+// fieldP is the modulus of the base field of bn256.
+var fieldP, _ = new(big.Int).SetString(
+	"21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
 // proofPairingData describes three components of zkp proof in bn256 format.
 type proofPairingData struct {
 	A *bn256.G1
@@ -29,11 +33,17 @@ type vk struct {
 
 // vkJSON is the Verification Key data structure in string format (from json).
 type vkJSON struct {
-	Alpha []string   `json:"vk_alpha_1"`
-	Beta  [][]string `json:"vk_beta_2"`
-	Gamma [][]string `json:"vk_gamma_2"`
-	Delta [][]string `json:"vk_delta_2"`
-	IC    [][]string `json:"IC"`
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+	// NPublic is nil if the key has no nPublic.
+	NPublic *int       `json:"nPublic"`
+	Alpha   []string   `json:"vk_alpha_1"`
+	Beta    [][]string `json:"vk_beta_2"`
+	Gamma   [][]string `json:"vk_gamma_2"`
+	Delta   [][]string `json:"vk_delta_2"`
+	// AlphaBeta is e(alpha, beta) as computed by snarkjs.
+	AlphaBeta [][][]string `json:"vk_alphabeta_12,omitempty"`
+	IC        [][]string   `json:"IC"`
 }
 
 func parseProofData(pr types.ProofData) (proofPairingData, error) {
@@ -44,17 +54,17 @@ func parseProofData(pr types.ProofData) (proofPairingData, error) {
 
 	p.A, err = stringToG1(pr.A)
 	if err != nil {
-		return p, err
+		return p, fmt.Errorf("invalid pi_a: %w", err)
 	}
 
 	p.B, err = stringToG2(pr.B)
 	if err != nil {
-		return p, err
+		return p, fmt.Errorf("invalid pi_b: %w", err)
 	}
 
 	p.C, err = stringToG1(pr.C)
 	if err != nil {
-		return p, err
+		return p, fmt.Errorf("invalid pi_c: %w", err)
 	}
 
 	return p, err
@@ -64,28 +74,31 @@ func parseVK(vkStr vkJSON) (*vk, error) {
 	var err error
 	v.Alpha, err = stringToG1(vkStr.Alpha)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid vk_alpha_1: %w", err)
 	}
 
 	v.Beta, err = stringToG2(vkStr.Beta)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid vk_beta_2: %w", err)
 	}
 
 	v.Gamma, err = stringToG2(vkStr.Gamma)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid vk_gamma_2: %w", err)
 	}
 
 	v.Delta, err = stringToG2(vkStr.Delta)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid vk_delta_2: %w", err)
 	}
 
+	if len(vkStr.IC) == 0 {
+		return nil, errors.New("verification key has no IC points")
+	}
 	for i := 0; i < len(vkStr.IC); i++ {
 		p, err := stringToG1(vkStr.IC[i])
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid IC[%v]: %w", i, err)
 		}
 		v.IC = append(v.IC, p)
 	}
@@ -115,137 +128,222 @@ func stringToBigInt(s string) (*big.Int, error) {
 	}
 	return n, nil
 }
-func stringToG1(h []string) (*bn256.G1, error) {
-	if len(h) <= 2 {
-		return nil, fmt.Errorf("not enought data for stringToG1")
-	}
-	h = h[:2]
-	hexa := false
-	if len(h[0]) > 1 {
-		if h[0][:2] == "0x" {
-			hexa = true
-		}
-	}
-	in := ""
 
-	var b []byte
-	var err error
-	if hexa {
-		for i := range h {
-			in += strings.TrimPrefix(h[i], "0x")
-		}
-		b, err = hex.DecodeString(in)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// TODO TMP
-		// TODO use stringToBytes()
-		if h[0] == "1" {
-			h[0] = "0"
-		}
-		if h[1] == "1" {
-			h[1] = "0"
-		}
-		bi0, ok := new(big.Int).SetString(h[0], 10)
-		if !ok {
-			return nil, fmt.Errorf("error parsing stringToG1")
-		}
-		bi1, ok := new(big.Int).SetString(h[1], 10)
-		if !ok {
-			return nil, fmt.Errorf("error parsing stringToG1")
-		}
-		b0 := bi0.Bytes()
-		b1 := bi1.Bytes()
-		if len(b0) != 32 {
-			b0 = addZPadding(b0)
-		}
-		if len(b1) != 32 {
-			b1 = addZPadding(b1)
-		}
+// stringToFieldElement parses a coordinate of a point, in decimal or in
+// hexadecimal with the 0x prefix, and checks that it is in the base field.
+func stringToFieldElement(s string) (*big.Int, error) {
+	return stringToCoordinate(s, fieldP)
+}
 
-		b = append(b, b0...)
-		b = append(b, b1...)
+// stringToCoordinate parses a coordinate of a point, in decimal or in
+// hexadecimal with the 0x prefix, and checks that it is less than modulus.
+func stringToCoordinate(s string, modulus *big.Int) (*big.Int, error) {
+	n, err := stringToBigInt(s)
+	if err != nil {
+		return nil, err
 	}
-	p := new(bn256.G1)
-	_, err = p.Unmarshal(b)
+	if n.Sign() < 0 || n.Cmp(modulus) >= 0 {
+		return nil, fmt.Errorf("coordinate is not in the field: %s", s)
+	}
+	return n, nil
+}
 
-	return p, err
+// stringToG1 parses a point of G1 in the snarkjs format [x, y, z] of
+// projective coordinates. z must be 1, or 0 for the point at infinity.
+func stringToG1(h []string) (*bn256.G1, error) {
+	b, err := g1Bytes(h, fieldP, 32)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
+
+// stringToG2 parses a point of G2 in the snarkjs format [x, y, z] of
+// projective coordinates, each of them [real, imaginary]. z must be 1, or 0
+// for the point at infinity. Points are checked to be in G2.
+//
+// Hexadecimal coordinates are in the bn256 order: imaginary part first.
 func stringToG2(h [][]string) (*bn256.G2, error) {
-	if len(h) <= 2 {
-		return nil, fmt.Errorf("not enought data for stringToG2")
-	}
-	h = h[:2]
-	hexa := false
-	if len(h[0][0]) > 1 {
-		if h[0][0][:2] == "0x" {
-			hexa = true
-		}
+	b, err := g2Bytes(h, fieldP, 32)
+	if err != nil {
+		return nil, err
 	}
-	in := ""
-	var (
-		b   []byte
-		err error
-	)
-	if hexa {
-		for i := 0; i < len(h); i++ {
-			for j := 0; j < len(h[i]); j++ {
-				in += strings.TrimPrefix(h[i][j], "0x")
-			}
-		}
-		b, err = hex.DecodeString(in)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// TODO TMP
-		var bH []byte
-		bH, err = stringToBytes(h[0][1])
-		if err != nil {
-			return nil, err
-		}
-		b = append(b, bH...)
-		bH, err = stringToBytes(h[0][0])
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseG1 parses a point of G1 of bn128 in the snarkjs format [x, y, z] of
+// projective coordinates, in decimal or in hexadecimal with the 0x prefix.
+// z must be 1, or 0 for the point at infinity.
+func ParseG1(h []string) (*bn256.G1, error) {
+	return stringToG1(h)
+}
+
+// ParseG2 parses a point of G2 of bn128 in the snarkjs format [x, y, z] of
+// projective coordinates, each of them [real, imaginary]. z must be 1, or 0
+// for the point at infinity. Points are checked to be in G2.
+func ParseG2(h [][]string) (*bn256.G2, error) {
+	return stringToG2(h)
+}
+
+// g1Bytes converts a point of G1 in the snarkjs format to the affine
+// coordinates x and y of size bytes each, the encoding of the Unmarshal of
+// the curve packages. The point at infinity is all zeros.
+func g1Bytes(h []string, modulus *big.Int, size int) ([]byte, error) {
+	if len(h) != 3 {
+		return nil, fmt.Errorf("G1 point must have 3 coordinates, got %v",
+			len(h))
+	}
+	c := make([]*big.Int, len(h))
+	for i := range h {
+		var err error
+		c[i], err = stringToCoordinate(h[i], modulus)
 		if err != nil {
 			return nil, err
 		}
-		b = append(b, bH...)
-		bH, err = stringToBytes(h[1][1])
-		if err != nil {
-			return nil, err
+	}
+
+	b := make([]byte, 2*size)
+	switch {
+	case c[2].Sign() == 0:
+	case c[2].Cmp(big.NewInt(1)) == 0:
+		c[0].FillBytes(b[:size])
+		c[1].FillBytes(b[size:])
+	default:
+		return nil, fmt.Errorf("unsupported G1 z coordinate: %v", h[2])
+	}
+	return b, nil
+}
+
+// g2Bytes converts a point of G2 in the snarkjs format to the affine
+// coordinates x and y of the Unmarshal of the curve packages, the imaginary
+// part first and size bytes each. The point at infinity is all zeros.
+func g2Bytes(h [][]string, modulus *big.Int, size int) ([]byte, error) {
+	if len(h) != 3 {
+		return nil, fmt.Errorf("G2 point must have 3 coordinates, got %v",
+			len(h))
+	}
+	hexa := len(h[0]) > 0 && strings.HasPrefix(h[0][0], "0x")
+
+	// c[i] is the i-th coordinate with the imaginary part first
+	var c [3][2]*big.Int
+	for i := range h {
+		if len(h[i]) != 2 {
+			return nil, fmt.Errorf(
+				"G2 coordinate must have 2 elements, got %v", len(h[i]))
 		}
-		b = append(b, bH...)
-		bH, err = stringToBytes(h[1][0])
-		if err != nil {
-			return nil, err
+		for j := range h[i] {
+			n, err := stringToCoordinate(h[i][j], modulus)
+			if err != nil {
+				return nil, err
+			}
+			if hexa {
+				c[i][j] = n
+			} else {
+				c[i][1-j] = n
+			}
 		}
-		b = append(b, bH...)
 	}
 
-	p := new(bn256.G2)
-	_, err = p.Unmarshal(b)
-	return p, err
+	b := make([]byte, 4*size)
+	switch {
+	case c[2][0].Sign() == 0 && c[2][1].Sign() == 0:
+	case c[2][0].Sign() == 0 && c[2][1].Cmp(big.NewInt(1)) == 0:
+		c[0][0].FillBytes(b[:size])
+		c[0][1].FillBytes(b[size : 2*size])
+		c[1][0].FillBytes(b[2*size : 3*size])
+		c[1][1].FillBytes(b[3*size:])
+	default:
+		return nil, fmt.Errorf("unsupported G2 z coordinate: %v", h[2])
+	}
+	return b, nil
 }
-func addZPadding(b []byte) []byte {
-	var z [32]byte
-	var r []byte
-	r = append(r, z[len(b):]...) // add padding on the left
-	r = append(r, b...)
-	return r[:32]
+
+// g1Strings is the inverse of g1Bytes: it converts the affine coordinates of
+// size bytes each to a point of G1 in the snarkjs format.
+func g1Strings(m []byte, size int) []string {
+	if isZero(m) {
+		return []string{"0", "1", "0"}
+	}
+	return []string{
+		new(big.Int).SetBytes(m[:size]).String(),
+		new(big.Int).SetBytes(m[size:]).String(),
+		"1",
+	}
 }
-func stringToBytes(s string) ([]byte, error) {
-	if s == "1" {
-		s = "0"
+
+// g2Strings is the inverse of g2Bytes: it converts the affine coordinates,
+// the imaginary part first and size bytes each, to a point of G2 in the
+// snarkjs format.
+func g2Strings(m []byte, size int) [][]string {
+	if isZero(m) {
+		return [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}
 	}
-	bi, ok := new(big.Int).SetString(s, 10)
-	if !ok {
-		return nil, fmt.Errorf("error parsing bigint stringToBytes")
+	c := make([]string, 4)
+	for i := range c {
+		c[i] = new(big.Int).SetBytes(m[i*size : (i+1)*size]).String()
 	}
-	b := bi.Bytes()
-	if len(b) != 32 {
-		b = addZPadding(b)
+	return [][]string{{c[1], c[0]}, {c[3], c[2]}, {"1", "0"}}
+}
+
+// stringToGT parses vk_alphabeta_12 of a snarkjs verification key. snarkjs
+// stores GT elements as [c0, c1] with c0, c1 = [a, b, c] in Fp6 and every Fp2
+// coefficient as [real, imaginary], bn256 marshals x·ω + y with
+// x, y = a·τ² + b·τ + c and the imaginary part first.
+func stringToGT(h [][][]string) (*bn256.GT, error) {
+	if len(h) != 2 {
+		return nil, fmt.Errorf("GT element must have 2 coefficients, got %v",
+			len(h))
 	}
-	return b, nil
+	b := make([]byte, 0, 12*32)
+	for i := 1; i >= 0; i-- {
+		if len(h[i]) != 3 {
+			return nil, fmt.Errorf(
+				"GT coefficient must have 3 elements, got %v", len(h[i]))
+		}
+		for j := 2; j >= 0; j-- {
+			if len(h[i][j]) != 2 {
+				return nil, fmt.Errorf(
+					"GT coefficient must have 2 elements, got %v",
+					len(h[i][j]))
+			}
+			for k := 1; k >= 0; k-- {
+				n, err := stringToFieldElement(h[i][j][k])
+				if err != nil {
+					return nil, err
+				}
+				b = append(b, n.FillBytes(make([]byte, 32))...)
+			}
+		}
+	}
+	e, err := bn256.UnmarshalGT(b)
+	if err != nil {
+		return nil, err
+	}
+	return bn256.FromSnarkjsGT(e), nil
+}
 
+// gtStrings is the inverse of stringToGT: it converts the result of Pair to
+// the value snarkjs computes for the same points in its JSON format.
+func gtStrings(e *bn256.GT) [][][]string {
+	m := bn256.ToSnarkjsGT(e).Marshal()
+	h := make([][][]string, 2)
+	for i := 1; i >= 0; i-- {
+		h[i] = make([][]string, 3)
+		for j := 2; j >= 0; j-- {
+			h[i][j] = make([]string, 2)
+			for k := 1; k >= 0; k-- {
+				h[i][j][k] = new(big.Int).SetBytes(m[:32]).String()
+				m = m[32:]
+			}
+		}
+	}
+	return h
 }
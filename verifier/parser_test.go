@@ -0,0 +1,196 @@
+package verifier
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"github.com/stretchr/testify/require"
+)
+
+const fieldPStr = "21888242871839275222246405745257275088696311157297823662689037894645226208583"
+
+func TestStringToG1(t *testing.T) {
+	gen := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	inf := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+
+	p, err := stringToG1([]string{"1", "2", "1"})
+	require.NoError(t, err)
+	require.Equal(t, gen.Marshal(), p.Marshal())
+
+	p, err = stringToG1([]string{"0x01", "0x02", "0x01"})
+	require.NoError(t, err)
+	require.Equal(t, gen.Marshal(), p.Marshal())
+
+	for _, h := range [][]string{{"0", "1", "0"}, {"0", "0", "1"},
+		{"1", "2", "0"}} {
+		p, err = stringToG1(h)
+		require.NoError(t, err)
+		require.Equal(t, inf.Marshal(), p.Marshal())
+	}
+
+	testCases := []struct {
+		name string
+		h    []string
+		err  string
+	}{
+		{"empty", nil, "G1 point must have 3 coordinates, got 0"},
+		{"affine", []string{"1", "2"}, "G1 point must have 3 coordinates, got 2"},
+		{"not a number", []string{"1", "two", "1"},
+			"can not parse string to *big.Int: two"},
+		{"negative", []string{"-1", "2", "1"},
+			"coordinate is not in the field: -1"},
+		{"modulus", []string{fieldPStr, "2", "1"},
+			"coordinate is not in the field: " + fieldPStr},
+		{"z", []string{"1", "2", "2"}, "unsupported G1 z coordinate: 2"},
+		{"not on curve", []string{"1", "3", "1"}, "bn256: malformed point"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stringToG1(tc.h)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestStringToG2(t *testing.T) {
+	vkBytes, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	var vkStr vkJSON
+	require.NoError(t, json.Unmarshal(vkBytes, &vkStr))
+
+	_, err = stringToG2(vkStr.Beta)
+	require.NoError(t, err)
+
+	inf := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	p, err := stringToG2([][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}})
+	require.NoError(t, err)
+	require.Equal(t, inf.Marshal(), p.Marshal())
+
+	// on the twist but not in G2
+	notInG2 := [][]string{{"1", "0"},
+		{"18278151005453108793778860132295291098363647455926340152056652516292830556603",
+			"5912654199736721486680175016176231956195085055698687135131307249486702594212"},
+		{"1", "0"}}
+
+	testCases := []struct {
+		name string
+		h    [][]string
+		err  string
+	}{
+		{"empty", nil, "G2 point must have 3 coordinates, got 0"},
+		{"short coordinate", [][]string{{"1"}, {"1", "0"}, {"1", "0"}},
+			"G2 coordinate must have 2 elements, got 1"},
+		{"empty coordinate", [][]string{{}, {"1", "0"}, {"1", "0"}},
+			"G2 coordinate must have 2 elements, got 0"},
+		{"modulus", [][]string{{"1", fieldPStr}, {"1", "0"}, {"1", "0"}},
+			"coordinate is not in the field: " + fieldPStr},
+		{"z", [][]string{{"1", "0"}, {"1", "0"}, {"0", "1"}},
+			"unsupported G2 z coordinate: [0 1]"},
+		{"not on curve", [][]string{{"1", "0"}, {"1", "0"}, {"1", "0"}},
+			"bn256: malformed point"},
+		{"not in G2", notInG2, "bn256: point is not in G2"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stringToG2(tc.h)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseVerifyingKeyErrors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		field string
+		value string
+		err   string
+	}{
+		{"alpha", "vk_alpha_1", `["1","3","1"]`,
+			"invalid vk_alpha_1: bn256: malformed point"},
+		{"delta", "vk_delta_2", `[["1"]]`,
+			"invalid vk_delta_2: G2 point must have 3 coordinates, got 1"},
+		{"IC", "IC", `[["1","2","1"],["1","2","1"],["1"]]`,
+			"invalid IC[2]: G1 point must have 3 coordinates, got 1"},
+		{"no IC", "IC", `[]`, "verification key has no IC points"},
+		{"protocol", "protocol", `"plonk"`,
+			"verification key protocol is not groth16: plonk"},
+		{"nPublic", "nPublic", `3`,
+			"verification key nPublic 3 does not match len(IC)-1 = 2"},
+		{"alphabeta", "vk_alphabeta_12", `[[["1","2"]]]`,
+			"invalid vk_alphabeta_12: GT element must have 2 coefficients, got 1"},
+		{"alphabeta coefficient", "vk_alphabeta_12",
+			`[[["1","2"],["1","2"],["1"]],[]]`,
+			"invalid vk_alphabeta_12: GT coefficient must have 3 elements, got 0"},
+		{"alphabeta modulus", "vk_alphabeta_12",
+			`[[["1","2"],["1","2"],["1","2"]],[["1","2"],["1","2"],["1","` +
+				fieldPStr + `"]]]`,
+			"invalid vk_alphabeta_12: coordinate is not in the field: " +
+				fieldPStr},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var m map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(vkJSON, &m))
+			m[tc.field] = json.RawMessage(tc.value)
+			b, err := json.Marshal(m)
+			require.NoError(t, err)
+
+			_, err = ParseVerifyingKey(b)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestVerifyMalformedProof(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	vk, err := ParseVerifyingKey(vkJSON)
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	require.EqualError(t, vk.Verify(types.ZKProof{}), "proof is empty")
+
+	proof := *zkProof.Proof
+	proof.A = nil
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: &proof,
+		PubSignals: zkProof.PubSignals}),
+		"invalid pi_a: G1 point must have 3 coordinates, got 0")
+
+	proof = *zkProof.Proof
+	proof.B = [][]string{{"1", "0"}, {}, {"1", "0"}}
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: &proof,
+		PubSignals: zkProof.PubSignals}),
+		"invalid pi_b: G2 coordinate must have 2 elements, got 0")
+
+	proof = *zkProof.Proof
+	proof.C = []string{"0", "1", "0"}
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: &proof,
+		PubSignals: zkProof.PubSignals}), "invalid proofs")
+
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: zkProof.Proof,
+		PubSignals: []string{"396", "-3"}}), "input value is not in the fields")
+
+	proof = *zkProof.Proof
+	proof.Protocol = "plonk"
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: &proof,
+		PubSignals: zkProof.PubSignals}), "proof protocol is not groth16: plonk")
+
+	proof = *zkProof.Proof
+	proof.Curve = "bls12381"
+	require.EqualError(t, vk.Verify(types.ZKProof{Proof: &proof,
+		PubSignals: zkProof.PubSignals}),
+		"proof curve bls12381 does not match verification key curve bn128")
+
+	err = vk.BatchVerify([]types.ZKProof{zkProof,
+		{Proof: &proof, PubSignals: zkProof.PubSignals}})
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, []int{1}, batchErr.Invalid())
+}
@@ -0,0 +1,436 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// maxPower is the two-adicity of the scalar field, the biggest domain of a
+// PLONK circuit is 2^maxPower.
+const maxPower = 28
+
+// plonkVK is the PLONK Verification Key data structure in bn256 format.
+type plonkVK struct {
+	nPublic int
+	power   int
+	k1, k2  *big.Int
+	w       *big.Int
+
+	Qm, Ql, Qr, Qo, Qc *bn256.G1
+	S1, S2, S3         *bn256.G1
+	X2                 *bn256.G2
+}
+
+// plonkVKJSON is the PLONK Verification Key data structure in string format
+// (from json).
+type plonkVKJSON struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	Qm       []string   `json:"Qm"`
+	Ql       []string   `json:"Ql"`
+	Qr       []string   `json:"Qr"`
+	Qo       []string   `json:"Qo"`
+	Qc       []string   `json:"Qc"`
+	S1       []string   `json:"S1"`
+	S2       []string   `json:"S2"`
+	S3       []string   `json:"S3"`
+	X2       [][]string `json:"X_2"`
+	W        string     `json:"w"`
+}
+
+// plonkProof is the PLONK proof in bn256 format.
+type plonkProof struct {
+	A, B, C, Z, T1, T2, T3, Wxi, Wxiw *bn256.G1
+
+	evalA, evalB, evalC, evalS1, evalS2, evalZw *big.Int
+}
+
+// plonkChallenges are the challenges of the transcript of a PLONK proof.
+type plonkChallenges struct {
+	beta, gamma, alpha, xi, u *big.Int
+	// v[i] is v^i, v[0] is unused
+	v [6]*big.Int
+	// xin is xi^n and zh is xi^n - 1, the vanishing polynomial at xi
+	xin, zh *big.Int
+}
+
+// VerifyPlonk performs a verification of a snarkjs PLONK zkp based on
+// verification key and public inputs
+func VerifyPlonk(zkProof types.PlonkZKProof, verificationKey []byte) error {
+	if zkProof.Proof == nil {
+		return errors.New("proof is empty")
+	}
+
+	// 1. cast external verification key data to internal model.
+	var vkStr plonkVKJSON
+	err := json.Unmarshal(verificationKey, &vkStr)
+	if err != nil {
+		return err
+	}
+	vk, err := parsePlonkVK(vkStr)
+	if err != nil {
+		return err
+	}
+
+	// 2. cast external proof data to internal model.
+	p, err := parsePlonkProof(*zkProof.Proof)
+	if err != nil {
+		return err
+	}
+
+	// 3. cast external public inputs data to internal model.
+	pubSignals, err := stringsToArrayBigInt(zkProof.PubSignals)
+	if err != nil {
+		return err
+	}
+
+	return verifyPlonk(vk, p, pubSignals)
+}
+
+// verifyPlonk performs the verification of the PLONK zkSNARK proofs as the
+// snarkjs verifier does.
+func verifyPlonk(vk *plonkVK, proof *plonkProof, inputs []*big.Int) error {
+	if err := checkPublicInputs(inputs, vk.nPublic); err != nil {
+		return err
+	}
+
+	ch := plonkCalculateChallenges(vk, proof, inputs)
+
+	ch.xin = frPow2(ch.xi, vk.power)
+	ch.zh = frSub(new(big.Int), ch.xin, big.NewInt(1))
+
+	L, err := lagrangeEvaluations(ch.xi, ch.zh, vk.w, vk.power, vk.nPublic)
+	if err != nil {
+		return err
+	}
+
+	pi := publicInputsEvaluation(inputs, L)
+
+	// r0 is the constant term of the linearization polynomial
+	//
+	//	r0 = PI(xi) - L1(xi)·alpha² - alpha·zw·(a + beta·s1 + gamma)·
+	//		(b + beta·s2 + gamma)·(c + gamma)
+	alpha2 := frSquare(new(big.Int), ch.alpha)
+	e3a := plonkPermutationTerm(proof.evalA, ch.beta, proof.evalS1, ch.gamma)
+	e3b := plonkPermutationTerm(proof.evalB, ch.beta, proof.evalS2, ch.gamma)
+	e3c := frAdd(new(big.Int), proof.evalC, ch.gamma)
+	e3 := frMul(new(big.Int), e3a, e3b)
+	frMul(e3, e3, e3c)
+	frMul(e3, e3, proof.evalZw)
+	frMul(e3, e3, ch.alpha)
+	l1Alpha2 := frMul(new(big.Int), L[1], alpha2)
+	r0 := frSub(new(big.Int), pi, l1Alpha2)
+	frSub(r0, r0, e3)
+
+	// [D], the commitment of the linearization polynomial without r0
+	betaXi := frMul(new(big.Int), ch.beta, ch.xi)
+	d2a := plonkPermutationTerm(proof.evalA, betaXi, big.NewInt(1), ch.gamma)
+	frMul(d2a, d2a,
+		plonkPermutationTerm(proof.evalB, betaXi, vk.k1, ch.gamma))
+	frMul(d2a, d2a,
+		plonkPermutationTerm(proof.evalC, betaXi, vk.k2, ch.gamma))
+	frMul(d2a, d2a, ch.alpha)
+	zScalar := frAdd(new(big.Int), d2a, l1Alpha2)
+	frAdd(zScalar, zScalar, ch.u)
+
+	d3 := frMul(new(big.Int), e3a, e3b)
+	frMul(d3, d3, ch.alpha)
+	frMul(d3, d3, ch.beta)
+	frMul(d3, d3, proof.evalZw)
+
+	zhXin := frMul(new(big.Int), ch.zh, ch.xin)
+	zhXin2 := frMul(new(big.Int), zhXin, ch.xin)
+
+	// [E] = e·[1]
+	e := frSub(new(big.Int), big.NewInt(0), r0)
+	for i, eval := range []*big.Int{proof.evalA, proof.evalB, proof.evalC,
+		proof.evalS1, proof.evalS2} {
+		frAdd(e, e, frMul(new(big.Int), ch.v[i+1], eval))
+	}
+	frAdd(e, e, frMul(new(big.Int), ch.u, proof.evalZw))
+
+	// A1 = [Wxi] + u·[Wxiw]
+	// B1 = xi·[Wxi] + u·xi·w·[Wxiw] + [F] - [E]
+	// with [F] = [D] + v·[A] + v²·[B] + v³·[C] + v⁴·[S1] + v⁵·[S2]
+	uXiW := frMul(new(big.Int), ch.u, ch.xi)
+	frMul(uXiW, uXiW, vk.w)
+	a1 := new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{proof.Wxi, proof.Wxiw},
+		[]*big.Int{big.NewInt(1), ch.u})
+	b1 := new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{
+			vk.Qm, vk.Ql, vk.Qr, vk.Qo, vk.Qc,
+			proof.Z, vk.S3, proof.T1, proof.T2, proof.T3,
+			proof.A, proof.B, proof.C, vk.S1, vk.S2,
+			proof.Wxi, proof.Wxiw, g1Generator,
+		},
+		[]*big.Int{
+			frMul(new(big.Int), proof.evalA, proof.evalB),
+			proof.evalA, proof.evalB, proof.evalC, big.NewInt(1),
+			zScalar, frNeg(d3), frNeg(ch.zh), frNeg(zhXin), frNeg(zhXin2),
+			ch.v[1], ch.v[2], ch.v[3], ch.v[4], ch.v[5],
+			ch.xi, uXiW, frNeg(e),
+		})
+
+	// e(-A1, [x]_2)·e(B1, [1]_2) = 1
+	res := bn256.PairingCheck([]*bn256.G1{new(bn256.G1).Neg(a1), b1},
+		[]*bn256.G2{vk.X2, g2Generator})
+	if !res {
+		return fmt.Errorf("invalid proofs")
+	}
+	return nil
+}
+
+// plonkCalculateChallenges runs the Keccak transcript of a PLONK proof.
+func plonkCalculateChallenges(vk *plonkVK, proof *plonkProof,
+	inputs []*big.Int) *plonkChallenges {
+
+	var (
+		ch plonkChallenges
+		t  keccakTranscript
+	)
+
+	// round 2: beta and gamma
+	for _, p := range []*bn256.G1{vk.Qm, vk.Ql, vk.Qr, vk.Qo, vk.Qc,
+		vk.S1, vk.S2, vk.S3} {
+		t.addG1(p)
+	}
+	for _, in := range inputs {
+		t.addScalar(in)
+	}
+	t.addG1(proof.A)
+	t.addG1(proof.B)
+	t.addG1(proof.C)
+	ch.beta = t.challenge()
+
+	t.addScalar(ch.beta)
+	ch.gamma = t.challenge()
+
+	// round 3: alpha
+	t.addScalar(ch.beta)
+	t.addScalar(ch.gamma)
+	t.addG1(proof.Z)
+	ch.alpha = t.challenge()
+
+	// round 4: xi
+	t.addScalar(ch.alpha)
+	t.addG1(proof.T1)
+	t.addG1(proof.T2)
+	t.addG1(proof.T3)
+	ch.xi = t.challenge()
+
+	// round 5: v
+	t.addScalar(ch.xi)
+	for _, eval := range []*big.Int{proof.evalA, proof.evalB, proof.evalC,
+		proof.evalS1, proof.evalS2, proof.evalZw} {
+		t.addScalar(eval)
+	}
+	ch.v[1] = t.challenge()
+	for i := 2; i < len(ch.v); i++ {
+		ch.v[i] = frMul(new(big.Int), ch.v[i-1], ch.v[1])
+	}
+
+	// u
+	t.addG1(proof.Wxi)
+	t.addG1(proof.Wxiw)
+	ch.u = t.challenge()
+
+	return &ch
+}
+
+// lagrangeEvaluations returns L_i(xi) for i from 1 to max(1, nPublic), the
+// Lagrange polynomials of the domain of size n = 2^power generated by w,
+// where zh is xi^n - 1:
+//
+//	L_i(xi) = w^(i-1)·zh / (n·(xi - w^(i-1)))
+func lagrangeEvaluations(xi, zh, w *big.Int, power, nPublic int) ([]*big.Int,
+	error) {
+
+	n := new(big.Int).Lsh(big.NewInt(1), uint(power))
+
+	if nPublic < 1 {
+		nPublic = 1
+	}
+	L := make([]*big.Int, nPublic+1)
+	wi := big.NewInt(1)
+	for i := 1; i <= nPublic; i++ {
+		den := frSub(new(big.Int), xi, wi)
+		frMul(den, den, n)
+		if den.Sign() == 0 {
+			return nil, errors.New("invalid proofs")
+		}
+		den.ModInverse(den, constants.Q)
+		L[i] = frMul(new(big.Int), wi, zh)
+		frMul(L[i], L[i], den)
+		wi = frMul(new(big.Int), wi, w)
+	}
+	return L, nil
+}
+
+// publicInputsEvaluation returns PI(xi) = -∑ inputs[i]·L_{i+1}(xi).
+func publicInputsEvaluation(inputs, L []*big.Int) *big.Int {
+	pi := new(big.Int)
+	for i := range inputs {
+		frSub(pi, pi, frMul(new(big.Int), inputs[i], L[i+1]))
+	}
+	return pi
+}
+
+// checkPublicInputs checks that there are nPublic inputs in the scalar
+// field.
+func checkPublicInputs(inputs []*big.Int, nPublic int) error {
+	if len(inputs) != nPublic {
+		return fmt.Errorf("len(inputs) != vk.nPublic")
+	}
+	for i := range inputs {
+		if inputs[i].Sign() < 0 || inputs[i].Cmp(constants.Q) != -1 {
+			return fmt.Errorf("input value is not in the fields")
+		}
+	}
+	return nil
+}
+
+// plonkPermutationTerm returns a + beta·s + gamma.
+func plonkPermutationTerm(a, beta, s, gamma *big.Int) *big.Int {
+	r := frMul(new(big.Int), beta, s)
+	frAdd(r, r, a)
+	return frAdd(r, r, gamma)
+}
+
+func parsePlonkVK(vkStr plonkVKJSON) (*plonkVK, error) {
+	if vkStr.Protocol != "" && vkStr.Protocol != "plonk" {
+		return nil, fmt.Errorf("verification key protocol is not plonk: %v",
+			vkStr.Protocol)
+	}
+	if vkStr.Curve != "" && vkStr.Curve != "bn128" {
+		return nil, fmt.Errorf("unsupported curve: %v", vkStr.Curve)
+	}
+	if vkStr.NPublic < 0 {
+		return nil, fmt.Errorf("invalid nPublic: %v", vkStr.NPublic)
+	}
+	if vkStr.Power < 1 || vkStr.Power > maxPower {
+		return nil, fmt.Errorf("invalid power: %v", vkStr.Power)
+	}
+
+	v := plonkVK{nPublic: vkStr.NPublic, power: vkStr.Power}
+	var err error
+	for _, s := range []struct {
+		name string
+		str  string
+		dst  **big.Int
+	}{
+		{"k1", vkStr.K1, &v.k1},
+		{"k2", vkStr.K2, &v.k2},
+		{"w", vkStr.W, &v.w},
+	} {
+		*s.dst, err = stringToScalar(s.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", s.name, err)
+		}
+	}
+	if !isRootOfUnity(v.w, v.power) {
+		return nil, fmt.Errorf("invalid w: not a root of unity of order 2^%v",
+			v.power)
+	}
+
+	for _, p := range []struct {
+		name string
+		str  []string
+		dst  **bn256.G1
+	}{
+		{"Qm", vkStr.Qm, &v.Qm},
+		{"Ql", vkStr.Ql, &v.Ql},
+		{"Qr", vkStr.Qr, &v.Qr},
+		{"Qo", vkStr.Qo, &v.Qo},
+		{"Qc", vkStr.Qc, &v.Qc},
+		{"S1", vkStr.S1, &v.S1},
+		{"S2", vkStr.S2, &v.S2},
+		{"S3", vkStr.S3, &v.S3},
+	} {
+		*p.dst, err = stringToG1(p.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", p.name, err)
+		}
+	}
+
+	v.X2, err = stringToG2(vkStr.X2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X_2: %w", err)
+	}
+
+	return &v, nil
+}
+
+func parsePlonkProof(pr types.PlonkProofData) (*plonkProof, error) {
+	if pr.Protocol != "" && pr.Protocol != "plonk" {
+		return nil, fmt.Errorf("proof protocol is not plonk: %v", pr.Protocol)
+	}
+	if pr.Curve != "" && pr.Curve != "bn128" {
+		return nil, fmt.Errorf(
+			"proof curve %v does not match verification key curve bn128",
+			pr.Curve)
+	}
+
+	var (
+		p   plonkProof
+		err error
+	)
+	for _, c := range []struct {
+		name string
+		str  []string
+		dst  **bn256.G1
+	}{
+		{"A", pr.A, &p.A},
+		{"B", pr.B, &p.B},
+		{"C", pr.C, &p.C},
+		{"Z", pr.Z, &p.Z},
+		{"T1", pr.T1, &p.T1},
+		{"T2", pr.T2, &p.T2},
+		{"T3", pr.T3, &p.T3},
+		{"Wxi", pr.Wxi, &p.Wxi},
+		{"Wxiw", pr.Wxiw, &p.Wxiw},
+	} {
+		*c.dst, err = stringToG1(c.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", c.name, err)
+		}
+	}
+
+	for _, e := range []struct {
+		name string
+		str  string
+		dst  **big.Int
+	}{
+		{"eval_a", pr.EvalA, &p.evalA},
+		{"eval_b", pr.EvalB, &p.evalB},
+		{"eval_c", pr.EvalC, &p.evalC},
+		{"eval_s1", pr.EvalS1, &p.evalS1},
+		{"eval_s2", pr.EvalS2, &p.evalS2},
+		{"eval_zw", pr.EvalZw, &p.evalZw},
+	} {
+		*e.dst, err = stringToScalar(e.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", e.name, err)
+		}
+	}
+
+	return &p, nil
+}
+
+// isRootOfUnity reports whether w is a primitive root of unity of order
+// 2^power.
+func isRootOfUnity(w *big.Int, power int) bool {
+	// w^(2^(power-1)) must be -1
+	x := frPow2(w, power-1)
+	return frAdd(x, x, big.NewInt(1)).Sign() == 0
+}
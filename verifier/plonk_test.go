@@ -0,0 +1,182 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+// readTestPlonkProof reads the PLONK proof of the test vectors, see
+// testdata/README.md for their origin.
+func readTestPlonkProof(t testing.TB) types.PlonkZKProof {
+	proofJSON, err := os.ReadFile("testdata/plonk_proof.json")
+	require.NoError(t, err)
+	publicJSON, err := os.ReadFile("testdata/plonk_public.json")
+	require.NoError(t, err)
+
+	var zkProof types.PlonkZKProof
+	require.NoError(t, json.Unmarshal(proofJSON, &zkProof.Proof))
+	require.NoError(t, json.Unmarshal(publicJSON, &zkProof.PubSignals))
+	return zkProof
+}
+
+// modifyVK sets the field of a verification key to value.
+func modifyVK(t testing.TB, vkJSON []byte, field string,
+	value interface{}) []byte {
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(vkJSON, &m))
+	m[field] = value
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVerifyPlonk(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/plonk_verification_key.json")
+	require.NoError(t, err)
+
+	zkProof := readTestPlonkProof(t)
+	require.NoError(t, VerifyPlonk(zkProof, vkJSON))
+
+	zkProof.PubSignals = []string{"396", "48"}
+	require.EqualError(t, VerifyPlonk(zkProof, vkJSON), "invalid proofs")
+
+	zkProof = readTestPlonkProof(t)
+	zkProof.Proof.EvalA = "1"
+	require.EqualError(t, VerifyPlonk(zkProof, vkJSON), "invalid proofs")
+
+	zkProof = readTestPlonkProof(t)
+	zkProof.Proof.Wxi, zkProof.Proof.Wxiw = zkProof.Proof.Wxiw, zkProof.Proof.Wxi
+	require.EqualError(t, VerifyPlonk(zkProof, vkJSON), "invalid proofs")
+}
+
+func TestVerifyPlonkErrors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/plonk_verification_key.json")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		vk      []byte
+		modify  func(p *types.PlonkZKProof)
+		wantErr string
+	}{
+		{
+			name:    "empty proof",
+			vk:      vkJSON,
+			modify:  func(p *types.PlonkZKProof) { p.Proof = nil },
+			wantErr: "proof is empty",
+		},
+		{
+			name: "too many inputs",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.PubSignals = append(p.PubSignals, "1")
+			},
+			wantErr: "len(inputs) != vk.nPublic",
+		},
+		{
+			name:    "wrong nPublic",
+			vk:      modifyVK(t, vkJSON, "nPublic", 1),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "len(inputs) != vk.nPublic",
+		},
+		{
+			name: "input not in the field",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.PubSignals[0] = constants.Q.String()
+			},
+			wantErr: "input value is not in the fields",
+		},
+		{
+			name: "eval not in the field",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.Proof.EvalZw = constants.Q.String()
+			},
+			wantErr: "invalid eval_zw: scalar is not in the field: " +
+				constants.Q.String(),
+		},
+		{
+			name: "malformed commitment",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.Proof.T2 = p.Proof.T2[:2]
+			},
+			wantErr: "invalid T2: G1 point must have 3 coordinates, got 2",
+		},
+		{
+			name: "groth16 proof",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.Proof.Protocol = "groth16"
+			},
+			wantErr: "proof protocol is not plonk: groth16",
+		},
+		{
+			name:    "groth16 key",
+			vk:      modifyVK(t, vkJSON, "protocol", "groth16"),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "verification key protocol is not plonk: groth16",
+		},
+		{
+			name: "bls12381 proof",
+			vk:   vkJSON,
+			modify: func(p *types.PlonkZKProof) {
+				p.Proof.Curve = "bls12381"
+			},
+			wantErr: "proof curve bls12381 does not match verification key " +
+				"curve bn128",
+		},
+		{
+			name:    "bls12381 key",
+			vk:      modifyVK(t, vkJSON, "curve", "bls12381"),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "unsupported curve: bls12381",
+		},
+		{
+			name:    "invalid power",
+			vk:      modifyVK(t, vkJSON, "power", 29),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "invalid power: 29",
+		},
+		{
+			name:    "w of the wrong order",
+			vk:      modifyVK(t, vkJSON, "power", 4),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "invalid w: not a root of unity of order 2^4",
+		},
+		{
+			name:    "malformed X_2",
+			vk:      modifyVK(t, vkJSON, "X_2", [][]string{{"1", "2"}}),
+			modify:  func(p *types.PlonkZKProof) {},
+			wantErr: "invalid X_2: G2 point must have 3 coordinates, got 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zkProof := readTestPlonkProof(t)
+			tc.modify(&zkProof)
+			require.EqualError(t, VerifyPlonk(zkProof, tc.vk), tc.wantErr)
+		})
+	}
+}
+
+func BenchmarkVerifyPlonk(b *testing.B) {
+	vkJSON, err := os.ReadFile("testdata/plonk_verification_key.json")
+	require.NoError(b, err)
+	zkProof := readTestPlonkProof(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyPlonk(zkProof, vkJSON); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
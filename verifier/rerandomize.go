@@ -0,0 +1,108 @@
+package verifier
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/iden3/go-rapidsnark/verifier/bls12381"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// RerandomizeGroth16 returns a new Groth16 proof for the same public signals
+// that can't be linked to zkProof. See VerifyingKey.Rerandomize.
+func RerandomizeGroth16(zkProof types.ZKProof, verificationKey []byte,
+	rnd io.Reader) (types.ZKProof, error) {
+
+	vk, err := ParseVerifyingKey(verificationKey)
+	if err != nil {
+		return types.ZKProof{}, err
+	}
+	return vk.Rerandomize(zkProof, rnd)
+}
+
+// Rerandomize returns a new Groth16 proof for the same public signals that
+// can't be linked to zkProof. With random r≠0 and s read from rnd the new
+// proof is
+//
+//	A' = r⁻¹·A
+//	B' = r·B + r·s·delta
+//	C' = C + s·A
+//
+// which is valid if and only if zkProof is. The proof is not verified.
+func (vk *VerifyingKey) Rerandomize(zkProof types.ZKProof,
+	rnd io.Reader) (types.ZKProof, error) {
+
+	if zkProof.Proof == nil {
+		return types.ZKProof{}, errors.New("proof is empty")
+	}
+	if err := vk.checkProofData(*zkProof.Proof); err != nil {
+		return types.ZKProof{}, err
+	}
+
+	order := constants.Q
+	if vk.bls != nil {
+		order = bls12381.Order
+	}
+	r, s, err := rerandomizeScalars(rnd, order)
+	if err != nil {
+		return types.ZKProof{}, err
+	}
+	rInv := new(big.Int).ModInverse(r, order)
+	rs := new(big.Int).Mul(r, s)
+	rs.Mod(rs, order)
+
+	var pr types.ProofData
+	if vk.bls != nil {
+		p, err := parseBLSProofData(*zkProof.Proof)
+		if err != nil {
+			return types.ZKProof{}, err
+		}
+		a := new(bls12381.G1).ScalarMult(p.A, rInv)
+		b := new(bls12381.G2).ScalarMult(p.B, r)
+		b.Add(b, new(bls12381.G2).ScalarMult(vk.bls.delta, rs))
+		c := new(bls12381.G1).ScalarMult(p.A, s)
+		c.Add(c, p.C)
+		pr.A = g1Strings(a.Marshal(), 48)
+		pr.B = g2Strings(b.Marshal(), 48)
+		pr.C = g1Strings(c.Marshal(), 48)
+	} else {
+		p, err := parseProofData(*zkProof.Proof)
+		if err != nil {
+			return types.ZKProof{}, err
+		}
+		a := new(bn256.G1).ScalarMult(p.A, rInv)
+		b := new(bn256.G2).ScalarMult(p.B, r)
+		b.Add(b, new(bn256.G2).ScalarMult(vk.delta, rs))
+		c := new(bn256.G1).ScalarMult(p.A, s)
+		c.Add(c, p.C)
+		pr.A = g1Strings(a.Marshal(), 32)
+		pr.B = g2Strings(b.Marshal(), 32)
+		pr.C = g1Strings(c.Marshal(), 32)
+	}
+	pr.Protocol = "groth16"
+	pr.Curve = vk.curve
+
+	pubSignals := make([]string, len(zkProof.PubSignals))
+	copy(pubSignals, zkProof.PubSignals)
+	return types.ZKProof{Proof: &pr, PubSignals: pubSignals}, nil
+}
+
+// rerandomizeScalars returns the random r and s of Rerandomize, r≠0.
+func rerandomizeScalars(rnd io.Reader, order *big.Int) (r, s *big.Int,
+	err error) {
+
+	r, err = rand.Int(rnd, new(big.Int).Sub(order, big.NewInt(1)))
+	if err != nil {
+		return nil, nil, err
+	}
+	r.Add(r, big.NewInt(1))
+	s, err = rand.Int(rnd, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, s, nil
+}
@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/iden3/go-rapidsnark/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerandomizeGroth16(t *testing.T) {
+	testCases := []struct {
+		curve string
+		vk    string
+		proof types.ZKProof
+	}{
+		{"bn128", "testdata/verification_key.json", readTestProof(t)},
+		{"bls12381", "testdata/bls12381_verification_key.json",
+			readTestBLSProof(t)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.curve, func(t *testing.T) {
+			vkJSON, err := os.ReadFile(tc.vk)
+			require.NoError(t, err)
+
+			p1, err := RerandomizeGroth16(tc.proof, vkJSON, rand.Reader)
+			require.NoError(t, err)
+			require.NoError(t, VerifyGroth16(p1, vkJSON))
+			require.Equal(t, tc.proof.PubSignals, p1.PubSignals)
+			require.Equal(t, tc.curve, p1.Proof.Curve)
+			require.NotEqual(t, tc.proof.Proof.A, p1.Proof.A)
+			require.NotEqual(t, tc.proof.Proof.B, p1.Proof.B)
+			require.NotEqual(t, tc.proof.Proof.C, p1.Proof.C)
+
+			// the snarkjs JSON of the new proof
+			proofJSON, err := json.Marshal(p1.Proof)
+			require.NoError(t, err)
+			require.NoError(t, Verify(proofJSON, p1.PubSignals, vkJSON))
+
+			p2, err := RerandomizeGroth16(p1, vkJSON, rand.Reader)
+			require.NoError(t, err)
+			require.NoError(t, VerifyGroth16(p2, vkJSON))
+			require.NotEqual(t, p1.Proof.A, p2.Proof.A)
+
+			// an invalid proof stays invalid
+			wrong := tc.proof
+			wrong.PubSignals = append([]string{"1"}, tc.proof.PubSignals[1:]...)
+			p3, err := RerandomizeGroth16(wrong, vkJSON, rand.Reader)
+			require.NoError(t, err)
+			require.EqualError(t, VerifyGroth16(p3, vkJSON), "invalid proofs")
+		})
+	}
+}
+
+func TestRerandomizeGroth16Errors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	zkProof := readTestProof(t)
+
+	_, err = RerandomizeGroth16(types.ZKProof{}, vkJSON, rand.Reader)
+	require.EqualError(t, err, "proof is empty")
+
+	_, err = RerandomizeGroth16(zkProof, vkJSON, bytes.NewReader(nil))
+	require.EqualError(t, err, "EOF")
+
+	_, err = RerandomizeGroth16(readTestBLSProof(t), vkJSON, rand.Reader)
+	require.EqualError(t, err,
+		"proof curve bls12381 does not match verification key curve bn128")
+
+	proof := *zkProof.Proof
+	proof.A = nil
+	_, err = RerandomizeGroth16(types.ZKProof{Proof: &proof}, vkJSON,
+		rand.Reader)
+	require.EqualError(t, err,
+		"invalid pi_a: G1 point must have 3 coordinates, got 0")
+}
@@ -0,0 +1,167 @@
+package verifier
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed verifier_groth16.sol.tmpl
+var solidityGroth16Template string
+
+var solidityGroth16 = template.Must(
+	template.New("verifier_groth16.sol").Parse(solidityGroth16Template))
+
+var solidityIdentifier = regexp.MustCompile(`^[a-zA-Z$_][a-zA-Z0-9$_]*$`)
+
+// SolidityOption configures the Solidity verifier generated by
+// ExportSolidityVerifier.
+type SolidityOption func(cfg *solidityConfig)
+
+type solidityConfig struct {
+	pragma       string
+	contractName string
+}
+
+// WithPragma sets the version of the Solidity pragma of the contract,
+// ">=0.7.0 <0.9.0" by default.
+func WithPragma(pragma string) SolidityOption {
+	return func(cfg *solidityConfig) {
+		cfg.pragma = pragma
+	}
+}
+
+// WithContractName sets the name of the contract, Groth16Verifier by
+// default.
+func WithContractName(name string) SolidityOption {
+	return func(cfg *solidityConfig) {
+		cfg.contractName = name
+	}
+}
+
+// solidityG1 is a point of G1 as the decimal affine coordinates used by the
+// precompiled contracts of the EVM.
+type solidityG1 struct {
+	X, Y string
+}
+
+// solidityG2 is a point of G2 as the decimal affine coordinates used by the
+// precompiled contracts of the EVM: the imaginary part first.
+type solidityG2 struct {
+	X1, X2, Y1, Y2 string
+}
+
+// solidityInput is a public input of the circuit: the index of its IC point
+// and the offset of the input in the calldata array.
+type solidityInput struct {
+	IC     int
+	Offset int
+}
+
+type solidityData struct {
+	Pragma       string
+	ContractName string
+	Alpha        solidityG1
+	Beta         solidityG2
+	Gamma        solidityG2
+	Delta        solidityG2
+	IC           []solidityG1
+	Inputs       []solidityInput
+	// FieldChecks are the offsets in the calldata array of the words
+	// checked to be in the scalar field. As in snarkjs, there is one check
+	// for every IC point, so the last one reads the word after the public
+	// inputs, which is zero unless the call has trailing data.
+	FieldChecks []int
+}
+
+// ExportSolidityVerifier generates a Solidity contract that verifies the
+// Groth16 proofs of a verification key in the snarkjs JSON format, the same
+// as `snarkjs zkey export solidityverifier` does. The contract follows the
+// layout of the snarkjs template: its verifyProof function takes the proof
+// and the public inputs as generated by `snarkjs zkey export soliditycalldata`.
+// Only keys on the bn128 curve, the one of the EVM, are supported.
+func ExportSolidityVerifier(verificationKey []byte,
+	opts ...SolidityOption) ([]byte, error) {
+
+	key, err := ParseVerifyingKey(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	if key.bls != nil {
+		return nil, fmt.Errorf(
+			"solidity verifier is not supported on curve %v", key.curve)
+	}
+
+	var vkStr vkJSON
+	err = json.Unmarshal(verificationKey, &vkStr)
+	if err != nil {
+		return nil, err
+	}
+	vkKey, err := parseVK(vkStr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := solidityConfig{
+		pragma:       ">=0.7.0 <0.9.0",
+		contractName: "Groth16Verifier",
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !solidityIdentifier.MatchString(cfg.contractName) {
+		return nil, fmt.Errorf("invalid contract name: %q", cfg.contractName)
+	}
+	if cfg.pragma == "" || strings.ContainsAny(cfg.pragma, ";\r\n") {
+		return nil, fmt.Errorf("invalid pragma: %q", cfg.pragma)
+	}
+
+	data := solidityData{
+		Pragma:       cfg.pragma,
+		ContractName: cfg.contractName,
+		Alpha:        toSolidityG1(vkKey.Alpha.Marshal()),
+		Beta:         toSolidityG2(vkKey.Beta.Marshal()),
+		Gamma:        toSolidityG2(vkKey.Gamma.Marshal()),
+		Delta:        toSolidityG2(vkKey.Delta.Marshal()),
+	}
+	for i, p := range vkKey.IC {
+		data.IC = append(data.IC, toSolidityG1(p.Marshal()))
+		data.FieldChecks = append(data.FieldChecks, i*32)
+		if i > 0 {
+			data.Inputs = append(data.Inputs,
+				solidityInput{IC: i, Offset: (i - 1) * 32})
+		}
+	}
+
+	var buf bytes.Buffer
+	err = solidityGroth16.Execute(&buf, data)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// solidityWords converts m, a sequence of big-endian 32-byte words, to
+// decimal strings.
+func solidityWords(m []byte) []string {
+	words := make([]string, len(m)/32)
+	for i := range words {
+		words[i] = new(big.Int).SetBytes(m[i*32 : (i+1)*32]).String()
+	}
+	return words
+}
+
+func toSolidityG1(m []byte) solidityG1 {
+	w := solidityWords(m)
+	return solidityG1{X: w[0], Y: w[1]}
+}
+
+func toSolidityG2(m []byte) solidityG2 {
+	w := solidityWords(m)
+	return solidityG2{X1: w[0], X2: w[1], Y1: w[2], Y2: w[3]}
+}
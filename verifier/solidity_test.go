@@ -0,0 +1,81 @@
+package verifier
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSolidityVerifier(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	// verifier_groth16.sol was not written by snarkjs, the zkey of
+	// verification_key.json is not in the repository. It is the output of
+	// "proof export-solidity -vk testdata/verification_key.json", checked
+	// line by line against templates/verifier_groth16.sol.ejs of snarkjs
+	// 0.7, the template of "snarkjs zkey export solidityverifier". The
+	// contract is compiled and run in the EVM by TestSolidityVerifier of the
+	// tests module when solc is installed.
+	want, err := os.ReadFile("testdata/verifier_groth16.sol")
+	require.NoError(t, err)
+
+	sol, err := ExportSolidityVerifier(vkJSON)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(sol))
+
+	sol, err = ExportSolidityVerifier(vkJSON, WithPragma("^0.8.20"),
+		WithContractName("AuthVerifier"))
+	require.NoError(t, err)
+	wantCustom := strings.Replace(string(want),
+		"pragma solidity >=0.7.0 <0.9.0;", "pragma solidity ^0.8.20;", 1)
+	wantCustom = strings.Replace(wantCustom, "contract Groth16Verifier {",
+		"contract AuthVerifier {", 1)
+	require.Equal(t, wantCustom, string(sol))
+}
+
+func TestExportSolidityVerifierErrors(t *testing.T) {
+	vkJSON, err := os.ReadFile("testdata/verification_key.json")
+	require.NoError(t, err)
+	blsVKJSON, err := os.ReadFile("testdata/bls12381_verification_key.json")
+	require.NoError(t, err)
+	plonkVKJSON, err := os.ReadFile("testdata/plonk_verification_key.json")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		vk      []byte
+		opts    []SolidityOption
+		wantErr string
+	}{
+		{
+			name:    "bls12381 key",
+			vk:      blsVKJSON,
+			wantErr: "solidity verifier is not supported on curve bls12381",
+		},
+		{
+			name:    "plonk key",
+			vk:      plonkVKJSON,
+			wantErr: "verification key protocol is not groth16: plonk",
+		},
+		{
+			name:    "invalid contract name",
+			vk:      vkJSON,
+			opts:    []SolidityOption{WithContractName("Verifier {")},
+			wantErr: `invalid contract name: "Verifier {"`,
+		},
+		{
+			name:    "invalid pragma",
+			vk:      vkJSON,
+			opts:    []SolidityOption{WithPragma("^0.8.0;\ncontract X {}")},
+			wantErr: `invalid pragma: "^0.8.0;\ncontract X {}"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ExportSolidityVerifier(tc.vk, tc.opts...)
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"log"
+	"math/big"
+)
+
+type fflonkVerificationKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	W        string     `json:"w"`
+	W3       string     `json:"w3"`
+	W4       string     `json:"w4"`
+	W8       string     `json:"w8"`
+	Wr       string     `json:"wr"`
+	X2       [][]string `json:"X_2"`
+	C0       []string   `json:"C0"`
+}
+
+type fflonkPolynomials struct {
+	C1 []string `json:"C1"`
+	C2 []string `json:"C2"`
+	W1 []string `json:"W1"`
+	W2 []string `json:"W2"`
+}
+
+type fflonkEvaluations struct {
+	Ql  string `json:"ql"`
+	Qr  string `json:"qr"`
+	Qm  string `json:"qm"`
+	Qo  string `json:"qo"`
+	Qc  string `json:"qc"`
+	S1  string `json:"s1"`
+	S2  string `json:"s2"`
+	S3  string `json:"s3"`
+	A   string `json:"a"`
+	B   string `json:"b"`
+	C   string `json:"c"`
+	Z   string `json:"z"`
+	Zw  string `json:"zw"`
+	T1w string `json:"t1w"`
+	T2w string `json:"t2w"`
+	Inv string `json:"inv"`
+}
+
+type fflonkProof struct {
+	Polynomials fflonkPolynomials `json:"polynomials"`
+	Evaluations fflonkEvaluations `json:"evaluations"`
+	Protocol    string            `json:"protocol"`
+	Curve       string            `json:"curve"`
+}
+
+// proveFflonk proves the circuit for x = 5 and y = 77, following
+// snarkjs/src/fflonk_prove.js without blinding.
+func proveFflonk() (fflonkVerificationKey, fflonkProof, []string) {
+	const power = 3
+	tau, _ := new(big.Int).SetString("2718281828459045235360287471352662497757247093699959574966967627", 10)
+	cs := newCircuit(power, fe(5), fe(77), 9)
+	n := cs.n
+
+	w := rootOfUnity(power)
+	w4, w8 := rootOfUnity(2), rootOfUnity(3)
+	w3 := exp(fe(5), new(big.Int).Div(sub(fe(0), fe(1)), big.NewInt(3)))
+	if w3.Cmp(fe(1)) == 0 || exp(w3, big.NewInt(3)).Cmp(fe(1)) != 0 {
+		log.Fatal("w3 is not a cube root of unity")
+	}
+	// a cube root of w, of order 3·n
+	wr := mul(exp(w, big.NewInt(3)), w3)
+	if exp(wr, big.NewInt(3)).Cmp(w) != 0 {
+		log.Fatal("wr is not a cube root of w")
+	}
+
+	pA := interpolate(cs.domain, cs.a)
+	pB := interpolate(cs.domain, cs.b)
+	pC := interpolate(cs.domain, cs.c)
+	pQm := interpolate(cs.domain, cs.qm)
+	pQl := interpolate(cs.domain, cs.ql)
+	pQr := interpolate(cs.domain, cs.qr)
+	pQo := interpolate(cs.domain, cs.qo)
+	pQc := interpolate(cs.domain, cs.qc)
+	pS1 := interpolate(cs.domain, cs.sigma[0])
+	pS2 := interpolate(cs.domain, cs.sigma[1])
+	pS3 := interpolate(cs.domain, cs.sigma[2])
+	pPI := cs.publicPoly()
+	pL1 := cs.lagrange1()
+
+	// C0 combines the preprocessed polynomials, C0(X) = Σ p_j(X⁸)·Xʲ
+	pC0 := combine(8, pQl, pQr, pQo, pQm, pQc, pS1, pS2, pS3)
+	c0 := commit(pC0, tau)
+
+	// round 1: the wires and the gate quotient
+	gate := padd(padd(padd(padd(padd(pmul(pQl, pA), pmul(pQr, pB)),
+		pmul(pmul(pQm, pA), pB)), pmul(pQo, pC)), pQc), pPI)
+	pT0 := divZH(gate, n)
+	pC1 := combine(4, pA, pB, pC, pT0)
+	c1 := commit(pC1, tau)
+
+	var tr transcript
+	tr.addPoint(c0)
+	for _, p := range cs.public {
+		tr.addScalar(p)
+	}
+	tr.addPoint(c1)
+	beta := tr.challenge()
+	tr.addScalar(beta)
+	gamma := tr.challenge()
+
+	// round 2: the permutation
+	pZ := interpolate(cs.domain, cs.grandProduct(beta, gamma))
+	pZw := make(poly, len(pZ))
+	wi := fe(1)
+	for i := range pZ {
+		pZw[i] = mul(pZ[i], wi)
+		wi = mul(wi, w)
+	}
+	x := poly{fe(0), fe(1)}
+	plusGamma := func(p, q poly) poly {
+		return padd(padd(p, q), pconst(gamma))
+	}
+	pT1 := divZH(pmul(padd(pZ, pconst(fe(-1))), pL1), n)
+	perm1 := pmul(pmul(pmul(
+		plusGamma(pA, pscale(x, beta)),
+		plusGamma(pB, pscale(x, mul(beta, k[1])))),
+		plusGamma(pC, pscale(x, mul(beta, k[2])))), pZ)
+	perm2 := pmul(pmul(pmul(
+		plusGamma(pA, pscale(pS1, beta)),
+		plusGamma(pB, pscale(pS2, beta))),
+		plusGamma(pC, pscale(pS3, beta))), pZw)
+	pT2 := divZH(padd(perm1, pscale(perm2, fe(-1))), n)
+	pC2 := combine(3, pZ, pT1, pT2)
+	c2 := commit(pC2, tau)
+	tr.addScalar(gamma)
+	tr.addPoint(c2)
+	xiSeed := tr.challenge()
+
+	// round 3: the opening points, xi = h0⁸ = h1⁴ = h2³
+	xiSeed2 := mul(xiSeed, xiSeed)
+	h0 := mul(xiSeed2, xiSeed)
+	h1 := mul(h0, h0)
+	h2 := mul(h1, xiSeed2)
+	h3 := mul(h2, wr)
+	xi := mul(mul(h2, h2), h2)
+	roots := func(h, w *big.Int, k int) []*big.Int {
+		res := []*big.Int{h}
+		for i := 1; i < k; i++ {
+			res = append(res, mul(res[i-1], w))
+		}
+		return res
+	}
+	s0, s1 := roots(h0, w8, 8), roots(h1, w4, 4)
+	s2 := append(roots(h2, w3, 3), roots(h3, w3, 3)...)
+	xiw := mul(xi, w)
+
+	evals := []*big.Int{
+		pQl.eval(xi), pQr.eval(xi), pQm.eval(xi), pQo.eval(xi),
+		pQc.eval(xi), pS1.eval(xi), pS2.eval(xi), pS3.eval(xi),
+		pA.eval(xi), pB.eval(xi), pC.eval(xi),
+		pZ.eval(xi), pZ.eval(xiw), pT1.eval(xiw), pT2.eval(xiw),
+	}
+	tr.addScalar(xiSeed)
+	for _, e := range evals {
+		tr.addScalar(e)
+	}
+	alpha := tr.challenge()
+
+	// round 4: W1 opens C0, C1 and C2 at their roots
+	remainder := func(p poly, points []*big.Int) poly {
+		values := make([]*big.Int, len(points))
+		for i := range points {
+			values[i] = p.eval(points[i])
+		}
+		return interpolate(points, values)
+	}
+	r0, r1, r2 := remainder(pC0, s0), remainder(pC1, s1), remainder(pC2, s2)
+	quotient := func(p, r poly, points []*big.Int) poly {
+		return pdiv(padd(p, pscale(r, fe(-1))), vanishing(points))
+	}
+	pW1 := padd(padd(quotient(pC0, r0, s0),
+		pscale(quotient(pC1, r1, s1), alpha)),
+		pscale(quotient(pC2, r2, s2), mul(alpha, alpha)))
+	w1 := commit(pW1, tau)
+	tr.addScalar(alpha)
+	tr.addPoint(w1)
+	y := tr.challenge()
+
+	// round 5: W2 opens the linearization of W1 at y
+	zt0, zt1, zt2 := vanishing(s0).eval(y), vanishing(s1).eval(y),
+		vanishing(s2).eval(y)
+	q1 := mul(alpha, mul(zt0, inv(zt1)))
+	q2 := mul(mul(alpha, alpha), mul(zt0, inv(zt2)))
+	l := padd(pC0, pconst(sub(fe(0), r0.eval(y))))
+	l = padd(l, pscale(padd(pC1, pconst(sub(fe(0), r1.eval(y)))), q1))
+	l = padd(l, pscale(padd(pC2, pconst(sub(fe(0), r2.eval(y)))), q2))
+	l = padd(l, pscale(pW1, sub(fe(0), zt0)))
+	w2 := commit(divLinear(l, y), tau)
+
+	vk := fflonkVerificationKey{
+		Protocol: "fflonk",
+		Curve:    "bn128",
+		NPublic:  len(cs.public),
+		Power:    power,
+		K1:       k[1].String(),
+		K2:       k[2].String(),
+		W:        w.String(),
+		W3:       w3.String(),
+		W4:       w4.String(),
+		W8:       w8.String(),
+		Wr:       wr.String(),
+		X2:       g2Strings(tau),
+		C0:       g1Strings(c0),
+	}
+	// the inverse the verifier checks, 1 / (ZH(xi)·ZT1(y)·ZT2(y))
+	zh := sub(exp(xi, big.NewInt(int64(n))), fe(1))
+	proof := fflonkProof{
+		Polynomials: fflonkPolynomials{
+			C1: g1Strings(c1),
+			C2: g1Strings(c2),
+			W1: g1Strings(w1),
+			W2: g1Strings(w2),
+		},
+		Evaluations: fflonkEvaluations{
+			Ql:  evals[0].String(),
+			Qr:  evals[1].String(),
+			Qm:  evals[2].String(),
+			Qo:  evals[3].String(),
+			Qc:  evals[4].String(),
+			S1:  evals[5].String(),
+			S2:  evals[6].String(),
+			S3:  evals[7].String(),
+			A:   evals[8].String(),
+			B:   evals[9].String(),
+			C:   evals[10].String(),
+			Z:   evals[11].String(),
+			Zw:  evals[12].String(),
+			T1w: evals[13].String(),
+			T2w: evals[14].String(),
+			Inv: inv(mul(mul(zh, zt1), zt2)).String(),
+		},
+		Protocol: "fflonk",
+		Curve:    "bn128",
+	}
+	public := []string{cs.public[0].String(), cs.public[1].String()}
+	return vk, proof, public
+}
+
+// combine returns Σ p_j(Xᵏ)·Xʲ.
+func combine(k int, ps ...poly) poly {
+	res := poly{fe(0)}
+	for j, p := range ps {
+		composed := make(poly, j+(len(p)-1)*k+1)
+		for i := range composed {
+			composed[i] = fe(0)
+		}
+		for i := range p {
+			composed[j+i*k] = p[i]
+		}
+		res = padd(res, composed)
+	}
+	return res
+}
+
+// vanishing returns Π (X - p) over the points.
+func vanishing(points []*big.Int) poly {
+	z := poly{fe(1)}
+	for _, p := range points {
+		z = pmul(z, poly{sub(fe(0), p), fe(1)})
+	}
+	return z
+}
+
+// pdiv returns p / d. The remainder must be zero.
+func pdiv(p, d poly) poly {
+	p = append(poly{}, p...)
+	for len(d) > 0 && d[len(d)-1].Sign() == 0 {
+		d = d[:len(d)-1]
+	}
+	if len(p) < len(d) {
+		for _, c := range p {
+			if c.Sign() != 0 {
+				log.Fatal("polynomial division has a remainder")
+			}
+		}
+		return poly{fe(0)}
+	}
+	q := make(poly, len(p)-len(d)+1)
+	lead := inv(d[len(d)-1])
+	for i := len(q) - 1; i >= 0; i-- {
+		c := mul(p[i+len(d)-1], lead)
+		q[i] = c
+		for j := range d {
+			p[i+j] = sub(p[i+j], mul(c, d[j]))
+		}
+	}
+	for _, c := range p {
+		if c.Sign() != 0 {
+			log.Fatal("polynomial division has a remainder")
+		}
+	}
+	return q
+}
@@ -0,0 +1,16 @@
+module github.com/iden3/go-rapidsnark/verifier/testdata/plonkgen
+
+go 1.22
+
+require (
+	github.com/consensys/gnark-crypto v0.14.0
+	golang.org/x/crypto v0.26.0
+)
+
+require (
+	github.com/bits-and-blooms/bitset v1.14.2 // indirect
+	github.com/consensys/bavard v0.1.13 // indirect
+	github.com/mmcloughlin/addchain v0.4.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	rsc.io/tmplfunc v0.0.3 // indirect
+)
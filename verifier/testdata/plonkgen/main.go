@@ -0,0 +1,366 @@
+// Command plonkgen writes the PLONK and fflonk test vectors of the verifier
+// package: plonk_verification_key.json, plonk_proof.json, plonk_public.json
+// and the same files for fflonk.
+//
+// It is a small prover for the circuit of muladd.circom, described in
+// ../README.md, with setups derived from fixed taus, so the output is
+// reproducible. It follows the snarkjs provers: the same transcripts, the
+// same k1 and k2 and the same roots of unity. The polynomials are kept in
+// coefficient form, which is slow but fine for a domain of 8 rows, and the
+// curve arithmetic is done with gnark-crypto, not with the packages under
+// test.
+//
+// Usage, from this directory:
+//
+//	go run . ..
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"golang.org/x/crypto/sha3"
+)
+
+var r, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: plonkgen <output dir>")
+	}
+	out := os.Args[1]
+
+	vk, proof, public := provePlonk()
+	writeJSON(out, "plonk_verification_key.json", vk)
+	writeJSON(out, "plonk_proof.json", proof)
+	writeJSON(out, "plonk_public.json", public)
+
+	fvk, fproof, fpublic := proveFflonk()
+	writeJSON(out, "fflonk_verification_key.json", fvk)
+	writeJSON(out, "fflonk_proof.json", fproof)
+	writeJSON(out, "fflonk_public.json", fpublic)
+}
+
+func writeJSON(dir, name string, v interface{}) {
+	data, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+	if err = os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// circuit is the PLONK arithmetization of
+//
+//	mul = x·y
+//	sum = x+y
+//
+// with mul and sum public. Row 0 and 1 hold the public inputs, row 2 and 3
+// the gates and row 4 a constant gate, so no selector is the zero
+// polynomial.
+type circuit struct {
+	n      int
+	domain []*big.Int
+	public []*big.Int
+	// the wires, the selectors and the permutation, evaluated on the domain
+	a, b, c            []*big.Int
+	qm, ql, qr, qo, qc []*big.Int
+	sigma              [3][]*big.Int
+}
+
+// k are the coset generators of the three wire columns, 1, k1 and k2.
+var k = []*big.Int{fe(1), fe(2), fe(3)}
+
+func newCircuit(power int, x, y *big.Int, constant int64) circuit {
+	n := 1 << power
+	cs := circuit{
+		n:      n,
+		domain: make([]*big.Int, n),
+		public: []*big.Int{mul(x, y), add(x, y)},
+	}
+	w := rootOfUnity(power)
+	cs.domain[0] = fe(1)
+	for i := 1; i < n; i++ {
+		cs.domain[i] = mul(cs.domain[i-1], w)
+	}
+
+	zeros := func() []*big.Int {
+		v := make([]*big.Int, n)
+		for i := range v {
+			v[i] = fe(0)
+		}
+		return v
+	}
+	cs.a, cs.b, cs.c = zeros(), zeros(), zeros()
+	cs.qm, cs.ql, cs.qr, cs.qo, cs.qc = zeros(), zeros(), zeros(), zeros(),
+		zeros()
+	cs.a[0], cs.ql[0] = cs.public[0], fe(1)
+	cs.a[1], cs.ql[1] = cs.public[1], fe(1)
+	cs.a[2], cs.b[2], cs.c[2] = x, y, cs.public[0]
+	cs.qm[2], cs.qo[2] = fe(1), fe(-1)
+	cs.a[3], cs.b[3], cs.c[3] = x, y, cs.public[1]
+	cs.ql[3], cs.qr[3], cs.qo[3] = fe(1), fe(1), fe(-1)
+	cs.c[4], cs.qo[4], cs.qc[4] = fe(constant), fe(1), fe(-constant)
+
+	// the copy constraints, as cycles of the positions column·n + row
+	cycles := [][]int{
+		{0*n + 0, 2*n + 2}, // mul
+		{0*n + 1, 2*n + 3}, // sum
+		{0*n + 2, 0*n + 3}, // x
+		{1*n + 2, 1*n + 3}, // y
+	}
+	perm := make([]int, 3*n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for _, c := range cycles {
+		for i := range c {
+			perm[c[i]] = c[(i+1)%len(c)]
+		}
+	}
+	for col := range cs.sigma {
+		cs.sigma[col] = make([]*big.Int, n)
+		for row := range cs.sigma[col] {
+			pos := perm[col*n+row]
+			cs.sigma[col][row] = mul(k[pos/n], cs.domain[pos%n])
+		}
+	}
+	return cs
+}
+
+// grandProduct returns the evaluations of the permutation polynomial z on
+// the domain.
+func (cs circuit) grandProduct(beta, gamma *big.Int) []*big.Int {
+	z := make([]*big.Int, cs.n)
+	z[0] = fe(1)
+	for i := 0; i < cs.n-1; i++ {
+		num := fe(1)
+		den := fe(1)
+		for col, wire := range [][]*big.Int{cs.a, cs.b, cs.c} {
+			id := mul(k[col], cs.domain[i])
+			num = mul(num, add(add(wire[i], mul(beta, id)), gamma))
+			den = mul(den, add(add(wire[i], mul(beta, cs.sigma[col][i])),
+				gamma))
+		}
+		z[i+1] = mul(z[i], mul(num, inv(den)))
+	}
+	return z
+}
+
+// publicPoly returns the public input polynomial, -Σ public_i·L_i.
+func (cs circuit) publicPoly() poly {
+	v := make([]*big.Int, cs.n)
+	for i := range v {
+		v[i] = fe(0)
+	}
+	for i, p := range cs.public {
+		v[i] = sub(fe(0), p)
+	}
+	return interpolate(cs.domain, v)
+}
+
+// lagrange1 returns L_1, the Lagrange basis of the first row.
+func (cs circuit) lagrange1() poly {
+	v := make([]*big.Int, cs.n)
+	for i := range v {
+		v[i] = fe(0)
+	}
+	v[0] = fe(1)
+	return interpolate(cs.domain, v)
+}
+
+// rootOfUnity returns the root of unity of order 2^power of ffjavascript,
+// derived from 5^((r-1)/2^28).
+func rootOfUnity(power int) *big.Int {
+	t := new(big.Int).Rsh(sub(fe(0), fe(1)), 28)
+	w := exp(fe(5), t)
+	return exp(w, new(big.Int).Lsh(big.NewInt(1), uint(28-power)))
+}
+
+func fe(x int64) *big.Int {
+	return mod(big.NewInt(x))
+}
+
+func mod(x *big.Int) *big.Int {
+	return x.Mod(x, r)
+}
+
+func add(a, b *big.Int) *big.Int {
+	return mod(new(big.Int).Add(a, b))
+}
+
+func sub(a, b *big.Int) *big.Int {
+	return mod(new(big.Int).Sub(a, b))
+}
+
+func mul(a, b *big.Int) *big.Int {
+	return mod(new(big.Int).Mul(a, b))
+}
+
+func inv(a *big.Int) *big.Int {
+	return new(big.Int).ModInverse(a, r)
+}
+
+func exp(a, e *big.Int) *big.Int {
+	return new(big.Int).Exp(a, e, r)
+}
+
+// poly is a polynomial by its coefficients, lowest degree first.
+type poly []*big.Int
+
+func (p poly) eval(x *big.Int) *big.Int {
+	res := fe(0)
+	for i := len(p) - 1; i >= 0; i-- {
+		res = add(mul(res, x), p[i])
+	}
+	return res
+}
+
+func padd(a, b poly) poly {
+	if len(b) > len(a) {
+		a, b = b, a
+	}
+	res := make(poly, len(a))
+	for i := range res {
+		res[i] = fe(0).Set(a[i])
+		if i < len(b) {
+			res[i] = add(res[i], b[i])
+		}
+	}
+	return res
+}
+
+func pscale(a poly, s *big.Int) poly {
+	res := make(poly, len(a))
+	for i := range a {
+		res[i] = mul(a[i], s)
+	}
+	return res
+}
+
+func pmul(a, b poly) poly {
+	res := make(poly, len(a)+len(b)-1)
+	for i := range res {
+		res[i] = fe(0)
+	}
+	for i := range a {
+		for j := range b {
+			res[i+j] = add(res[i+j], mul(a[i], b[j]))
+		}
+	}
+	return res
+}
+
+func pconst(c *big.Int) poly {
+	return poly{c}
+}
+
+// divLinear returns p / (X - z). The remainder must be zero.
+func divLinear(p poly, z *big.Int) poly {
+	q := make(poly, len(p)-1)
+	carry := fe(0)
+	for i := len(p) - 1; i >= 1; i-- {
+		carry = add(p[i], mul(carry, z))
+		q[i-1] = carry
+	}
+	if add(p[0], mul(carry, z)).Sign() != 0 {
+		log.Fatal("division by X - z has a remainder")
+	}
+	return q
+}
+
+// divZH returns p / (Xⁿ - 1). The remainder must be zero.
+func divZH(p poly, n int) poly {
+	p = append(poly{}, p...)
+	q := make(poly, len(p))
+	for i := range q {
+		q[i] = fe(0)
+	}
+	for i := len(p) - 1; i >= n; i-- {
+		c := p[i]
+		q[i-n] = add(q[i-n], c)
+		p[i] = fe(0)
+		p[i-n] = add(p[i-n], c)
+	}
+	for _, c := range p[:n] {
+		if c.Sign() != 0 {
+			log.Fatal("division by Xⁿ - 1 has a remainder")
+		}
+	}
+	return q
+}
+
+// interpolate returns the polynomial of degree < len(points) with the
+// given values at the points.
+func interpolate(points, values []*big.Int) poly {
+	res := poly{fe(0)}
+	for i := range points {
+		basis := poly{fe(1)}
+		den := fe(1)
+		for j := range points {
+			if j == i {
+				continue
+			}
+			basis = pmul(basis, poly{sub(fe(0), points[j]), fe(1)})
+			den = mul(den, sub(points[i], points[j]))
+		}
+		res = padd(res, pscale(basis, mul(values[i], inv(den))))
+	}
+	return res
+}
+
+// commit returns [p(τ)]₁.
+func commit(p poly, tau *big.Int) bn254.G1Affine {
+	var c bn254.G1Affine
+	c.ScalarMultiplicationBase(p.eval(tau))
+	return c
+}
+
+func fpString(x fp.Element) string {
+	return x.BigInt(new(big.Int)).String()
+}
+
+func g1Strings(p bn254.G1Affine) []string {
+	return []string{fpString(p.X), fpString(p.Y), "1"}
+}
+
+// g2Strings returns [τ]₂ in the snarkjs format.
+func g2Strings(tau *big.Int) [][]string {
+	var p bn254.G2Affine
+	p.ScalarMultiplicationBase(tau)
+	return [][]string{
+		{fpString(p.X.A0), fpString(p.X.A1)},
+		{fpString(p.Y.A0), fpString(p.Y.A1)},
+		{"1", "0"},
+	}
+}
+
+// transcript is the Fiat-Shamir transcript of snarkjs: the keccak256 hash
+// of the points, as x and y in 32 bytes big-endian, and the scalars in 32
+// bytes big-endian added since the last challenge.
+type transcript struct {
+	data []byte
+}
+
+func (t *transcript) addPoint(p bn254.G1Affine) {
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	t.data = append(append(t.data, x[:]...), y[:]...)
+}
+
+func (t *transcript) addScalar(s *big.Int) {
+	t.data = append(t.data, s.FillBytes(make([]byte, 32))...)
+}
+
+func (t *transcript) challenge() *big.Int {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(t.data)
+	t.data = nil
+	return mod(new(big.Int).SetBytes(h.Sum(nil)))
+}
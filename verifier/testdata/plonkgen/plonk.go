@@ -0,0 +1,221 @@
+package main
+
+import (
+	"log"
+	"math/big"
+)
+
+type plonkVerificationKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	Qm       []string   `json:"Qm"`
+	Ql       []string   `json:"Ql"`
+	Qr       []string   `json:"Qr"`
+	Qo       []string   `json:"Qo"`
+	Qc       []string   `json:"Qc"`
+	S1       []string   `json:"S1"`
+	S2       []string   `json:"S2"`
+	S3       []string   `json:"S3"`
+	X2       [][]string `json:"X_2"`
+	W        string     `json:"w"`
+}
+
+type plonkProof struct {
+	A        []string `json:"A"`
+	B        []string `json:"B"`
+	C        []string `json:"C"`
+	Z        []string `json:"Z"`
+	T1       []string `json:"T1"`
+	T2       []string `json:"T2"`
+	T3       []string `json:"T3"`
+	Wxi      []string `json:"Wxi"`
+	Wxiw     []string `json:"Wxiw"`
+	EvalA    string   `json:"eval_a"`
+	EvalB    string   `json:"eval_b"`
+	EvalC    string   `json:"eval_c"`
+	EvalS1   string   `json:"eval_s1"`
+	EvalS2   string   `json:"eval_s2"`
+	EvalZw   string   `json:"eval_zw"`
+	Protocol string   `json:"protocol"`
+	Curve    string   `json:"curve"`
+}
+
+// provePlonk proves the circuit for x = 11 and y = 36, following
+// snarkjs/src/plonk_prove.js without blinding.
+func provePlonk() (plonkVerificationKey, plonkProof, []string) {
+	const power = 3
+	tau, _ := new(big.Int).SetString("4142135623730950488016887242096980785696718753769480731766797379", 10)
+	cs := newCircuit(power, fe(11), fe(36), 7)
+	n := cs.n
+	w := rootOfUnity(power)
+
+	pA := interpolate(cs.domain, cs.a)
+	pB := interpolate(cs.domain, cs.b)
+	pC := interpolate(cs.domain, cs.c)
+	pQm := interpolate(cs.domain, cs.qm)
+	pQl := interpolate(cs.domain, cs.ql)
+	pQr := interpolate(cs.domain, cs.qr)
+	pQo := interpolate(cs.domain, cs.qo)
+	pQc := interpolate(cs.domain, cs.qc)
+	pS1 := interpolate(cs.domain, cs.sigma[0])
+	pS2 := interpolate(cs.domain, cs.sigma[1])
+	pS3 := interpolate(cs.domain, cs.sigma[2])
+	pPI := cs.publicPoly()
+	pL1 := cs.lagrange1()
+
+	vk := plonkVerificationKey{
+		Protocol: "plonk",
+		Curve:    "bn128",
+		NPublic:  len(cs.public),
+		Power:    power,
+		K1:       k[1].String(),
+		K2:       k[2].String(),
+		Qm:       g1Strings(commit(pQm, tau)),
+		Ql:       g1Strings(commit(pQl, tau)),
+		Qr:       g1Strings(commit(pQr, tau)),
+		Qo:       g1Strings(commit(pQo, tau)),
+		Qc:       g1Strings(commit(pQc, tau)),
+		S1:       g1Strings(commit(pS1, tau)),
+		S2:       g1Strings(commit(pS2, tau)),
+		S3:       g1Strings(commit(pS3, tau)),
+		X2:       g2Strings(tau),
+		W:        w.String(),
+	}
+
+	// round 1: the wires
+	cA, cB, cC := commit(pA, tau), commit(pB, tau), commit(pC, tau)
+	var tr transcript
+	for _, p := range []poly{pQm, pQl, pQr, pQo, pQc, pS1, pS2, pS3} {
+		tr.addPoint(commit(p, tau))
+	}
+	for _, p := range cs.public {
+		tr.addScalar(p)
+	}
+	tr.addPoint(cA)
+	tr.addPoint(cB)
+	tr.addPoint(cC)
+	beta := tr.challenge()
+	tr.addScalar(beta)
+	gamma := tr.challenge()
+
+	// round 2: the permutation
+	pZ := interpolate(cs.domain, cs.grandProduct(beta, gamma))
+	cZ := commit(pZ, tau)
+	tr.addScalar(beta)
+	tr.addScalar(gamma)
+	tr.addPoint(cZ)
+	alpha := tr.challenge()
+
+	// round 3: the quotient
+	pZw := make(poly, len(pZ))
+	wi := fe(1)
+	for i := range pZ {
+		pZw[i] = mul(pZ[i], wi)
+		wi = mul(wi, w)
+	}
+	x := poly{fe(0), fe(1)}
+	plusGamma := func(p, q poly) poly {
+		return padd(padd(p, q), pconst(gamma))
+	}
+	gate := padd(padd(padd(padd(padd(pmul(pmul(pQm, pA), pB),
+		pmul(pQl, pA)), pmul(pQr, pB)), pmul(pQo, pC)), pQc), pPI)
+	perm1 := pmul(pmul(pmul(
+		plusGamma(pA, pscale(x, beta)),
+		plusGamma(pB, pscale(x, mul(beta, k[1])))),
+		plusGamma(pC, pscale(x, mul(beta, k[2])))), pZ)
+	perm2 := pmul(pmul(pmul(
+		plusGamma(pA, pscale(pS1, beta)),
+		plusGamma(pB, pscale(pS2, beta))),
+		plusGamma(pC, pscale(pS3, beta))), pZw)
+	perm := pscale(padd(perm1, pscale(perm2, fe(-1))), alpha)
+	first := pscale(pmul(padd(pZ, pconst(fe(-1))), pL1), mul(alpha, alpha))
+	pT := divZH(padd(padd(gate, perm), first), n)
+	for len(pT) < 3*n {
+		pT = append(pT, fe(0))
+	}
+	for _, c := range pT[3*n:] {
+		if c.Sign() != 0 {
+			log.Fatal("the degree of t is too high")
+		}
+	}
+	pT1, pT2, pT3 := pT[:n], pT[n:2*n], pT[2*n:3*n]
+	cT1, cT2, cT3 := commit(pT1, tau), commit(pT2, tau), commit(pT3, tau)
+	tr.addScalar(alpha)
+	tr.addPoint(cT1)
+	tr.addPoint(cT2)
+	tr.addPoint(cT3)
+	xi := tr.challenge()
+
+	// round 4: the evaluations
+	evalA, evalB, evalC := pA.eval(xi), pB.eval(xi), pC.eval(xi)
+	evalS1, evalS2 := pS1.eval(xi), pS2.eval(xi)
+	evalZw := pZ.eval(mul(xi, w))
+	tr.addScalar(xi)
+	for _, e := range []*big.Int{evalA, evalB, evalC, evalS1, evalS2, evalZw} {
+		tr.addScalar(e)
+	}
+	v := []*big.Int{nil, tr.challenge()}
+	for i := 2; i <= 5; i++ {
+		v = append(v, mul(v[i-1], v[1]))
+	}
+
+	// round 5: the linearization polynomial and the openings
+	xin := exp(xi, big.NewInt(int64(n)))
+	zh := sub(xin, fe(1))
+	l1 := pL1.eval(xi)
+	alpha2 := mul(alpha, alpha)
+	permA := add(add(evalA, mul(beta, evalS1)), gamma)
+	permB := add(add(evalB, mul(beta, evalS2)), gamma)
+	r0 := sub(sub(pPI.eval(xi), mul(l1, alpha2)),
+		mul(mul(mul(mul(permA, permB), add(evalC, gamma)), evalZw), alpha))
+	betaXi := mul(beta, xi)
+	zCoef := mul(mul(mul(
+		add(add(evalA, betaXi), gamma),
+		add(add(evalB, mul(betaXi, k[1])), gamma)),
+		add(add(evalC, mul(betaXi, k[2])), gamma)), alpha)
+	zCoef = add(zCoef, mul(l1, alpha2))
+	s3Coef := mul(mul(mul(mul(permA, permB), alpha), beta), evalZw)
+	pR := padd(padd(padd(padd(padd(
+		pscale(pQm, mul(evalA, evalB)), pscale(pQl, evalA)),
+		pscale(pQr, evalB)), pscale(pQo, evalC)), pQc), pscale(pZ, zCoef))
+	pR = padd(pR, pscale(pS3, sub(fe(0), s3Coef)))
+	pR = padd(pR, pscale(padd(padd(pT1, pscale(pT2, xin)),
+		pscale(pT3, mul(xin, xin))), sub(fe(0), zh)))
+	pR = padd(pR, pconst(r0))
+	if pR.eval(xi).Sign() != 0 {
+		log.Fatal("the linearization polynomial does not vanish at xi")
+	}
+	wxi := pR
+	for i, p := range []poly{pA, pB, pC, pS1, pS2} {
+		wxi = padd(wxi, pscale(padd(p, pconst(sub(fe(0), p.eval(xi)))),
+			v[i+1]))
+	}
+	pWxi := divLinear(wxi, xi)
+	pWxiw := divLinear(padd(pZ, pconst(sub(fe(0), evalZw))), mul(xi, w))
+
+	proof := plonkProof{
+		A:        g1Strings(cA),
+		B:        g1Strings(cB),
+		C:        g1Strings(cC),
+		Z:        g1Strings(cZ),
+		T1:       g1Strings(cT1),
+		T2:       g1Strings(cT2),
+		T3:       g1Strings(cT3),
+		Wxi:      g1Strings(commit(pWxi, tau)),
+		Wxiw:     g1Strings(commit(pWxiw, tau)),
+		EvalA:    evalA.String(),
+		EvalB:    evalB.String(),
+		EvalC:    evalC.String(),
+		EvalS1:   evalS1.String(),
+		EvalS2:   evalS2.String(),
+		EvalZw:   evalZw.String(),
+		Protocol: "plonk",
+		Curve:    "bn128",
+	}
+	public := []string{cs.public[0].String(), cs.public[1].String()}
+	return vk, proof, public
+}
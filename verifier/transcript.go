@@ -0,0 +1,52 @@
+package verifier
+
+import (
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccakTranscript derives the challenges of the PLONK and fflonk
+// verifiers like the Keccak256Transcript of snarkjs: a challenge is the
+// Keccak-256 hash of the data added since the previous one, as a big-endian
+// number reduced modulo the scalar field.
+type keccakTranscript struct {
+	data []byte
+}
+
+// addG1 adds a commitment as its uncompressed big-endian affine
+// coordinates.
+func (t *keccakTranscript) addG1(p *bn256.G1) {
+	b := p.Marshal()
+	if isZero(b) {
+		// snarkjs flags the point at infinity
+		b[0] |= 0x40
+	}
+	t.data = append(t.data, b...)
+}
+
+// addScalar adds an element of the scalar field as 32 big-endian bytes.
+func (t *keccakTranscript) addScalar(s *big.Int) {
+	t.data = append(t.data, s.FillBytes(make([]byte, 32))...)
+}
+
+// challenge returns the challenge of the data added to the transcript and
+// resets it.
+func (t *keccakTranscript) challenge() *big.Int {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(t.data)
+	t.data = t.data[:0]
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, constants.Q)
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
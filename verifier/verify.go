@@ -0,0 +1,57 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/iden3/go-rapidsnark/types"
+)
+
+// Verify verifies a snarkjs proof, the content of the proof.json file of
+// snarkjs, against the public signals and the verification key. The
+// protocol field of the verification key selects the verifier: groth16,
+// plonk or fflonk. Keys without protocol are taken as groth16.
+func Verify(proof []byte, pubSignals []string, verificationKey []byte) error {
+	var vkProtocol, proofProtocol struct {
+		Protocol string `json:"protocol"`
+	}
+	if err := json.Unmarshal(verificationKey, &vkProtocol); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(proof, &proofProtocol); err != nil {
+		return err
+	}
+
+	protocol := vkProtocol.Protocol
+	if protocol == "" {
+		protocol = "groth16"
+	}
+	if proofProtocol.Protocol != "" && proofProtocol.Protocol != protocol {
+		return fmt.Errorf(
+			"proof protocol %v does not match verification key protocol %v",
+			proofProtocol.Protocol, protocol)
+	}
+
+	switch protocol {
+	case "groth16":
+		zkProof := types.ZKProof{PubSignals: pubSignals}
+		if err := json.Unmarshal(proof, &zkProof.Proof); err != nil {
+			return err
+		}
+		return VerifyGroth16(zkProof, verificationKey)
+	case "plonk":
+		zkProof := types.PlonkZKProof{PubSignals: pubSignals}
+		if err := json.Unmarshal(proof, &zkProof.Proof); err != nil {
+			return err
+		}
+		return VerifyPlonk(zkProof, verificationKey)
+	case "fflonk":
+		zkProof := types.FflonkZKProof{PubSignals: pubSignals}
+		if err := json.Unmarshal(proof, &zkProof.Proof); err != nil {
+			return err
+		}
+		return VerifyFflonk(zkProof, verificationKey)
+	default:
+		return fmt.Errorf("unsupported protocol: %v", protocol)
+	}
+}
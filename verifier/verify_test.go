@@ -0,0 +1,81 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	read := func(name string) []byte {
+		b, err := os.ReadFile("testdata/" + name)
+		require.NoError(t, err)
+		return b
+	}
+	readPublic := func(name string) []string {
+		var pub []string
+		require.NoError(t, json.Unmarshal(read(name), &pub))
+		return pub
+	}
+
+	testCases := []struct {
+		protocol string
+		proof    []byte
+		public   []string
+		vk       []byte
+	}{
+		{"groth16", read("proof.json"), readPublic("public.json"),
+			read("verification_key.json")},
+		{"plonk", read("plonk_proof.json"), readPublic("plonk_public.json"),
+			read("plonk_verification_key.json")},
+		{"fflonk", read("fflonk_proof.json"), readPublic("fflonk_public.json"),
+			read("fflonk_verification_key.json")},
+		{"groth16 bls12381", read("bls12381_proof.json"),
+			readPublic("bls12381_public.json"),
+			read("bls12381_verification_key.json")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			require.NoError(t, Verify(tc.proof, tc.public, tc.vk))
+
+			wrongPublic := append([]string{"1"}, tc.public[1:]...)
+			require.EqualError(t, Verify(tc.proof, wrongPublic, tc.vk),
+				"invalid proofs")
+		})
+	}
+
+	t.Run("protocol mismatch", func(t *testing.T) {
+		err := Verify(read("plonk_proof.json"), readPublic("public.json"),
+			read("verification_key.json"))
+		require.EqualError(t, err, "proof protocol plonk does not match "+
+			"verification key protocol groth16")
+	})
+
+	t.Run("groth16 key without protocol", func(t *testing.T) {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(read("verification_key.json"), &m))
+		delete(m, "protocol")
+		vk, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, Verify(read("proof.json"),
+			readPublic("public.json"), vk))
+	})
+
+	t.Run("unsupported protocol", func(t *testing.T) {
+		err := Verify(read("proof.json"), readPublic("public.json"),
+			modifyVK(t, read("verification_key.json"), "protocol", "marlin"))
+		require.EqualError(t, err, "proof protocol groth16 does not match "+
+			"verification key protocol marlin")
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(read("proof.json"), &m))
+		delete(m, "protocol")
+		proof, err := json.Marshal(m)
+		require.NoError(t, err)
+		err = Verify(proof, readPublic("public.json"),
+			modifyVK(t, read("verification_key.json"), "protocol", "marlin"))
+		require.EqualError(t, err, "unsupported protocol: marlin")
+	})
+}
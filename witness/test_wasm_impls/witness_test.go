@@ -1,11 +1,14 @@
 package witness
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"io"
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/iden3/go-rapidsnark/witness/v2"
 	"github.com/iden3/go-rapidsnark/witness/wasmer"
@@ -106,6 +109,11 @@ func TestEngines(t *testing.T) {
 						require.NotEmpty(t, wtns)
 						require.Equal(t, circomTC.wantWTNSBinHex,
 							hashBytes(wtns))
+
+						parsed, err2 := witness.ParseWTNS(wtns)
+						require.NoError(t, err2)
+						require.Equal(t, circomTC.wantWtnsHex,
+							hashInts(parsed.Witness))
 					})
 				})
 			}
@@ -132,3 +140,130 @@ func hashBytes(in []byte) string {
 	}
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+func TestCalculateContext(t *testing.T) {
+	wasmBytes, err := os.ReadFile("testdata/circom2_1_0/circuit.wasm")
+	require.NoError(t, err)
+	inputBytes, err := os.ReadFile("testdata/circom2_1_0/input.json")
+	require.NoError(t, err)
+	inputs, err := witness.ParseInputs(inputBytes)
+	require.NoError(t, err)
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("Wazero engine", func(t *testing.T) {
+		wc, err := wazero.NewCircom2WZWitnessCalculator(wasmBytes)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, wc.(io.Closer).Close())
+		}()
+
+		wcCtx, ok := wc.(witness.CalculatorImplContext)
+		require.True(t, ok)
+
+		_, err = wcCtx.CalculateContext(canceledCtx, inputs, true)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// the engine is still usable after a canceled calculation
+		wtns, err := wcCtx.CalculateContext(context.Background(), inputs,
+			true)
+		require.NoError(t, err)
+		require.Equal(t, "c0a2b43f5a333310c2bb8d357db46d3b",
+			hashInts(wtns.Witness))
+	})
+
+	engines := []struct {
+		title  string
+		engine func(code []byte) (witness.CalculatorImpl, error)
+	}{
+		{title: "Wazero", engine: wazero.NewCircom2WZWitnessCalculator},
+		{title: "Wasmer", engine: wasmer.NewCircom2WitnessCalculator},
+	}
+	for _, tc := range engines {
+		t.Run(tc.title, func(t *testing.T) {
+			calc, err := witness.NewCalculator(wasmBytes,
+				witness.WithWasmEngine(tc.engine))
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, calc.Close())
+			}()
+
+			calcCtx, ok := calc.(witness.CalculatorContext)
+			require.True(t, ok)
+
+			_, err = calcCtx.CalculateWitnessContext(canceledCtx, inputs,
+				true)
+			require.ErrorIs(t, err, context.Canceled)
+			_, err = calcCtx.CalculateBinWitnessContext(canceledCtx, inputs,
+				true)
+			require.ErrorIs(t, err, context.Canceled)
+			_, err = calcCtx.CalculateWTNSBinContext(canceledCtx, inputs, true)
+			require.ErrorIs(t, err, context.Canceled)
+
+			ctx, cancel := context.WithTimeout(context.Background(),
+				time.Minute)
+			defer cancel()
+			wtns, err := calcCtx.CalculateWitnessContext(ctx, inputs, true)
+			require.NoError(t, err)
+			require.Equal(t, "c0a2b43f5a333310c2bb8d357db46d3b",
+				hashInts(wtns))
+		})
+	}
+}
+
+// endlessCircuit is a wasm module with the exports of a circom witness
+// calculator that the engines call first, and an init that never returns:
+//
+//	(module
+//	  (func (export "getFieldNumLen32") (result i32) i32.const 8)
+//	  (func (export "getInputSize") (result i32) i32.const 0)
+//	  (func (export "getWitnessSize") (result i32) i32.const 1)
+//	  (func (export "init") (param i32) (loop br 0)))
+var endlessCircuit = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// types: () -> i32, (i32) -> ()
+	0x01, 0x09, 0x02, 0x60, 0x00, 0x01, 0x7f, 0x60, 0x01, 0x7f, 0x00,
+	// functions
+	0x03, 0x05, 0x04, 0x00, 0x00, 0x00, 0x01,
+	// exports
+	0x07, 0x3b, 0x04,
+	0x10, 'g', 'e', 't', 'F', 'i', 'e', 'l', 'd', 'N', 'u', 'm', 'L', 'e',
+	'n', '3', '2', 0x00, 0x00,
+	0x0c, 'g', 'e', 't', 'I', 'n', 'p', 'u', 't', 'S', 'i', 'z', 'e',
+	0x00, 0x01,
+	0x0e, 'g', 'e', 't', 'W', 'i', 't', 'n', 'e', 's', 's', 'S', 'i', 'z',
+	'e', 0x00, 0x02,
+	0x04, 'i', 'n', 'i', 't', 0x00, 0x03,
+	// code
+	0x0a, 0x18, 0x04,
+	0x04, 0x00, 0x41, 0x08, 0x0b,
+	0x04, 0x00, 0x41, 0x00, 0x0b,
+	0x04, 0x00, 0x41, 0x01, 0x0b,
+	0x07, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b,
+}
+
+func TestCalculateContextCancelRunning(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	calc, err := witness.NewCalculator(endlessCircuit,
+		witness.WithWasmEngine(wazero.NewCircom2WZWitnessCalculator))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, calc.Close())
+	}()
+	calcCtx, ok := calc.(witness.CalculatorContext)
+	require.True(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(delay, cancel)
+
+	start := time.Now()
+	_, err = calcCtx.CalculateWitnessContext(ctx, map[string]interface{}{},
+		false)
+	require.ErrorIs(t, err, context.Canceled)
+	// canceled while init was running, not by the check before it
+	require.GreaterOrEqual(t, time.Since(start), delay)
+	require.Less(t, time.Since(start), 10*time.Second)
+}
@@ -26,9 +26,13 @@ type Circom2WZWitnessCalculator struct {
 func NewCircom2WZWitnessCalculator(
 	wasmBytes []byte) (witness.CalculatorImpl, error) {
 
-	runtime := wz.NewRuntime(context.Background())
-
 	ctx := context.Background()
+
+	// close module instances when the context passed to the calculation is
+	// done, so long-running calculations can be canceled
+	runtime := wz.NewRuntimeWithConfig(ctx,
+		wz.NewRuntimeConfig().WithCloseOnContextDone(true))
+
 	modRuntime, err := runtime.NewHostModuleBuilder("runtime").
 		NewFunctionBuilder().
 		WithGoFunction(
@@ -492,8 +496,23 @@ func fnvHash(s string) (int32, int32) {
 func (wc *Circom2WZWitnessCalculator) Calculate(inputs map[string]interface{},
 	sanityCheck bool) (wtns witness.Witness, err error) {
 
+	return wc.CalculateContext(context.Background(), inputs, sanityCheck)
+}
+
+// CalculateContext calculates the witness given the inputs. The calculation
+// is interrupted and ctx.Err() is returned when ctx is done.
+func (wc *Circom2WZWitnessCalculator) CalculateContext(ctx context.Context,
+	inputs map[string]interface{},
+	sanityCheck bool) (wtns witness.Witness, err error) {
+
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
 	wCtxState := &witnessCtxState{}
-	ctx := withWtnsCtx(context.Background(), wCtxState)
+	ctx = withWtnsCtx(ctx, wCtxState)
 
 	cfg := wz.NewModuleConfig()
 	var instance api.Module
@@ -2,7 +2,7 @@ package witness
 
 import (
 	"bytes"
-	"encoding/binary"
+	"context"
 	"errors"
 	"io"
 	"math/big"
@@ -23,6 +23,14 @@ type CalculatorImpl interface {
 		sanityCheck bool) (wtns Witness, err error)
 }
 
+// CalculatorImplContext is implemented by wasm engines that can stop the
+// calculation when the context is done.
+type CalculatorImplContext interface {
+	CalculatorImpl
+	CalculateContext(ctx context.Context, inputs map[string]interface{},
+		sanityCheck bool) (wtns Witness, err error)
+}
+
 type Calculator interface {
 	CalculateWitness(inputs map[string]interface{},
 		sanityCheck bool) ([]*big.Int, error)
@@ -33,6 +41,21 @@ type Calculator interface {
 	Close() error
 }
 
+// CalculatorContext is implemented by the calculators returned by
+// NewCalculator. Its methods are like the ones of Calculator but give up
+// when ctx is done. Engines that implement CalculatorImplContext stop the
+// calculation itself; with other engines the context is only checked before
+// and after it.
+type CalculatorContext interface {
+	Calculator
+	CalculateWitnessContext(ctx context.Context,
+		inputs map[string]interface{}, sanityCheck bool) ([]*big.Int, error)
+	CalculateBinWitnessContext(ctx context.Context,
+		inputs map[string]interface{}, sanityCheck bool) ([]byte, error)
+	CalculateWTNSBinContext(ctx context.Context,
+		inputs map[string]interface{}, sanityCheck bool) ([]byte, error)
+}
+
 type calcConfig struct {
 	wasmEngine func([]byte) (CalculatorImpl, error)
 }
@@ -41,10 +64,35 @@ type calc struct {
 	wc CalculatorImpl
 }
 
+func (c *calc) calculate(ctx context.Context, inputs map[string]interface{},
+	sanityCheck bool) (Witness, error) {
+
+	if err := ctx.Err(); err != nil {
+		return Witness{}, err
+	}
+
+	if wc, ok := c.wc.(CalculatorImplContext); ok {
+		return wc.CalculateContext(ctx, inputs, sanityCheck)
+	}
+
+	wtns, err := c.wc.Calculate(inputs, sanityCheck)
+	if err != nil {
+		return wtns, err
+	}
+	return wtns, ctx.Err()
+}
+
 func (c *calc) CalculateWitness(inputs map[string]interface{},
 	sanityCheck bool) ([]*big.Int, error) {
 
-	wtns, err := c.wc.Calculate(inputs, sanityCheck)
+	return c.CalculateWitnessContext(context.Background(), inputs,
+		sanityCheck)
+}
+
+func (c *calc) CalculateWitnessContext(ctx context.Context,
+	inputs map[string]interface{}, sanityCheck bool) ([]*big.Int, error) {
+
+	wtns, err := c.calculate(ctx, inputs, sanityCheck)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +102,14 @@ func (c *calc) CalculateWitness(inputs map[string]interface{},
 func (c *calc) CalculateBinWitness(inputs map[string]interface{},
 	sanityCheck bool) ([]byte, error) {
 
-	wtns, err := c.wc.Calculate(inputs, sanityCheck)
+	return c.CalculateBinWitnessContext(context.Background(), inputs,
+		sanityCheck)
+}
+
+func (c *calc) CalculateBinWitnessContext(ctx context.Context,
+	inputs map[string]interface{}, sanityCheck bool) ([]byte, error) {
+
+	wtns, err := c.calculate(ctx, inputs, sanityCheck)
 	if err != nil {
 		return nil, err
 	}
@@ -77,59 +132,22 @@ func (c *calc) CalculateBinWitness(inputs map[string]interface{},
 func (c *calc) CalculateWTNSBin(inputs map[string]interface{},
 	sanityCheck bool) ([]byte, error) {
 
-	wtns, err := c.wc.Calculate(inputs, sanityCheck)
-	if err != nil {
-		return nil, err
-	}
-
-	buff := new(bytes.Buffer)
-
-	n8 := wtns.N32 * 4
-	idSection2length := n8 * len(wtns.Witness)
-
-	totalLn := 4 + 4 + 4 + 4 + 8 + 4 + n8 + 4 + 4 + 8 + idSection2length
-	buff.Grow(totalLn)
-
-	// wtns
-	_, _ = buff.Write([]byte("wtns"))
-
-	//version 2
-	_ = binary.Write(buff, binary.LittleEndian, uint32(2))
-
-	//number of sections: 2
-	_ = binary.Write(buff, binary.LittleEndian, uint32(2))
-
-	//id section 1
-	_ = binary.Write(buff, binary.LittleEndian, uint32(1))
-
-	//id section 1 length in 64bytes
-	idSection1length := 8 + n8
-	_ = binary.Write(buff, binary.LittleEndian, uint64(idSection1length))
+	return c.CalculateWTNSBinContext(context.Background(), inputs,
+		sanityCheck)
+}
 
-	//this.n32
-	_ = binary.Write(buff, binary.LittleEndian, uint32(n8))
+func (c *calc) CalculateWTNSBinContext(ctx context.Context,
+	inputs map[string]interface{}, sanityCheck bool) ([]byte, error) {
 
-	err = writeInt(buff, wtns.Prime, n8)
+	wtns, err := c.calculate(ctx, inputs, sanityCheck)
 	if err != nil {
 		return nil, err
 	}
 
-	// witness size
-	_ = binary.Write(buff, binary.LittleEndian, uint32(len(wtns.Witness)))
-
-	//id section 2
-	_ = binary.Write(buff, binary.LittleEndian, uint32(2))
-
-	// section 2 length
-	_ = binary.Write(buff, binary.LittleEndian, uint64(idSection2length))
-
-	for _, i := range wtns.Witness {
-		err = writeInt(buff, i, n8)
-		if err != nil {
-			return nil, err
-		}
+	buff := new(bytes.Buffer)
+	if _, err = wtns.WriteTo(buff); err != nil {
+		return nil, err
 	}
-
 	return buff.Bytes(), nil
 }
 
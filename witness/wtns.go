@@ -0,0 +1,183 @@
+package witness
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+const (
+	wtnsMagic         = "wtns"
+	wtnsVersion       = 2
+	wtnsSectionHeader = 1
+	wtnsSectionData   = 2
+)
+
+// ParseWTNS decodes a witness in the wtns binary format, as written by
+// CalculateWTNSBin, snarkjs or the circom native witness generators.
+func ParseWTNS(data []byte) (Witness, error) {
+	return ReadWTNS(bytes.NewReader(data))
+}
+
+// ReadWTNS reads a witness in the wtns binary format from r. Both version 1
+// and version 2 files are supported, and sections may be in any order.
+func ReadWTNS(r io.Reader) (Witness, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return Witness{}, fmt.Errorf("invalid wtns file: %w", err)
+	}
+	if string(hdr[:4]) != wtnsMagic {
+		return Witness{}, errors.New("invalid wtns file format")
+	}
+	version := binary.LittleEndian.Uint32(hdr[4:8])
+	if version == 0 || version > wtnsVersion {
+		return Witness{}, fmt.Errorf("unsupported wtns file version: %v",
+			version)
+	}
+	nSections := binary.LittleEndian.Uint32(hdr[8:12])
+
+	sections := make(map[uint32][]byte, 2)
+	for i := uint32(0); i < nSections; i++ {
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return Witness{}, fmt.Errorf(
+				"invalid wtns file: truncated section header: %w", err)
+		}
+		id := binary.LittleEndian.Uint32(hdr[0:4])
+		size := binary.LittleEndian.Uint64(hdr[4:12])
+		if id != wtnsSectionHeader && id != wtnsSectionData {
+			return Witness{}, fmt.Errorf("unexpected wtns section id: %v", id)
+		}
+		if _, ok := sections[id]; ok {
+			return Witness{}, fmt.Errorf("duplicate wtns section: %v", id)
+		}
+
+		// do not trust the size for the allocation, the buffer grows with
+		// the data actually read
+		var sec bytes.Buffer
+		n, err := io.CopyN(&sec, r, int64(size))
+		if uint64(n) != size {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return Witness{}, fmt.Errorf(
+				"invalid wtns file: section %v is truncated: %w", id, err)
+		}
+		sections[id] = sec.Bytes()
+	}
+
+	return decodeWTNS(sections)
+}
+
+func decodeWTNS(sections map[uint32][]byte) (Witness, error) {
+	hdr, ok := sections[wtnsSectionHeader]
+	if !ok {
+		return Witness{}, errors.New("wtns header section is missing")
+	}
+	if len(hdr) < 4 {
+		return Witness{}, errors.New("invalid wtns header section size")
+	}
+	n8 := int(binary.LittleEndian.Uint32(hdr))
+	if n8 == 0 || n8%4 != 0 {
+		return Witness{}, fmt.Errorf("invalid wtns field element size: %v",
+			n8)
+	}
+	if len(hdr) != 4+n8+4 {
+		return Witness{}, errors.New("invalid wtns header section size")
+	}
+	prime := new(big.Int).SetBytes(utils.SwapEndianness(hdr[4 : 4+n8]))
+	if prime.Cmp(big.NewInt(1)) <= 0 {
+		return Witness{}, fmt.Errorf("invalid wtns prime: %v", prime)
+	}
+	nWitness := int(binary.LittleEndian.Uint32(hdr[4+n8:]))
+
+	data, ok := sections[wtnsSectionData]
+	if !ok {
+		return Witness{}, errors.New("wtns data section is missing")
+	}
+	if uint64(len(data)) != uint64(nWitness)*uint64(n8) {
+		return Witness{}, fmt.Errorf(
+			"invalid wtns data section size: %v, expected %v signals of %v bytes",
+			len(data), nWitness, n8)
+	}
+
+	wtns := Witness{
+		N32:     n8 / 4,
+		Prime:   prime,
+		Witness: make([]*big.Int, nWitness),
+	}
+	for i := range wtns.Witness {
+		v := new(big.Int).SetBytes(
+			utils.SwapEndianness(data[i*n8 : (i+1)*n8]))
+		if v.Cmp(prime) >= 0 {
+			return Witness{}, fmt.Errorf(
+				"wtns signal %v is not in the field", i)
+		}
+		wtns.Witness[i] = v
+	}
+	return wtns, nil
+}
+
+// WriteTo writes the witness to out in the wtns binary format, version 2.
+func (w Witness) WriteTo(out io.Writer) (int64, error) {
+	n8 := w.N32 * 4
+	if n8 <= 0 {
+		return 0, fmt.Errorf("invalid witness N32: %v", w.N32)
+	}
+	if w.Prime == nil || w.Prime.Sign() <= 0 ||
+		len(w.Prime.Bytes()) > n8 {
+		return 0, errors.New("invalid witness prime")
+	}
+	for i, v := range w.Witness {
+		if v == nil || v.Sign() < 0 || v.Cmp(w.Prime) >= 0 {
+			return 0, fmt.Errorf("witness signal %v is not in the field", i)
+		}
+	}
+
+	buff := new(bytes.Buffer)
+
+	idSection2length := n8 * len(w.Witness)
+
+	totalLn := 4 + 4 + 4 + 4 + 8 + 4 + n8 + 4 + 4 + 8 + idSection2length
+	buff.Grow(totalLn)
+
+	// wtns
+	_, _ = buff.Write([]byte(wtnsMagic))
+
+	//version 2
+	_ = binary.Write(buff, binary.LittleEndian, uint32(wtnsVersion))
+
+	//number of sections: 2
+	_ = binary.Write(buff, binary.LittleEndian, uint32(2))
+
+	//id section 1
+	_ = binary.Write(buff, binary.LittleEndian, uint32(wtnsSectionHeader))
+
+	//id section 1 length in 64bytes
+	idSection1length := 8 + n8
+	_ = binary.Write(buff, binary.LittleEndian, uint64(idSection1length))
+
+	//this.n32
+	_ = binary.Write(buff, binary.LittleEndian, uint32(n8))
+
+	_ = writeInt(buff, w.Prime, n8)
+
+	// witness size
+	_ = binary.Write(buff, binary.LittleEndian, uint32(len(w.Witness)))
+
+	//id section 2
+	_ = binary.Write(buff, binary.LittleEndian, uint32(wtnsSectionData))
+
+	// section 2 length
+	_ = binary.Write(buff, binary.LittleEndian, uint64(idSection2length))
+
+	for _, i := range w.Witness {
+		_ = writeInt(buff, i, n8)
+	}
+
+	return buff.WriteTo(out)
+}
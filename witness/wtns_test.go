@@ -0,0 +1,190 @@
+package witness
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var bn254Prime, _ = new(big.Int).SetString(
+	"21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// wtnsSection encodes a section of a wtns file.
+func wtnsSection(id uint32, data []byte) []byte {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, id)
+	_ = binary.Write(&b, binary.LittleEndian, uint64(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+// wtnsFile encodes a wtns file from its sections.
+func wtnsFile(version uint32, sections ...[]byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("wtns")
+	_ = binary.Write(&b, binary.LittleEndian, version)
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(sections)))
+	for _, s := range sections {
+		b.Write(s)
+	}
+	return b.Bytes()
+}
+
+func TestParseWTNS(t *testing.T) {
+	wtnsBytes, err := os.ReadFile("../prover/testdata/circuit.wtns")
+	require.NoError(t, err)
+
+	wtns, err := ParseWTNS(wtnsBytes)
+	require.NoError(t, err)
+	require.Equal(t, 8, wtns.N32)
+	require.Equal(t, bn254Prime, wtns.Prime)
+	require.Len(t, wtns.Witness, 6)
+	require.Equal(t, int64(1), wtns.Witness[0].Int64())
+
+	wtns2, err := ReadWTNS(bytes.NewReader(wtnsBytes))
+	require.NoError(t, err)
+	require.Equal(t, wtns, wtns2)
+
+	var b bytes.Buffer
+	n, err := wtns.WriteTo(&b)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(wtnsBytes)), n)
+	require.Equal(t, wtnsBytes, b.Bytes())
+
+	// the header is the first section: 12 bytes of the file header,
+	// 12 bytes of the section header, n8, prime and the signal count
+	hdr := wtnsBytes[12 : 12+12+4+32+4]
+	data := wtnsBytes[12+len(hdr):]
+
+	t.Run("unordered sections", func(t *testing.T) {
+		got, err := ParseWTNS(wtnsFile(2, data, hdr))
+		require.NoError(t, err)
+		require.Equal(t, wtns, got)
+	})
+
+	t.Run("version 1", func(t *testing.T) {
+		got, err := ParseWTNS(wtnsFile(1, hdr, data))
+		require.NoError(t, err)
+		require.Equal(t, wtns, got)
+	})
+}
+
+func TestParseWTNSErrors(t *testing.T) {
+	wtnsBytes, err := os.ReadFile("../prover/testdata/circuit.wtns")
+	require.NoError(t, err)
+	hdr := wtnsBytes[12 : 12+12+4+32+4]
+	data := wtnsBytes[12+len(hdr):]
+
+	// header section with the bn254 prime padded to n8 bytes
+	validHdr := func(n8 uint32, nWitness uint32) []byte {
+		var b bytes.Buffer
+		_ = binary.Write(&b, binary.LittleEndian, n8)
+		prime := make([]byte, n8)
+		copy(prime, hdr[16:48])
+		b.Write(prime)
+		_ = binary.Write(&b, binary.LittleEndian, nWitness)
+		return wtnsSection(wtnsSectionHeader, b.Bytes())
+	}
+	twoSignals := wtnsSection(wtnsSectionData, make([]byte, 64))
+	notInField := wtnsSection(wtnsSectionData,
+		append(make([]byte, 32), hdr[16:48]...))
+
+	testCases := []struct {
+		name string
+		wtns []byte
+		err  string
+	}{
+		{
+			name: "empty",
+			wtns: nil,
+			err:  "invalid wtns file: EOF",
+		},
+		{
+			name: "magic",
+			wtns: append([]byte("zkey"), wtnsBytes[4:]...),
+			err:  "invalid wtns file format",
+		},
+		{
+			name: "version",
+			wtns: wtnsFile(3, hdr, data),
+			err:  "unsupported wtns file version: 3",
+		},
+		{
+			name: "section id",
+			wtns: wtnsFile(2, hdr, data, wtnsSection(3, nil)),
+			err:  "unexpected wtns section id: 3",
+		},
+		{
+			name: "duplicate section",
+			wtns: wtnsFile(2, hdr, data, data),
+			err:  "duplicate wtns section: 2",
+		},
+		{
+			name: "missing header",
+			wtns: wtnsFile(2, data),
+			err:  "wtns header section is missing",
+		},
+		{
+			name: "missing data",
+			wtns: wtnsFile(2, hdr),
+			err:  "wtns data section is missing",
+		},
+		{
+			name: "truncated section",
+			wtns: wtnsFile(2, hdr, data[:len(data)-1]),
+			err: "invalid wtns file: section 2 is truncated: " +
+				"unexpected EOF",
+		},
+		{
+			name: "n8",
+			wtns: wtnsFile(2, validHdr(30, 2), twoSignals),
+			err:  "invalid wtns field element size: 30",
+		},
+		{
+			name: "data size",
+			wtns: wtnsFile(2, validHdr(32, 3), twoSignals),
+			err: "invalid wtns data section size: 64, " +
+				"expected 3 signals of 32 bytes",
+		},
+		{
+			name: "prime",
+			wtns: wtnsFile(2, wtnsSection(wtnsSectionHeader,
+				append([]byte{32, 0, 0, 0}, make([]byte, 36)...)),
+				twoSignals),
+			err: "invalid wtns prime: 0",
+		},
+		{
+			name: "signal not in field",
+			wtns: wtnsFile(2, validHdr(32, 2), notInField),
+			err:  "wtns signal 1 is not in the field",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWTNS(tc.wtns)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestWitnessWriteToErrors(t *testing.T) {
+	var b bytes.Buffer
+
+	_, err := Witness{Prime: bn254Prime}.WriteTo(&b)
+	require.EqualError(t, err, "invalid witness N32: 0")
+
+	_, err = Witness{N32: 8}.WriteTo(&b)
+	require.EqualError(t, err, "invalid witness prime")
+
+	_, err = Witness{
+		N32:     8,
+		Prime:   bn254Prime,
+		Witness: []*big.Int{big.NewInt(1), bn254Prime},
+	}.WriteTo(&b)
+	require.EqualError(t, err, "witness signal 1 is not in the field")
+	require.Zero(t, b.Len())
+}
@@ -0,0 +1,20 @@
+module github.com/iden3/go-rapidsnark/zkey
+
+go 1.18
+
+require (
+	github.com/iden3/go-rapidsnark/verifier v0.0.6
+	github.com/stretchr/testify v1.8.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/iden3/go-rapidsnark/types => ../types
+	github.com/iden3/go-rapidsnark/verifier => ../verifier
+)
@@ -0,0 +1,134 @@
+package zkey
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-rapidsnark/verifier/bn256"
+)
+
+// VerificationKey is a Groth16 verification key in the JSON format of
+// `snarkjs zkey export verificationkey`.
+type VerificationKey struct {
+	Protocol    string       `json:"protocol"`
+	Curve       string       `json:"curve"`
+	NPublic     int          `json:"nPublic"`
+	Alpha1      []string     `json:"vk_alpha_1"`
+	Beta2       [][]string   `json:"vk_beta_2"`
+	Gamma2      [][]string   `json:"vk_gamma_2"`
+	Delta2      [][]string   `json:"vk_delta_2"`
+	AlphaBeta12 [][][]string `json:"vk_alphabeta_12"`
+	IC          [][]string   `json:"IC"`
+}
+
+// ExportVerificationKey returns the verification key of the zkey as JSON,
+// formatted the same way as snarkjs does.
+func ExportVerificationKey(zkey []byte) ([]byte, error) {
+	z, err := Parse(zkey)
+	if err != nil {
+		return nil, err
+	}
+	return z.ExportVerificationKey()
+}
+
+// ExportVerificationKey returns the verification key of the zkey as JSON,
+// formatted the same way as snarkjs does.
+func (z *ZKey) ExportVerificationKey() ([]byte, error) {
+	vk, err := z.VerificationKey()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(vk, "", " ")
+}
+
+// VerificationKey returns the verification key of the zkey.
+func (z *ZKey) VerificationKey() (*VerificationKey, error) {
+	if z.Curve != CurveBN128 {
+		return nil, fmt.Errorf(
+			"verification key export is not supported for curve %v", z.Curve)
+	}
+
+	ic, err := z.IC()
+	if err != nil {
+		return nil, err
+	}
+	alphaBeta, err := alphaBeta12(z.Alpha1, z.Beta2)
+	if err != nil {
+		return nil, err
+	}
+
+	vk := &VerificationKey{
+		Protocol:    "groth16",
+		Curve:       z.Curve,
+		NPublic:     z.NPublic,
+		Alpha1:      g1ToStrings(z.Alpha1),
+		Beta2:       g2ToStrings(z.Beta2),
+		Gamma2:      g2ToStrings(z.Gamma2),
+		Delta2:      g2ToStrings(z.Delta2),
+		AlphaBeta12: alphaBeta,
+		IC:          make([][]string, len(ic)),
+	}
+	for i, p := range ic {
+		vk.IC[i] = g1ToStrings(p)
+	}
+	return vk, nil
+}
+
+// alphaBeta12 computes e(alpha1, beta2) as snarkjs does and returns it as
+// [c0, c1] with c0 and c1 in Fp6 = [[c0, c1], [c0, c1], [c0, c1]].
+func alphaBeta12(alpha1 G1, beta2 G2) ([][][]string, error) {
+	const n8 = 32
+
+	a := make([]byte, 2*n8)
+	alpha1.X.FillBytes(a[:n8])
+	alpha1.Y.FillBytes(a[n8:])
+	g1 := new(bn256.G1)
+	if _, err := g1.Unmarshal(a); err != nil {
+		return nil, fmt.Errorf("invalid vk_alpha_1: %w", err)
+	}
+
+	// bn256 expects the imaginary part first
+	b := make([]byte, 4*n8)
+	beta2.X[1].FillBytes(b[:n8])
+	beta2.X[0].FillBytes(b[n8 : 2*n8])
+	beta2.Y[1].FillBytes(b[2*n8 : 3*n8])
+	beta2.Y[0].FillBytes(b[3*n8:])
+	g2 := new(bn256.G2)
+	if _, err := g2.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("invalid vk_beta_2: %w", err)
+	}
+
+	// GT is marshaled as x·ω + y with x, y = a·τ² + b·τ + c and every
+	// Fp2 coefficient as its imaginary part followed by the real part.
+	m := bn256.ToSnarkjsGT(bn256.Pair(g1, g2)).Marshal()
+	coef := func(i int) string {
+		return new(big.Int).SetBytes(m[i*n8 : (i+1)*n8]).String()
+	}
+	fp6 := func(offset int) [][]string {
+		return [][]string{
+			{coef(offset + 5), coef(offset + 4)},
+			{coef(offset + 3), coef(offset + 2)},
+			{coef(offset + 1), coef(offset)},
+		}
+	}
+	return [][][]string{fp6(6), fp6(0)}, nil
+}
+
+func g1ToStrings(p G1) []string {
+	if p.IsInfinity() {
+		return []string{"0", "1", "0"}
+	}
+	return []string{p.X.String(), p.Y.String(), "1"}
+}
+
+func g2ToStrings(p G2) [][]string {
+	if p.IsInfinity() {
+		return [][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return [][]string{
+		{p.X[0].String(), p.X[1].String()},
+		{p.Y[0].String(), p.Y[1].String()},
+		{"1", "0"},
+	}
+}
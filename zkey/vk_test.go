@@ -0,0 +1,48 @@
+package zkey
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportVerificationKey(t *testing.T) {
+	data, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	// written by the generator of circuit.zkey, which computes
+	// vk_alphabeta_12 with gnark-crypto
+	want, err := os.ReadFile("../prover/testdata/verification_key.json")
+	require.NoError(t, err)
+
+	vkJSON, err := ExportVerificationKey(data)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(vkJSON))
+}
+
+// snarkjs_verification_key.json was exported by snarkjs, check that the
+// same vk_alphabeta_12 and the same formatting are produced.
+func TestVerificationKeySnarkjs(t *testing.T) {
+	want, err := os.ReadFile("testdata/snarkjs_verification_key.json")
+	require.NoError(t, err)
+	var vk VerificationKey
+	require.NoError(t, json.Unmarshal(want, &vk))
+
+	alpha1 := G1{X: bigFromString(vk.Alpha1[0]),
+		Y: bigFromString(vk.Alpha1[1])}
+	beta2 := G2{
+		X: [2]*big.Int{bigFromString(vk.Beta2[0][0]),
+			bigFromString(vk.Beta2[0][1])},
+		Y: [2]*big.Int{bigFromString(vk.Beta2[1][0]),
+			bigFromString(vk.Beta2[1][1])},
+	}
+	alphaBeta, err := alphaBeta12(alpha1, beta2)
+	require.NoError(t, err)
+	require.Equal(t, vk.AlphaBeta12, alphaBeta)
+
+	vkJSON, err := json.MarshalIndent(vk, "", " ")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(vkJSON))
+}
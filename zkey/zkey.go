@@ -0,0 +1,542 @@
+// Package zkey parses Groth16 proving keys stored in the snarkjs .zkey
+// binary format.
+//
+// Only the section table and the header sections are read when a zkey is
+// opened. Large sections, such as the proving key points, are left on disk
+// and can be accessed with ZKey.SectionReader.
+package zkey
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Section ids of a Groth16 zkey file.
+const (
+	SectionHeader        uint32 = 1
+	SectionGroth16Header uint32 = 2
+	SectionIC            uint32 = 3
+	SectionCoefs         uint32 = 4
+	SectionPointsA       uint32 = 5
+	SectionPointsB1      uint32 = 6
+	SectionPointsB2      uint32 = 7
+	SectionPointsC       uint32 = 8
+	SectionPointsH       uint32 = 9
+	SectionContributions uint32 = 10
+)
+
+// ProtocolGroth16 is the protocol id stored in the header of Groth16 zkeys.
+const ProtocolGroth16 = 1
+
+// Curve names as used by snarkjs.
+const (
+	CurveBN128    = "bn128"
+	CurveBLS12381 = "bls12381"
+)
+
+const (
+	magic      = "zkey"
+	maxVersion = 1
+	// the header sections are small, do not trust bigger sizes from the
+	// section table
+	maxHeaderSectionSize = 4096
+)
+
+type curve struct {
+	name string
+	q    *big.Int
+	r    *big.Int
+}
+
+var curves = []curve{
+	{
+		name: CurveBN128,
+		q:    bigFromString("21888242871839275222246405745257275088696311157297823662689037894645226208583"),
+		r:    bigFromString("21888242871839275222246405745257275088548364400416034343698204186575808495617"),
+	},
+	{
+		name: CurveBLS12381,
+		q:    bigFromString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559787"),
+		r:    bigFromString("52435875175126190479447740508185965837690552500527637822603658699938581184513"),
+	},
+}
+
+var errShortSection = errors.New("section is too short")
+
+// Section describes the location of a section in the zkey file.
+type Section struct {
+	ID uint32
+	// Offset is the position of the section data in the file, after the
+	// section header.
+	Offset int64
+	Size   int64
+}
+
+// G1 is an affine point of the G1 group. The point at infinity has both
+// coordinates equal to zero.
+type G1 struct {
+	X, Y *big.Int
+}
+
+// IsInfinity reports whether p is the point at infinity.
+func (p G1) IsInfinity() bool {
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+// G2 is an affine point of the G2 group. Coordinates are elements of the
+// quadratic extension field stored as [c0, c1]. The point at infinity has
+// all coordinates equal to zero.
+type G2 struct {
+	X, Y [2]*big.Int
+}
+
+// IsInfinity reports whether p is the point at infinity.
+func (p G2) IsInfinity() bool {
+	return p.X[0].Sign() == 0 && p.X[1].Sign() == 0 &&
+		p.Y[0].Sign() == 0 && p.Y[1].Sign() == 0
+}
+
+// ZKey is a parsed Groth16 zkey header.
+type ZKey struct {
+	Version  uint32
+	Sections []Section
+	Protocol uint32
+
+	// Curve is the snarkjs name of the curve, CurveBN128 or CurveBLS12381.
+	Curve string
+	// N8q is the size in bytes of a base field element.
+	N8q int
+	// Q is the base field prime.
+	Q *big.Int
+	// N8r is the size in bytes of a scalar field element.
+	N8r int
+	// R is the scalar field prime.
+	R *big.Int
+
+	NVars      int
+	NPublic    int
+	DomainSize int
+
+	Alpha1 G1
+	Beta1  G1
+	Beta2  G2
+	Gamma2 G2
+	Delta1 G1
+	Delta2 G2
+
+	r io.ReaderAt
+	// R^-1 mod q to decode coordinates in Montgomery form
+	qRInv *big.Int
+}
+
+// Parse parses the zkey stored in data.
+func Parse(data []byte) (*ZKey, error) {
+	return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+// Read parses the zkey from r, which has the given size in bytes. Only
+// the section table and the header sections are read. r is kept to read
+// other sections on demand, so it must stay open while the ZKey is in use.
+func Read(r io.ReaderAt, size int64) (*ZKey, error) {
+	z := &ZKey{r: r}
+	if err := z.readSectionTable(size); err != nil {
+		return nil, err
+	}
+
+	sec, err := z.readHeaderSection(SectionHeader)
+	if err != nil {
+		return nil, err
+	}
+	br := &binReader{buf: sec}
+	z.Protocol = br.uint32()
+	if br.err != nil {
+		return nil, fmt.Errorf("invalid zkey header: %w", br.err)
+	}
+	if z.Protocol != ProtocolGroth16 {
+		return nil, fmt.Errorf("zkey protocol is not groth16: %v",
+			z.Protocol)
+	}
+
+	if err = z.readGroth16Header(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (z *ZKey) readSectionTable(size int64) error {
+	var hdr [12]byte
+	if size < int64(len(hdr)) {
+		return errors.New("zkey file is too short")
+	}
+	if err := z.readAt(hdr[:], 0); err != nil {
+		return fmt.Errorf("failed to read zkey file: %w", err)
+	}
+	if string(hdr[:4]) != magic {
+		return errors.New("invalid zkey file format")
+	}
+	z.Version = binary.LittleEndian.Uint32(hdr[4:8])
+	if z.Version == 0 || z.Version > maxVersion {
+		return fmt.Errorf("unsupported zkey file version: %v", z.Version)
+	}
+	nSections := binary.LittleEndian.Uint32(hdr[8:12])
+
+	pos := int64(len(hdr))
+	for i := uint32(0); i < nSections; i++ {
+		if size-pos < int64(len(hdr)) {
+			return errors.New("invalid zkey file: truncated section header")
+		}
+		if err := z.readAt(hdr[:], pos); err != nil {
+			return fmt.Errorf("failed to read zkey file: %w", err)
+		}
+		id := binary.LittleEndian.Uint32(hdr[0:4])
+		secSize := binary.LittleEndian.Uint64(hdr[4:12])
+		pos += int64(len(hdr))
+		if uint64(size-pos) < secSize {
+			return fmt.Errorf("invalid zkey file: section %v is truncated",
+				id)
+		}
+		z.Sections = append(z.Sections,
+			Section{ID: id, Offset: pos, Size: int64(secSize)})
+		pos += int64(secSize)
+	}
+	return nil
+}
+
+// Section returns the location of the section with the given id. If the
+// file has several sections with the same id, the first one is returned.
+func (z *ZKey) Section(id uint32) (Section, bool) {
+	for _, s := range z.Sections {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// SectionReader returns a reader for the data of the section with the
+// given id.
+func (z *ZKey) SectionReader(id uint32) (*io.SectionReader, error) {
+	s, ok := z.Section(id)
+	if !ok {
+		return nil, fmt.Errorf("zkey section %v is missing", id)
+	}
+	return io.NewSectionReader(z.r, s.Offset, s.Size), nil
+}
+
+// IC returns the nPublic+1 points of the verification key used to combine
+// the public inputs.
+func (z *ZKey) IC() ([]G1, error) {
+	return z.PointsG1(SectionIC)
+}
+
+// PointsG1 decodes the G1 points of the section with the given id, one of
+// SectionIC, SectionPointsA, SectionPointsB1, SectionPointsC or
+// SectionPointsH. The whole section is read into memory.
+func (z *ZKey) PointsG1(id uint32) ([]G1, error) {
+	n, ok := z.numPoints(id)
+	if !ok || id == SectionPointsB2 {
+		return nil, fmt.Errorf("zkey section %v has no G1 points", id)
+	}
+	buf, err := z.readPointsSection(id, n*2*z.N8q)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &binReader{buf: buf}
+	points := make([]G1, n)
+	for i := range points {
+		if points[i], err = z.readG1(br); err != nil {
+			return nil, fmt.Errorf("zkey section %v: %w", id, err)
+		}
+	}
+	return points, nil
+}
+
+// PointsG2 decodes the G2 points of the section with the given id, which
+// must be SectionPointsB2. The whole section is read into memory.
+func (z *ZKey) PointsG2(id uint32) ([]G2, error) {
+	n, ok := z.numPoints(id)
+	if !ok || id != SectionPointsB2 {
+		return nil, fmt.Errorf("zkey section %v has no G2 points", id)
+	}
+	buf, err := z.readPointsSection(id, n*4*z.N8q)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &binReader{buf: buf}
+	points := make([]G2, n)
+	for i := range points {
+		if points[i], err = z.readG2(br); err != nil {
+			return nil, fmt.Errorf("zkey section %v: %w", id, err)
+		}
+	}
+	return points, nil
+}
+
+// Coef is a non-zero entry of the A or B constraint matrices.
+type Coef struct {
+	// Matrix is 0 for A and 1 for B.
+	Matrix     uint32
+	Constraint uint32
+	Signal     uint32
+	// Value is an element of the scalar field in normal form.
+	Value *big.Int
+}
+
+// Coefs decodes the entries of the A and B matrices stored in the
+// SectionCoefs section.
+func (z *ZKey) Coefs() ([]Coef, error) {
+	s, ok := z.Section(SectionCoefs)
+	if !ok {
+		return nil, fmt.Errorf("zkey section %v is missing", SectionCoefs)
+	}
+	buf, err := z.readSection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &binReader{buf: buf}
+	nCoefs := int(br.uint32())
+	if br.err == nil && nCoefs > len(br.buf)/(12+z.N8r) {
+		br.err = errShortSection
+	}
+	if br.err != nil {
+		return nil, fmt.Errorf("invalid zkey coefficients: %w", br.err)
+	}
+
+	// coefficients are stored multiplied by R² (double Montgomery form)
+	rR2Inv := new(big.Int).Lsh(big.NewInt(1), uint(z.N8r*8))
+	rR2Inv.ModInverse(rR2Inv, z.R)
+	rR2Inv.Mul(rR2Inv, rR2Inv)
+	rR2Inv.Mod(rR2Inv, z.R)
+
+	coefs := make([]Coef, nCoefs)
+	for i := range coefs {
+		c := &coefs[i]
+		c.Matrix = br.uint32()
+		c.Constraint = br.uint32()
+		c.Signal = br.uint32()
+		c.Value = readLEInt(br.next(z.N8r))
+		if c.Matrix > 1 || int(c.Constraint) >= z.DomainSize ||
+			int(c.Signal) >= z.NVars || c.Value.Cmp(z.R) >= 0 {
+			return nil, fmt.Errorf("invalid zkey coefficient #%v", i)
+		}
+		c.Value.Mul(c.Value, rR2Inv)
+		c.Value.Mod(c.Value, z.R)
+	}
+	return coefs, nil
+}
+
+// numPoints returns the number of points stored in the section with the
+// given id, if it is a section of points.
+func (z *ZKey) numPoints(id uint32) (int, bool) {
+	switch id {
+	case SectionIC:
+		return z.NPublic + 1, true
+	case SectionPointsA, SectionPointsB1, SectionPointsB2:
+		return z.NVars, true
+	case SectionPointsC:
+		return z.NVars - z.NPublic - 1, true
+	case SectionPointsH:
+		return z.DomainSize, true
+	default:
+		return 0, false
+	}
+}
+
+// readPointsSection reads the data of the section with the given id, which
+// must be size bytes long.
+func (z *ZKey) readPointsSection(id uint32, size int) ([]byte, error) {
+	s, ok := z.Section(id)
+	if !ok {
+		return nil, fmt.Errorf("zkey section %v is missing", id)
+	}
+	if s.Size != int64(size) {
+		return nil, fmt.Errorf("zkey section %v has unexpected size", id)
+	}
+	return z.readSection(s)
+}
+
+func (z *ZKey) readGroth16Header() error {
+	sec, err := z.readHeaderSection(SectionGroth16Header)
+	if err != nil {
+		return err
+	}
+	br := &binReader{buf: sec}
+
+	z.N8q = int(br.uint32())
+	z.Q = readLEInt(br.next(z.N8q))
+	z.N8r = int(br.uint32())
+	z.R = readLEInt(br.next(z.N8r))
+	if br.err != nil {
+		return fmt.Errorf("invalid zkey groth16 header: %w", br.err)
+	}
+	for _, c := range curves {
+		if c.q.Cmp(z.Q) == 0 && c.r.Cmp(z.R) == 0 {
+			z.Curve = c.name
+			break
+		}
+	}
+	if z.Curve == "" {
+		return errors.New("zkey curve is not supported")
+	}
+	// montgomery factor R = 2^(8*n8q)
+	z.qRInv = new(big.Int).Lsh(big.NewInt(1), uint(z.N8q*8))
+	z.qRInv.ModInverse(z.qRInv, z.Q)
+
+	z.NVars = int(br.uint32())
+	z.NPublic = int(br.uint32())
+	z.DomainSize = int(br.uint32())
+	if br.err != nil {
+		return fmt.Errorf("invalid zkey groth16 header: %w", br.err)
+	}
+	if z.NPublic >= z.NVars {
+		return errors.New("invalid zkey groth16 header: nPublic >= nVars")
+	}
+	if z.DomainSize == 0 || z.DomainSize&(z.DomainSize-1) != 0 {
+		return fmt.Errorf("invalid zkey domain size: %v", z.DomainSize)
+	}
+
+	if z.Alpha1, err = z.readG1(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_alpha_1: %w", err)
+	}
+	if z.Beta1, err = z.readG1(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_beta_1: %w", err)
+	}
+	if z.Beta2, err = z.readG2(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_beta_2: %w", err)
+	}
+	if z.Gamma2, err = z.readG2(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_gamma_2: %w", err)
+	}
+	if z.Delta1, err = z.readG1(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_delta_1: %w", err)
+	}
+	if z.Delta2, err = z.readG2(br); err != nil {
+		return fmt.Errorf("invalid zkey vk_delta_2: %w", err)
+	}
+	return nil
+}
+
+func (z *ZKey) readHeaderSection(id uint32) ([]byte, error) {
+	s, ok := z.Section(id)
+	if !ok {
+		return nil, fmt.Errorf("zkey section %v is missing", id)
+	}
+	if s.Size > maxHeaderSectionSize {
+		return nil, fmt.Errorf("zkey section %v is too large", id)
+	}
+	return z.readSection(s)
+}
+
+// readSection reads the data of the section s.
+func (z *ZKey) readSection(s Section) ([]byte, error) {
+	buf := make([]byte, s.Size)
+	if err := z.readAt(buf, s.Offset); err != nil {
+		return nil, fmt.Errorf("failed to read zkey section %v: %w", s.ID,
+			err)
+	}
+	return buf, nil
+}
+
+// readAt reads exactly len(buf) bytes at off. An io.EOF returned together
+// with all the bytes is not an error for io.ReaderAt.
+func (z *ZKey) readAt(buf []byte, off int64) error {
+	n, err := z.r.ReadAt(buf, off)
+	if n == len(buf) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readG1 decodes an affine G1 point stored as two little-endian coordinates
+// in Montgomery form.
+func (z *ZKey) readG1(br *binReader) (G1, error) {
+	x, err := z.readCoord(br)
+	if err != nil {
+		return G1{}, err
+	}
+	y, err := z.readCoord(br)
+	if err != nil {
+		return G1{}, err
+	}
+	return G1{X: x, Y: y}, nil
+}
+
+// readG2 decodes an affine G2 point stored as x.c0, x.c1, y.c0, y.c1
+// little-endian coordinates in Montgomery form.
+func (z *ZKey) readG2(br *binReader) (G2, error) {
+	var p G2
+	for _, c := range []**big.Int{&p.X[0], &p.X[1], &p.Y[0], &p.Y[1]} {
+		var err error
+		if *c, err = z.readCoord(br); err != nil {
+			return G2{}, err
+		}
+	}
+	return p, nil
+}
+
+// readCoord decodes a base field element in Montgomery form.
+func (z *ZKey) readCoord(br *binReader) (*big.Int, error) {
+	b := br.next(z.N8q)
+	if br.err != nil {
+		return nil, br.err
+	}
+	x := readLEInt(b)
+	if x.Cmp(z.Q) >= 0 {
+		return nil, errors.New("coordinate is not in the field")
+	}
+	x.Mul(x, z.qRInv)
+	return x.Mod(x, z.Q), nil
+}
+
+// binReader reads little-endian values from a section.
+type binReader struct {
+	buf []byte
+	err error
+}
+
+func (r *binReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errShortSection
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *binReader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func readLEInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func bigFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big.Int string: " + s)
+	}
+	return v
+}
@@ -0,0 +1,212 @@
+package zkey
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testVK struct {
+	NPublic int        `json:"nPublic"`
+	Curve   string     `json:"curve"`
+	Alpha1  []string   `json:"vk_alpha_1"`
+	Beta2   [][]string `json:"vk_beta_2"`
+	Gamma2  [][]string `json:"vk_gamma_2"`
+	Delta2  [][]string `json:"vk_delta_2"`
+	IC      [][]string `json:"IC"`
+}
+
+func readTestVK(t testing.TB) testVK {
+	vkJSON, err := os.ReadFile("../prover/testdata/verification_key.json")
+	require.NoError(t, err)
+	var vk testVK
+	require.NoError(t, json.Unmarshal(vkJSON, &vk))
+	return vk
+}
+
+func requireG1(t testing.TB, want []string, p G1) {
+	require.Equal(t, want[0], p.X.String())
+	require.Equal(t, want[1], p.Y.String())
+}
+
+func requireG2(t testing.TB, want [][]string, p G2) {
+	require.Equal(t, want[0][0], p.X[0].String())
+	require.Equal(t, want[0][1], p.X[1].String())
+	require.Equal(t, want[1][0], p.Y[0].String())
+	require.Equal(t, want[1][1], p.Y[1].String())
+}
+
+func TestParse(t *testing.T) {
+	data, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	vk := readTestVK(t)
+
+	z, err := Parse(data)
+	require.NoError(t, err)
+
+	require.Equal(t, uint32(1), z.Version)
+	require.Equal(t, uint32(ProtocolGroth16), z.Protocol)
+	require.Equal(t, CurveBN128, z.Curve)
+	require.Equal(t, vk.Curve, z.Curve)
+	require.Equal(t, 32, z.N8q)
+	require.Equal(t, 32, z.N8r)
+	require.Equal(t, "21888242871839275222246405745257275088696311157297823662689037894645226208583", z.Q.String())
+	require.Equal(t, "21888242871839275222246405745257275088548364400416034343698204186575808495617", z.R.String())
+	require.Equal(t, vk.NPublic, z.NPublic)
+	require.Equal(t, 6, z.NVars)
+	require.Equal(t, 8, z.DomainSize)
+
+	requireG1(t, vk.Alpha1, z.Alpha1)
+	requireG2(t, vk.Beta2, z.Beta2)
+	requireG2(t, vk.Gamma2, z.Gamma2)
+	requireG2(t, vk.Delta2, z.Delta2)
+	require.False(t, z.Beta1.IsInfinity())
+	require.False(t, z.Delta1.IsInfinity())
+
+	ic, err := z.IC()
+	require.NoError(t, err)
+	require.Len(t, ic, len(vk.IC))
+	for i := range ic {
+		requireG1(t, vk.IC[i], ic[i])
+	}
+
+	require.Len(t, z.Sections, 10)
+	for i, s := range z.Sections {
+		require.Equal(t, uint32(i+1), s.ID)
+		// every section starts after the header of the section
+		if i > 0 {
+			prev := z.Sections[i-1]
+			require.Equal(t, prev.Offset+prev.Size+12, s.Offset)
+		}
+	}
+	last := z.Sections[len(z.Sections)-1]
+	require.Equal(t, int64(len(data)), last.Offset+last.Size)
+
+	sr, err := z.SectionReader(SectionHeader)
+	require.NoError(t, err)
+	var protocol uint32
+	require.NoError(t, binary.Read(sr, binary.LittleEndian, &protocol))
+	require.Equal(t, uint32(ProtocolGroth16), protocol)
+
+	_, err = z.SectionReader(42)
+	require.EqualError(t, err, "zkey section 42 is missing")
+}
+
+func TestRead(t *testing.T) {
+	f, err := os.Open("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	defer f.Close()
+	st, err := f.Stat()
+	require.NoError(t, err)
+
+	z, err := Read(f, st.Size())
+	require.NoError(t, err)
+
+	data, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	want, err := Parse(data)
+	require.NoError(t, err)
+
+	require.Equal(t, want.Sections, z.Sections)
+	require.Equal(t, want.NVars, z.NVars)
+	require.Equal(t, want.Alpha1, z.Alpha1)
+	require.Equal(t, want.Delta2, z.Delta2)
+
+	ic, err := z.IC()
+	require.NoError(t, err)
+	wantIC, err := want.IC()
+	require.NoError(t, err)
+	require.Equal(t, wantIC, ic)
+}
+
+func TestParseErrors(t *testing.T) {
+	data, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+
+	_, err = Parse(nil)
+	require.EqualError(t, err, "zkey file is too short")
+
+	_, err = Parse([]byte("wtns\x02\x00\x00\x00\x00\x00\x00\x00"))
+	require.EqualError(t, err, "invalid zkey file format")
+
+	bad := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(bad[4:], 2)
+	_, err = Parse(bad)
+	require.EqualError(t, err, "unsupported zkey file version: 2")
+
+	_, err = Parse(data[:len(data)-1])
+	require.EqualError(t, err, "invalid zkey file: section 10 is truncated")
+
+	// the first section is the header, its data is the protocol id
+	bad = append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(bad[24:], 2)
+	_, err = Parse(bad)
+	require.EqualError(t, err, "zkey protocol is not groth16: 2")
+
+	// rename the groth16 header section
+	bad = append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(bad[28:], 42)
+	_, err = Parse(bad)
+	require.EqualError(t, err, "zkey section 2 is missing")
+
+	// corrupt the base field prime
+	bad = append([]byte{}, data...)
+	bad[44] ^= 1
+	_, err = Parse(bad)
+	require.EqualError(t, err, "zkey curve is not supported")
+}
+
+func TestPoints(t *testing.T) {
+	data, err := os.ReadFile("../prover/testdata/circuit.zkey")
+	require.NoError(t, err)
+	z, err := Parse(data)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		id uint32
+		n  int
+	}{
+		{SectionIC, z.NPublic + 1},
+		{SectionPointsA, z.NVars},
+		{SectionPointsB1, z.NVars},
+		{SectionPointsC, z.NVars - z.NPublic - 1},
+		{SectionPointsH, z.DomainSize},
+	} {
+		points, err := z.PointsG1(tc.id)
+		require.NoError(t, err)
+		require.Len(t, points, tc.n)
+	}
+	b2, err := z.PointsG2(SectionPointsB2)
+	require.NoError(t, err)
+	require.Len(t, b2, z.NVars)
+
+	_, err = z.PointsG1(SectionPointsB2)
+	require.EqualError(t, err, "zkey section 7 has no G1 points")
+	_, err = z.PointsG2(SectionPointsA)
+	require.EqualError(t, err, "zkey section 5 has no G2 points")
+	_, err = z.PointsG1(SectionCoefs)
+	require.EqualError(t, err, "zkey section 4 has no G1 points")
+
+	coefs, err := z.Coefs()
+	require.NoError(t, err)
+	require.NotEmpty(t, coefs)
+	for _, c := range coefs {
+		require.LessOrEqual(t, c.Matrix, uint32(1))
+		require.Less(t, int(c.Constraint), z.DomainSize)
+		require.Less(t, int(c.Signal), z.NVars)
+		require.Equal(t, -1, c.Value.Cmp(z.R))
+	}
+
+	// the first coefficient follows the number of coefficients
+	s, ok := z.Section(SectionCoefs)
+	require.True(t, ok)
+	bad := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(bad[s.Offset+4:], 2)
+	z, err = Parse(bad)
+	require.NoError(t, err)
+	_, err = z.Coefs()
+	require.EqualError(t, err, "invalid zkey coefficient #0")
+}